@@ -4,7 +4,7 @@
 // - protoc             v5.27.1
 // source: auth.proto
 
-//protoc -I protos/proto protos/proto/auth/auth.proto --go_out=./protos/gen/auth --go_opt=paths=source_relative --go-grpc_out=./protos/gen/auth/ --go-grpc_opt=paths=source_relative
+//protoc -I api/protos/proto api/protos/proto/auth.proto --go_out=./api/protos/gen/auth --go_opt=paths=source_relative --go-grpc_out=./api/protos/gen/auth/ --go-grpc_opt=paths=source_relative
 
 package authv1
 
@@ -21,9 +21,41 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	Auth_Register_FullMethodName  = "/users.Auth/Register"
-	Auth_Login_FullMethodName     = "/users.Auth/Login"
-	Auth_CheckAuth_FullMethodName = "/users.Auth/CheckAuth"
+	Auth_Register_FullMethodName               = "/users.Auth/Register"
+	Auth_Login_FullMethodName                  = "/users.Auth/Login"
+	Auth_CheckAuth_FullMethodName              = "/users.Auth/CheckAuth"
+	Auth_RefreshToken_FullMethodName           = "/users.Auth/RefreshToken"
+	Auth_Logout_FullMethodName                 = "/users.Auth/Logout"
+	Auth_RevokeToken_FullMethodName            = "/users.Auth/RevokeToken"
+	Auth_RevokeUserTokens_FullMethodName       = "/users.Auth/RevokeUserTokens"
+	Auth_UnlockAccount_FullMethodName          = "/users.Auth/UnlockAccount"
+	Auth_GrantRole_FullMethodName              = "/users.Auth/GrantRole"
+	Auth_RevokeRole_FullMethodName             = "/users.Auth/RevokeRole"
+	Auth_GetJWKS_FullMethodName                = "/users.Auth/GetJWKS"
+	Auth_RequestPasswordReset_FullMethodName   = "/users.Auth/RequestPasswordReset"
+	Auth_ConfirmPasswordReset_FullMethodName   = "/users.Auth/ConfirmPasswordReset"
+	Auth_ChangePassword_FullMethodName         = "/users.Auth/ChangePassword"
+	Auth_SendVerificationEmail_FullMethodName  = "/users.Auth/SendVerificationEmail"
+	Auth_VerifyEmail_FullMethodName            = "/users.Auth/VerifyEmail"
+	Auth_RequestEmailChange_FullMethodName     = "/users.Auth/RequestEmailChange"
+	Auth_ConfirmEmailChange_FullMethodName     = "/users.Auth/ConfirmEmailChange"
+	Auth_EnrollTotp_FullMethodName             = "/users.Auth/EnrollTotp"
+	Auth_ConfirmTotp_FullMethodName            = "/users.Auth/ConfirmTotp"
+	Auth_DisableTotp_FullMethodName            = "/users.Auth/DisableTotp"
+	Auth_VerifyMfa_FullMethodName              = "/users.Auth/VerifyMfa"
+	Auth_RequestMagicLink_FullMethodName       = "/users.Auth/RequestMagicLink"
+	Auth_RedeemMagicLink_FullMethodName        = "/users.Auth/RedeemMagicLink"
+	Auth_ExchangeExternalToken_FullMethodName  = "/users.Auth/ExchangeExternalToken"
+	Auth_LinkIdentity_FullMethodName           = "/users.Auth/LinkIdentity"
+	Auth_UnlinkIdentity_FullMethodName         = "/users.Auth/UnlinkIdentity"
+	Auth_Impersonate_FullMethodName            = "/users.Auth/Impersonate"
+	Auth_StopImpersonation_FullMethodName      = "/users.Auth/StopImpersonation"
+	Auth_ListSessions_FullMethodName           = "/users.Auth/ListSessions"
+	Auth_RevokeSession_FullMethodName          = "/users.Auth/RevokeSession"
+	Auth_RevokeAllOtherSessions_FullMethodName = "/users.Auth/RevokeAllOtherSessions"
+	Auth_CreateApiKey_FullMethodName           = "/users.Auth/CreateApiKey"
+	Auth_ListApiKeys_FullMethodName            = "/users.Auth/ListApiKeys"
+	Auth_RevokeApiKey_FullMethodName           = "/users.Auth/RevokeApiKey"
 )
 
 // AuthClient is the client API for Auth service.
@@ -33,6 +65,38 @@ type AuthClient interface {
 	Register(ctx context.Context, in *RegisterDTO, opts ...grpc.CallOption) (*RegisterRTO, error)
 	Login(ctx context.Context, in *LoginDTO, opts ...grpc.CallOption) (*LoginRTO, error)
 	CheckAuth(ctx context.Context, in *CheckAuthDTO, opts ...grpc.CallOption) (*CheckAuthRTO, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenDTO, opts ...grpc.CallOption) (*RefreshTokenRTO, error)
+	Logout(ctx context.Context, in *LogoutDTO, opts ...grpc.CallOption) (*LogoutRTO, error)
+	RevokeToken(ctx context.Context, in *RevokeTokenDTO, opts ...grpc.CallOption) (*RevokeTokenRTO, error)
+	RevokeUserTokens(ctx context.Context, in *RevokeUserTokensDTO, opts ...grpc.CallOption) (*RevokeUserTokensRTO, error)
+	UnlockAccount(ctx context.Context, in *UnlockAccountDTO, opts ...grpc.CallOption) (*UnlockAccountRTO, error)
+	GrantRole(ctx context.Context, in *GrantRoleDTO, opts ...grpc.CallOption) (*GrantRoleRTO, error)
+	RevokeRole(ctx context.Context, in *RevokeRoleDTO, opts ...grpc.CallOption) (*RevokeRoleRTO, error)
+	GetJWKS(ctx context.Context, in *GetJWKSDTO, opts ...grpc.CallOption) (*GetJWKSRTO, error)
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetDTO, opts ...grpc.CallOption) (*RequestPasswordResetRTO, error)
+	ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetDTO, opts ...grpc.CallOption) (*ConfirmPasswordResetRTO, error)
+	ChangePassword(ctx context.Context, in *ChangePasswordDTO, opts ...grpc.CallOption) (*ChangePasswordRTO, error)
+	SendVerificationEmail(ctx context.Context, in *SendVerificationEmailDTO, opts ...grpc.CallOption) (*SendVerificationEmailRTO, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailDTO, opts ...grpc.CallOption) (*VerifyEmailRTO, error)
+	RequestEmailChange(ctx context.Context, in *RequestEmailChangeDTO, opts ...grpc.CallOption) (*RequestEmailChangeRTO, error)
+	ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeDTO, opts ...grpc.CallOption) (*ConfirmEmailChangeRTO, error)
+	EnrollTotp(ctx context.Context, in *EnrollTotpDTO, opts ...grpc.CallOption) (*EnrollTotpRTO, error)
+	ConfirmTotp(ctx context.Context, in *ConfirmTotpDTO, opts ...grpc.CallOption) (*ConfirmTotpRTO, error)
+	DisableTotp(ctx context.Context, in *DisableTotpDTO, opts ...grpc.CallOption) (*DisableTotpRTO, error)
+	VerifyMfa(ctx context.Context, in *VerifyMfaDTO, opts ...grpc.CallOption) (*VerifyMfaRTO, error)
+	RequestMagicLink(ctx context.Context, in *RequestMagicLinkDTO, opts ...grpc.CallOption) (*RequestMagicLinkRTO, error)
+	RedeemMagicLink(ctx context.Context, in *RedeemMagicLinkDTO, opts ...grpc.CallOption) (*RedeemMagicLinkRTO, error)
+	ExchangeExternalToken(ctx context.Context, in *ExchangeExternalTokenDTO, opts ...grpc.CallOption) (*ExchangeExternalTokenRTO, error)
+	LinkIdentity(ctx context.Context, in *LinkIdentityDTO, opts ...grpc.CallOption) (*LinkIdentityRTO, error)
+	UnlinkIdentity(ctx context.Context, in *UnlinkIdentityDTO, opts ...grpc.CallOption) (*UnlinkIdentityRTO, error)
+	Impersonate(ctx context.Context, in *ImpersonateDTO, opts ...grpc.CallOption) (*ImpersonateRTO, error)
+	StopImpersonation(ctx context.Context, in *StopImpersonationDTO, opts ...grpc.CallOption) (*StopImpersonationRTO, error)
+	ListSessions(ctx context.Context, in *ListSessionsDTO, opts ...grpc.CallOption) (*ListSessionsRTO, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionDTO, opts ...grpc.CallOption) (*RevokeSessionRTO, error)
+	RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsDTO, opts ...grpc.CallOption) (*RevokeAllOtherSessionsRTO, error)
+	CreateApiKey(ctx context.Context, in *CreateApiKeyDTO, opts ...grpc.CallOption) (*CreateApiKeyRTO, error)
+	ListApiKeys(ctx context.Context, in *ListApiKeysDTO, opts ...grpc.CallOption) (*ListApiKeysRTO, error)
+	RevokeApiKey(ctx context.Context, in *RevokeApiKeyDTO, opts ...grpc.CallOption) (*RevokeApiKeyRTO, error)
 }
 
 type authClient struct {
@@ -73,6 +137,326 @@ func (c *authClient) CheckAuth(ctx context.Context, in *CheckAuthDTO, opts ...gr
 	return out, nil
 }
 
+func (c *authClient) RefreshToken(ctx context.Context, in *RefreshTokenDTO, opts ...grpc.CallOption) (*RefreshTokenRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenRTO)
+	err := c.cc.Invoke(ctx, Auth_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Logout(ctx context.Context, in *LogoutDTO, opts ...grpc.CallOption) (*LogoutRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutRTO)
+	err := c.cc.Invoke(ctx, Auth_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeToken(ctx context.Context, in *RevokeTokenDTO, opts ...grpc.CallOption) (*RevokeTokenRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeTokenRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeUserTokens(ctx context.Context, in *RevokeUserTokensDTO, opts ...grpc.CallOption) (*RevokeUserTokensRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeUserTokensRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeUserTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) UnlockAccount(ctx context.Context, in *UnlockAccountDTO, opts ...grpc.CallOption) (*UnlockAccountRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockAccountRTO)
+	err := c.cc.Invoke(ctx, Auth_UnlockAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GrantRole(ctx context.Context, in *GrantRoleDTO, opts ...grpc.CallOption) (*GrantRoleRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantRoleRTO)
+	err := c.cc.Invoke(ctx, Auth_GrantRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeRole(ctx context.Context, in *RevokeRoleDTO, opts ...grpc.CallOption) (*RevokeRoleRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeRoleRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetJWKS(ctx context.Context, in *GetJWKSDTO, opts ...grpc.CallOption) (*GetJWKSRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJWKSRTO)
+	err := c.cc.Invoke(ctx, Auth_GetJWKS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetDTO, opts ...grpc.CallOption) (*RequestPasswordResetRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPasswordResetRTO)
+	err := c.cc.Invoke(ctx, Auth_RequestPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetDTO, opts ...grpc.CallOption) (*ConfirmPasswordResetRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPasswordResetRTO)
+	err := c.cc.Invoke(ctx, Auth_ConfirmPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ChangePassword(ctx context.Context, in *ChangePasswordDTO, opts ...grpc.CallOption) (*ChangePasswordRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePasswordRTO)
+	err := c.cc.Invoke(ctx, Auth_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SendVerificationEmail(ctx context.Context, in *SendVerificationEmailDTO, opts ...grpc.CallOption) (*SendVerificationEmailRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendVerificationEmailRTO)
+	err := c.cc.Invoke(ctx, Auth_SendVerificationEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) VerifyEmail(ctx context.Context, in *VerifyEmailDTO, opts ...grpc.CallOption) (*VerifyEmailRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyEmailRTO)
+	err := c.cc.Invoke(ctx, Auth_VerifyEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestEmailChange(ctx context.Context, in *RequestEmailChangeDTO, opts ...grpc.CallOption) (*RequestEmailChangeRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestEmailChangeRTO)
+	err := c.cc.Invoke(ctx, Auth_RequestEmailChange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeDTO, opts ...grpc.CallOption) (*ConfirmEmailChangeRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmEmailChangeRTO)
+	err := c.cc.Invoke(ctx, Auth_ConfirmEmailChange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) EnrollTotp(ctx context.Context, in *EnrollTotpDTO, opts ...grpc.CallOption) (*EnrollTotpRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollTotpRTO)
+	err := c.cc.Invoke(ctx, Auth_EnrollTotp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmTotp(ctx context.Context, in *ConfirmTotpDTO, opts ...grpc.CallOption) (*ConfirmTotpRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTotpRTO)
+	err := c.cc.Invoke(ctx, Auth_ConfirmTotp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DisableTotp(ctx context.Context, in *DisableTotpDTO, opts ...grpc.CallOption) (*DisableTotpRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableTotpRTO)
+	err := c.cc.Invoke(ctx, Auth_DisableTotp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) VerifyMfa(ctx context.Context, in *VerifyMfaDTO, opts ...grpc.CallOption) (*VerifyMfaRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyMfaRTO)
+	err := c.cc.Invoke(ctx, Auth_VerifyMfa_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestMagicLink(ctx context.Context, in *RequestMagicLinkDTO, opts ...grpc.CallOption) (*RequestMagicLinkRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestMagicLinkRTO)
+	err := c.cc.Invoke(ctx, Auth_RequestMagicLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RedeemMagicLink(ctx context.Context, in *RedeemMagicLinkDTO, opts ...grpc.CallOption) (*RedeemMagicLinkRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedeemMagicLinkRTO)
+	err := c.cc.Invoke(ctx, Auth_RedeemMagicLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ExchangeExternalToken(ctx context.Context, in *ExchangeExternalTokenDTO, opts ...grpc.CallOption) (*ExchangeExternalTokenRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeExternalTokenRTO)
+	err := c.cc.Invoke(ctx, Auth_ExchangeExternalToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) LinkIdentity(ctx context.Context, in *LinkIdentityDTO, opts ...grpc.CallOption) (*LinkIdentityRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkIdentityRTO)
+	err := c.cc.Invoke(ctx, Auth_LinkIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) UnlinkIdentity(ctx context.Context, in *UnlinkIdentityDTO, opts ...grpc.CallOption) (*UnlinkIdentityRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlinkIdentityRTO)
+	err := c.cc.Invoke(ctx, Auth_UnlinkIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Impersonate(ctx context.Context, in *ImpersonateDTO, opts ...grpc.CallOption) (*ImpersonateRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImpersonateRTO)
+	err := c.cc.Invoke(ctx, Auth_Impersonate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) StopImpersonation(ctx context.Context, in *StopImpersonationDTO, opts ...grpc.CallOption) (*StopImpersonationRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopImpersonationRTO)
+	err := c.cc.Invoke(ctx, Auth_StopImpersonation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListSessions(ctx context.Context, in *ListSessionsDTO, opts ...grpc.CallOption) (*ListSessionsRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsRTO)
+	err := c.cc.Invoke(ctx, Auth_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeSession(ctx context.Context, in *RevokeSessionDTO, opts ...grpc.CallOption) (*RevokeSessionRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsDTO, opts ...grpc.CallOption) (*RevokeAllOtherSessionsRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAllOtherSessionsRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeAllOtherSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateApiKey(ctx context.Context, in *CreateApiKeyDTO, opts ...grpc.CallOption) (*CreateApiKeyRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateApiKeyRTO)
+	err := c.cc.Invoke(ctx, Auth_CreateApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListApiKeys(ctx context.Context, in *ListApiKeysDTO, opts ...grpc.CallOption) (*ListApiKeysRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListApiKeysRTO)
+	err := c.cc.Invoke(ctx, Auth_ListApiKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeApiKey(ctx context.Context, in *RevokeApiKeyDTO, opts ...grpc.CallOption) (*RevokeApiKeyRTO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeApiKeyRTO)
+	err := c.cc.Invoke(ctx, Auth_RevokeApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServer is the server API for Auth service.
 // All implementations must embed UnimplementedAuthServer
 // for forward compatibility
@@ -80,6 +464,38 @@ type AuthServer interface {
 	Register(context.Context, *RegisterDTO) (*RegisterRTO, error)
 	Login(context.Context, *LoginDTO) (*LoginRTO, error)
 	CheckAuth(context.Context, *CheckAuthDTO) (*CheckAuthRTO, error)
+	RefreshToken(context.Context, *RefreshTokenDTO) (*RefreshTokenRTO, error)
+	Logout(context.Context, *LogoutDTO) (*LogoutRTO, error)
+	RevokeToken(context.Context, *RevokeTokenDTO) (*RevokeTokenRTO, error)
+	RevokeUserTokens(context.Context, *RevokeUserTokensDTO) (*RevokeUserTokensRTO, error)
+	UnlockAccount(context.Context, *UnlockAccountDTO) (*UnlockAccountRTO, error)
+	GrantRole(context.Context, *GrantRoleDTO) (*GrantRoleRTO, error)
+	RevokeRole(context.Context, *RevokeRoleDTO) (*RevokeRoleRTO, error)
+	GetJWKS(context.Context, *GetJWKSDTO) (*GetJWKSRTO, error)
+	RequestPasswordReset(context.Context, *RequestPasswordResetDTO) (*RequestPasswordResetRTO, error)
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetDTO) (*ConfirmPasswordResetRTO, error)
+	ChangePassword(context.Context, *ChangePasswordDTO) (*ChangePasswordRTO, error)
+	SendVerificationEmail(context.Context, *SendVerificationEmailDTO) (*SendVerificationEmailRTO, error)
+	VerifyEmail(context.Context, *VerifyEmailDTO) (*VerifyEmailRTO, error)
+	RequestEmailChange(context.Context, *RequestEmailChangeDTO) (*RequestEmailChangeRTO, error)
+	ConfirmEmailChange(context.Context, *ConfirmEmailChangeDTO) (*ConfirmEmailChangeRTO, error)
+	EnrollTotp(context.Context, *EnrollTotpDTO) (*EnrollTotpRTO, error)
+	ConfirmTotp(context.Context, *ConfirmTotpDTO) (*ConfirmTotpRTO, error)
+	DisableTotp(context.Context, *DisableTotpDTO) (*DisableTotpRTO, error)
+	VerifyMfa(context.Context, *VerifyMfaDTO) (*VerifyMfaRTO, error)
+	RequestMagicLink(context.Context, *RequestMagicLinkDTO) (*RequestMagicLinkRTO, error)
+	RedeemMagicLink(context.Context, *RedeemMagicLinkDTO) (*RedeemMagicLinkRTO, error)
+	ExchangeExternalToken(context.Context, *ExchangeExternalTokenDTO) (*ExchangeExternalTokenRTO, error)
+	LinkIdentity(context.Context, *LinkIdentityDTO) (*LinkIdentityRTO, error)
+	UnlinkIdentity(context.Context, *UnlinkIdentityDTO) (*UnlinkIdentityRTO, error)
+	Impersonate(context.Context, *ImpersonateDTO) (*ImpersonateRTO, error)
+	StopImpersonation(context.Context, *StopImpersonationDTO) (*StopImpersonationRTO, error)
+	ListSessions(context.Context, *ListSessionsDTO) (*ListSessionsRTO, error)
+	RevokeSession(context.Context, *RevokeSessionDTO) (*RevokeSessionRTO, error)
+	RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsDTO) (*RevokeAllOtherSessionsRTO, error)
+	CreateApiKey(context.Context, *CreateApiKeyDTO) (*CreateApiKeyRTO, error)
+	ListApiKeys(context.Context, *ListApiKeysDTO) (*ListApiKeysRTO, error)
+	RevokeApiKey(context.Context, *RevokeApiKeyDTO) (*RevokeApiKeyRTO, error)
 	mustEmbedUnimplementedAuthServer()
 }
 
@@ -96,6 +512,102 @@ func (UnimplementedAuthServer) Login(context.Context, *LoginDTO) (*LoginRTO, err
 func (UnimplementedAuthServer) CheckAuth(context.Context, *CheckAuthDTO) (*CheckAuthRTO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckAuth not implemented")
 }
+func (UnimplementedAuthServer) RefreshToken(context.Context, *RefreshTokenDTO) (*RefreshTokenRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedAuthServer) Logout(context.Context, *LogoutDTO) (*LogoutRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedAuthServer) RevokeToken(context.Context, *RevokeTokenDTO) (*RevokeTokenRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (UnimplementedAuthServer) RevokeUserTokens(context.Context, *RevokeUserTokensDTO) (*RevokeUserTokensRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserTokens not implemented")
+}
+func (UnimplementedAuthServer) UnlockAccount(context.Context, *UnlockAccountDTO) (*UnlockAccountRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockAccount not implemented")
+}
+func (UnimplementedAuthServer) GrantRole(context.Context, *GrantRoleDTO) (*GrantRoleRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantRole not implemented")
+}
+func (UnimplementedAuthServer) RevokeRole(context.Context, *RevokeRoleDTO) (*RevokeRoleRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
+func (UnimplementedAuthServer) GetJWKS(context.Context, *GetJWKSDTO) (*GetJWKSRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJWKS not implemented")
+}
+func (UnimplementedAuthServer) RequestPasswordReset(context.Context, *RequestPasswordResetDTO) (*RequestPasswordResetRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedAuthServer) ConfirmPasswordReset(context.Context, *ConfirmPasswordResetDTO) (*ConfirmPasswordResetRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPasswordReset not implemented")
+}
+func (UnimplementedAuthServer) ChangePassword(context.Context, *ChangePasswordDTO) (*ChangePasswordRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedAuthServer) SendVerificationEmail(context.Context, *SendVerificationEmailDTO) (*SendVerificationEmailRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendVerificationEmail not implemented")
+}
+func (UnimplementedAuthServer) VerifyEmail(context.Context, *VerifyEmailDTO) (*VerifyEmailRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedAuthServer) RequestEmailChange(context.Context, *RequestEmailChangeDTO) (*RequestEmailChangeRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmailChange not implemented")
+}
+func (UnimplementedAuthServer) ConfirmEmailChange(context.Context, *ConfirmEmailChangeDTO) (*ConfirmEmailChangeRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmEmailChange not implemented")
+}
+func (UnimplementedAuthServer) EnrollTotp(context.Context, *EnrollTotpDTO) (*EnrollTotpRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTotp not implemented")
+}
+func (UnimplementedAuthServer) ConfirmTotp(context.Context, *ConfirmTotpDTO) (*ConfirmTotpRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTotp not implemented")
+}
+func (UnimplementedAuthServer) DisableTotp(context.Context, *DisableTotpDTO) (*DisableTotpRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTotp not implemented")
+}
+func (UnimplementedAuthServer) VerifyMfa(context.Context, *VerifyMfaDTO) (*VerifyMfaRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMfa not implemented")
+}
+func (UnimplementedAuthServer) RequestMagicLink(context.Context, *RequestMagicLinkDTO) (*RequestMagicLinkRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestMagicLink not implemented")
+}
+func (UnimplementedAuthServer) RedeemMagicLink(context.Context, *RedeemMagicLinkDTO) (*RedeemMagicLinkRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeemMagicLink not implemented")
+}
+func (UnimplementedAuthServer) ExchangeExternalToken(context.Context, *ExchangeExternalTokenDTO) (*ExchangeExternalTokenRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExchangeExternalToken not implemented")
+}
+func (UnimplementedAuthServer) LinkIdentity(context.Context, *LinkIdentityDTO) (*LinkIdentityRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkIdentity not implemented")
+}
+func (UnimplementedAuthServer) UnlinkIdentity(context.Context, *UnlinkIdentityDTO) (*UnlinkIdentityRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlinkIdentity not implemented")
+}
+func (UnimplementedAuthServer) Impersonate(context.Context, *ImpersonateDTO) (*ImpersonateRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Impersonate not implemented")
+}
+func (UnimplementedAuthServer) StopImpersonation(context.Context, *StopImpersonationDTO) (*StopImpersonationRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopImpersonation not implemented")
+}
+func (UnimplementedAuthServer) ListSessions(context.Context, *ListSessionsDTO) (*ListSessionsRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedAuthServer) RevokeSession(context.Context, *RevokeSessionDTO) (*RevokeSessionRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedAuthServer) RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsDTO) (*RevokeAllOtherSessionsRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllOtherSessions not implemented")
+}
+func (UnimplementedAuthServer) CreateApiKey(context.Context, *CreateApiKeyDTO) (*CreateApiKeyRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (UnimplementedAuthServer) ListApiKeys(context.Context, *ListApiKeysDTO) (*ListApiKeysRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (UnimplementedAuthServer) RevokeApiKey(context.Context, *RevokeApiKeyDTO) (*RevokeApiKeyRTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeApiKey not implemented")
+}
 func (UnimplementedAuthServer) mustEmbedUnimplementedAuthServer() {}
 
 // UnsafeAuthServer may be embedded to opt out of forward compatibility for this service.
@@ -163,6 +675,582 @@ func _Auth_CheckAuth_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RefreshToken(ctx, req.(*RefreshTokenDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Logout(ctx, req.(*LogoutDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeToken(ctx, req.(*RevokeTokenDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeUserTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserTokensDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeUserTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeUserTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeUserTokens(ctx, req.(*RevokeUserTokensDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_UnlockAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockAccountDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).UnlockAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_UnlockAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).UnlockAccount(ctx, req.(*UnlockAccountDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GrantRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRoleDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GrantRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GrantRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GrantRole(ctx, req.(*GrantRoleDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRoleDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeRole(ctx, req.(*RevokeRoleDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetJWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJWKSDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetJWKS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetJWKS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetJWKS(ctx, req.(*GetJWKSDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequestPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ConfirmPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPasswordResetDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ConfirmPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ConfirmPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ChangePassword(ctx, req.(*ChangePasswordDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_SendVerificationEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendVerificationEmailDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SendVerificationEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SendVerificationEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SendVerificationEmail(ctx, req.(*SendVerificationEmailDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_VerifyEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).VerifyEmail(ctx, req.(*VerifyEmailDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RequestEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmailChangeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequestEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequestEmailChange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequestEmailChange(ctx, req.(*RequestEmailChangeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ConfirmEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmEmailChangeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ConfirmEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ConfirmEmailChange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ConfirmEmailChange(ctx, req.(*ConfirmEmailChangeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_EnrollTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTotpDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).EnrollTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_EnrollTotp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).EnrollTotp(ctx, req.(*EnrollTotpDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ConfirmTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTotpDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ConfirmTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ConfirmTotp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ConfirmTotp(ctx, req.(*ConfirmTotpDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DisableTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTotpDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DisableTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_DisableTotp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DisableTotp(ctx, req.(*DisableTotpDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_VerifyMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMfaDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).VerifyMfa(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_VerifyMfa_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).VerifyMfa(ctx, req.(*VerifyMfaDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RequestMagicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestMagicLinkDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequestMagicLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequestMagicLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequestMagicLink(ctx, req.(*RequestMagicLinkDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RedeemMagicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeemMagicLinkDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RedeemMagicLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RedeemMagicLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RedeemMagicLink(ctx, req.(*RedeemMagicLinkDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ExchangeExternalToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeExternalTokenDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ExchangeExternalToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ExchangeExternalToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ExchangeExternalToken(ctx, req.(*ExchangeExternalTokenDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_LinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).LinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_LinkIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).LinkIdentity(ctx, req.(*LinkIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_UnlinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).UnlinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_UnlinkIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).UnlinkIdentity(ctx, req.(*UnlinkIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Impersonate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpersonateDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Impersonate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_Impersonate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Impersonate(ctx, req.(*ImpersonateDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_StopImpersonation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopImpersonationDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).StopImpersonation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_StopImpersonation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).StopImpersonation(ctx, req.(*StopImpersonationDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListSessions(ctx, req.(*ListSessionsDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeSession(ctx, req.(*RevokeSessionDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeAllOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllOtherSessionsDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeAllOtherSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeAllOtherSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeAllOtherSessions(ctx, req.(*RevokeAllOtherSessionsDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CreateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_CreateApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CreateApiKey(ctx, req.(*CreateApiKeyDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApiKeysDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListApiKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListApiKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListApiKeys(ctx, req.(*ListApiKeysDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeApiKeyDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeApiKey(ctx, req.(*RevokeApiKeyDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Auth_ServiceDesc is the grpc.ServiceDesc for Auth service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -182,6 +1270,134 @@ var Auth_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckAuth",
 			Handler:    _Auth_CheckAuth_Handler,
 		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _Auth_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Auth_Logout_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _Auth_RevokeToken_Handler,
+		},
+		{
+			MethodName: "RevokeUserTokens",
+			Handler:    _Auth_RevokeUserTokens_Handler,
+		},
+		{
+			MethodName: "UnlockAccount",
+			Handler:    _Auth_UnlockAccount_Handler,
+		},
+		{
+			MethodName: "GrantRole",
+			Handler:    _Auth_GrantRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _Auth_RevokeRole_Handler,
+		},
+		{
+			MethodName: "GetJWKS",
+			Handler:    _Auth_GetJWKS_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _Auth_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ConfirmPasswordReset",
+			Handler:    _Auth_ConfirmPasswordReset_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _Auth_ChangePassword_Handler,
+		},
+		{
+			MethodName: "SendVerificationEmail",
+			Handler:    _Auth_SendVerificationEmail_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _Auth_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "RequestEmailChange",
+			Handler:    _Auth_RequestEmailChange_Handler,
+		},
+		{
+			MethodName: "ConfirmEmailChange",
+			Handler:    _Auth_ConfirmEmailChange_Handler,
+		},
+		{
+			MethodName: "EnrollTotp",
+			Handler:    _Auth_EnrollTotp_Handler,
+		},
+		{
+			MethodName: "ConfirmTotp",
+			Handler:    _Auth_ConfirmTotp_Handler,
+		},
+		{
+			MethodName: "DisableTotp",
+			Handler:    _Auth_DisableTotp_Handler,
+		},
+		{
+			MethodName: "VerifyMfa",
+			Handler:    _Auth_VerifyMfa_Handler,
+		},
+		{
+			MethodName: "RequestMagicLink",
+			Handler:    _Auth_RequestMagicLink_Handler,
+		},
+		{
+			MethodName: "RedeemMagicLink",
+			Handler:    _Auth_RedeemMagicLink_Handler,
+		},
+		{
+			MethodName: "ExchangeExternalToken",
+			Handler:    _Auth_ExchangeExternalToken_Handler,
+		},
+		{
+			MethodName: "LinkIdentity",
+			Handler:    _Auth_LinkIdentity_Handler,
+		},
+		{
+			MethodName: "UnlinkIdentity",
+			Handler:    _Auth_UnlinkIdentity_Handler,
+		},
+		{
+			MethodName: "Impersonate",
+			Handler:    _Auth_Impersonate_Handler,
+		},
+		{
+			MethodName: "StopImpersonation",
+			Handler:    _Auth_StopImpersonation_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Auth_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Auth_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllOtherSessions",
+			Handler:    _Auth_RevokeAllOtherSessions_Handler,
+		},
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _Auth_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "ListApiKeys",
+			Handler:    _Auth_ListApiKeys_Handler,
+		},
+		{
+			MethodName: "RevokeApiKey",
+			Handler:    _Auth_RevokeApiKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.1
+// 	protoc-gen-go v1.34.2
 // 	protoc        v5.27.1
 // source: users.proto
 
@@ -11,6 +11,7 @@ package usersv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -27,12 +28,23 @@ type User struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email     string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Fname     string `protobuf:"bytes,3,opt,name=fname,proto3" json:"fname,omitempty"`
-	Lname     string `protobuf:"bytes,4,opt,name=lname,proto3" json:"lname,omitempty"`
-	Avatar    string `protobuf:"bytes,7,opt,name=avatar,proto3" json:"avatar,omitempty"`
-	AvatarMin string `protobuf:"bytes,8,opt,name=avatar_min,json=avatarMin,proto3" json:"avatar_min,omitempty"`
+	Id        string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email     string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Fname     string   `protobuf:"bytes,3,opt,name=fname,proto3" json:"fname,omitempty"`
+	Lname     string   `protobuf:"bytes,4,opt,name=lname,proto3" json:"lname,omitempty"`
+	Avatar    string   `protobuf:"bytes,7,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	AvatarMin string   `protobuf:"bytes,8,opt,name=avatar_min,json=avatarMin,proto3" json:"avatar_min,omitempty"`
+	Username  string   `protobuf:"bytes,9,opt,name=username,proto3" json:"username,omitempty"`
+	Bio       string   `protobuf:"bytes,10,opt,name=bio,proto3" json:"bio,omitempty"`
+	Links     []string `protobuf:"bytes,11,rep,name=links,proto3" json:"links,omitempty"`
+	Location  string   `protobuf:"bytes,12,opt,name=location,proto3" json:"location,omitempty"`
+	// birthday is YYYY-MM-DD, empty when not set or hidden from the caller
+	Birthday           string `protobuf:"bytes,13,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	BirthdayVisibility string `protobuf:"bytes,14,opt,name=birthday_visibility,json=birthdayVisibility,proto3" json:"birthday_visibility,omitempty"`
+	Pronouns           string `protobuf:"bytes,15,opt,name=pronouns,proto3" json:"pronouns,omitempty"`
+	Cover              string `protobuf:"bytes,16,opt,name=cover,proto3" json:"cover,omitempty"`
+	// version grows with every update, send it as expected_version to not overwrite concurrent updates
+	Version int64 `protobuf:"varint,17,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (x *User) Reset() {
@@ -109,6 +121,173 @@ func (x *User) GetAvatarMin() string {
 	return ""
 }
 
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *User) GetLinks() []string {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *User) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *User) GetBirthday() string {
+	if x != nil {
+		return x.Birthday
+	}
+	return ""
+}
+
+func (x *User) GetBirthdayVisibility() string {
+	if x != nil {
+		return x.BirthdayVisibility
+	}
+	return ""
+}
+
+func (x *User) GetPronouns() string {
+	if x != nil {
+		return x.Pronouns
+	}
+	return ""
+}
+
+func (x *User) GetCover() string {
+	if x != nil {
+		return x.Cover
+	}
+	return ""
+}
+
+func (x *User) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// GetUsersDTO takes at most the configured batch limit of ids.
+type GetUsersDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *GetUsersDTO) Reset() {
+	*x = GetUsersDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUsersDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersDTO) ProtoMessage() {}
+
+func (x *GetUsersDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersDTO.ProtoReflect.Descriptor instead.
+func (*GetUsersDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUsersDTO) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// GetUsersRDO has the users in the order of the requested ids.
+type GetUsersRDO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users       []*User  `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NotFoundIds []string `protobuf:"bytes,2,rep,name=not_found_ids,json=notFoundIds,proto3" json:"not_found_ids,omitempty"`
+}
+
+func (x *GetUsersRDO) Reset() {
+	*x = GetUsersRDO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUsersRDO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersRDO) ProtoMessage() {}
+
+func (x *GetUsersRDO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersRDO.ProtoReflect.Descriptor instead.
+func (*GetUsersRDO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUsersRDO) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *GetUsersRDO) GetNotFoundIds() []string {
+	if x != nil {
+		return x.NotFoundIds
+	}
+	return nil
+}
+
 type UploadAvatarDTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -116,12 +295,14 @@ type UploadAvatarDTO struct {
 
 	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Image  []byte `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// expected_version fails the upload with ABORTED when the user has been updated since, 0 skips the check
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
 }
 
 func (x *UploadAvatarDTO) Reset() {
 	*x = UploadAvatarDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[1]
+		mi := &file_users_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -134,7 +315,7 @@ func (x *UploadAvatarDTO) String() string {
 func (*UploadAvatarDTO) ProtoMessage() {}
 
 func (x *UploadAvatarDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[1]
+	mi := &file_users_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +328,7 @@ func (x *UploadAvatarDTO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarDTO.ProtoReflect.Descriptor instead.
 func (*UploadAvatarDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{1}
+	return file_users_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UploadAvatarDTO) GetUserId() string {
@@ -164,6 +345,13 @@ func (x *UploadAvatarDTO) GetImage() []byte {
 	return nil
 }
 
+func (x *UploadAvatarDTO) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type UploadAvatarRDO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -172,12 +360,13 @@ type UploadAvatarRDO struct {
 	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	AvatarUrl     string `protobuf:"bytes,2,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
 	AvatarMiniUrl string `protobuf:"bytes,3,opt,name=avatar_mini_url,json=avatarMiniUrl,proto3" json:"avatar_mini_url,omitempty"`
+	Version       int64  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (x *UploadAvatarRDO) Reset() {
 	*x = UploadAvatarRDO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[2]
+		mi := &file_users_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -190,7 +379,7 @@ func (x *UploadAvatarRDO) String() string {
 func (*UploadAvatarRDO) ProtoMessage() {}
 
 func (x *UploadAvatarRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[2]
+	mi := &file_users_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +392,7 @@ func (x *UploadAvatarRDO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarRDO.ProtoReflect.Descriptor instead.
 func (*UploadAvatarRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{2}
+	return file_users_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UploadAvatarRDO) GetUserId() string {
@@ -227,6 +416,13 @@ func (x *UploadAvatarRDO) GetAvatarMiniUrl() string {
 	return ""
 }
 
+func (x *UploadAvatarRDO) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type SubscribeDTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -239,7 +435,7 @@ type SubscribeDTO struct {
 func (x *SubscribeDTO) Reset() {
 	*x = SubscribeDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[3]
+		mi := &file_users_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -252,7 +448,7 @@ func (x *SubscribeDTO) String() string {
 func (*SubscribeDTO) ProtoMessage() {}
 
 func (x *SubscribeDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[3]
+	mi := &file_users_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,7 +461,7 @@ func (x *SubscribeDTO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeDTO.ProtoReflect.Descriptor instead.
 func (*SubscribeDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{3}
+	return file_users_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SubscribeDTO) GetBloggerId() string {
@@ -293,7 +489,7 @@ type SubscribeRDO struct {
 func (x *SubscribeRDO) Reset() {
 	*x = SubscribeRDO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[4]
+		mi := &file_users_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -306,7 +502,7 @@ func (x *SubscribeRDO) String() string {
 func (*SubscribeRDO) ProtoMessage() {}
 
 func (x *SubscribeRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[4]
+	mi := &file_users_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -319,7 +515,7 @@ func (x *SubscribeRDO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRDO.ProtoReflect.Descriptor instead.
 func (*SubscribeRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{4}
+	return file_users_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SubscribeRDO) GetIsSubscribe() bool {
@@ -340,7 +536,7 @@ type GetUserDTO struct {
 func (x *GetUserDTO) Reset() {
 	*x = GetUserDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[5]
+		mi := &file_users_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -353,7 +549,7 @@ func (x *GetUserDTO) String() string {
 func (*GetUserDTO) ProtoMessage() {}
 
 func (x *GetUserDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[5]
+	mi := &file_users_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +562,7 @@ func (x *GetUserDTO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserDTO.ProtoReflect.Descriptor instead.
 func (*GetUserDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{5}
+	return file_users_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetUserDTO) GetId() string {
@@ -387,7 +583,7 @@ type GetUserRDO struct {
 func (x *GetUserRDO) Reset() {
 	*x = GetUserRDO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[6]
+		mi := &file_users_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -400,7 +596,7 @@ func (x *GetUserRDO) String() string {
 func (*GetUserRDO) ProtoMessage() {}
 
 func (x *GetUserRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[6]
+	mi := &file_users_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -413,7 +609,7 @@ func (x *GetUserRDO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserRDO.ProtoReflect.Descriptor instead.
 func (*GetUserRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{6}
+	return file_users_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetUserRDO) GetUser() *User {
@@ -423,33 +619,482 @@ func (x *GetUserRDO) GetUser() *User {
 	return nil
 }
 
-type GetSubscribersDTO struct {
+type GetUserByUsernameDTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BloggerId string `protobuf:"bytes,1,opt,name=blogger_id,json=bloggerId,proto3" json:"blogger_id,omitempty"`
-	Page      int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	Size      int32  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *GetUserByUsernameDTO) Reset() {
+	*x = GetUserByUsernameDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserByUsernameDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByUsernameDTO) ProtoMessage() {}
+
+func (x *GetUserByUsernameDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByUsernameDTO.ProtoReflect.Descriptor instead.
+func (*GetUserByUsernameDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetUserByUsernameDTO) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type GetUserByUsernameRDO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User       *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	IsRedirect bool  `protobuf:"varint,2,opt,name=is_redirect,json=isRedirect,proto3" json:"is_redirect,omitempty"`
+}
+
+func (x *GetUserByUsernameRDO) Reset() {
+	*x = GetUserByUsernameRDO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserByUsernameRDO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByUsernameRDO) ProtoMessage() {}
+
+func (x *GetUserByUsernameRDO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByUsernameRDO.ProtoReflect.Descriptor instead.
+func (*GetUserByUsernameRDO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetUserByUsernameRDO) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *GetUserByUsernameRDO) GetIsRedirect() bool {
+	if x != nil {
+		return x.IsRedirect
+	}
+	return false
+}
+
+type CheckUsernameAvailableDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *CheckUsernameAvailableDTO) Reset() {
+	*x = CheckUsernameAvailableDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckUsernameAvailableDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckUsernameAvailableDTO) ProtoMessage() {}
+
+func (x *CheckUsernameAvailableDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckUsernameAvailableDTO.ProtoReflect.Descriptor instead.
+func (*CheckUsernameAvailableDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CheckUsernameAvailableDTO) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type CheckUsernameAvailableRDO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsAvailable bool     `protobuf:"varint,1,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	Reason      string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Suggestions []string `protobuf:"bytes,3,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+}
+
+func (x *CheckUsernameAvailableRDO) Reset() {
+	*x = CheckUsernameAvailableRDO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckUsernameAvailableRDO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckUsernameAvailableRDO) ProtoMessage() {}
+
+func (x *CheckUsernameAvailableRDO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckUsernameAvailableRDO.ProtoReflect.Descriptor instead.
+func (*CheckUsernameAvailableRDO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CheckUsernameAvailableRDO) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *CheckUsernameAvailableRDO) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CheckUsernameAvailableRDO) GetSuggestions() []string {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type GetSubscribersDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BloggerId string `protobuf:"bytes,1,opt,name=blogger_id,json=bloggerId,proto3" json:"blogger_id,omitempty"`
+	Page      int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size      int32  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *GetSubscribersDTO) Reset() {
+	*x = GetSubscribersDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSubscribersDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscribersDTO) ProtoMessage() {}
+
+func (x *GetSubscribersDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscribersDTO.ProtoReflect.Descriptor instead.
+func (*GetSubscribersDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetSubscribersDTO) GetBloggerId() string {
+	if x != nil {
+		return x.BloggerId
+	}
+	return ""
+}
+
+func (x *GetSubscribersDTO) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetSubscribersDTO) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type GetSubscribersRDO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subscribers []*User `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+	TotalCount  int32   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+}
+
+func (x *GetSubscribersRDO) Reset() {
+	*x = GetSubscribersRDO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSubscribersRDO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscribersRDO) ProtoMessage() {}
+
+func (x *GetSubscribersRDO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscribersRDO.ProtoReflect.Descriptor instead.
+func (*GetSubscribersRDO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSubscribersRDO) GetSubscribers() []*User {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+func (x *GetSubscribersRDO) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetSubscriptionsDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	Page         int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Size         int32  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *GetSubscriptionsDTO) Reset() {
+	*x = GetSubscriptionsDTO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSubscriptionsDTO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsDTO) ProtoMessage() {}
+
+func (x *GetSubscriptionsDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsDTO.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetSubscriptionsDTO) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsDTO) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsDTO) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type GetSubscriptionsRDO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subscriptions []*User `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	TotalCount    int32   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+}
+
+func (x *GetSubscriptionsRDO) Reset() {
+	*x = GetSubscriptionsRDO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_users_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSubscriptionsRDO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsRDO) ProtoMessage() {}
+
+func (x *GetSubscriptionsRDO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsRDO.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsRDO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetSubscriptionsRDO) GetSubscriptions() []*User {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *GetSubscriptionsRDO) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type UpdateUserDTO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UpdateData map[string]string `protobuf:"bytes,2,rep,name=updateData,proto3" json:"updateData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// expected_version fails the update with ABORTED when the user has been updated since, 0 skips the check
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
 }
 
-func (x *GetSubscribersDTO) Reset() {
-	*x = GetSubscribersDTO{}
+func (x *UpdateUserDTO) Reset() {
+	*x = UpdateUserDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[7]
+		mi := &file_users_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSubscribersDTO) String() string {
+func (x *UpdateUserDTO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSubscribersDTO) ProtoMessage() {}
+func (*UpdateUserDTO) ProtoMessage() {}
 
-func (x *GetSubscribersDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[7]
+func (x *UpdateUserDTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -460,58 +1105,66 @@ func (x *GetSubscribersDTO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSubscribersDTO.ProtoReflect.Descriptor instead.
-func (*GetSubscribersDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UpdateUserDTO.ProtoReflect.Descriptor instead.
+func (*UpdateUserDTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetSubscribersDTO) GetBloggerId() string {
+func (x *UpdateUserDTO) GetId() string {
 	if x != nil {
-		return x.BloggerId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *GetSubscribersDTO) GetPage() int32 {
+func (x *UpdateUserDTO) GetUpdateData() map[string]string {
 	if x != nil {
-		return x.Page
+		return x.UpdateData
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSubscribersDTO) GetSize() int32 {
+func (x *UpdateUserDTO) GetExpectedVersion() int64 {
 	if x != nil {
-		return x.Size
+		return x.ExpectedVersion
 	}
 	return 0
 }
 
-type GetSubscribersRDO struct {
+// UserPatch holds the new values of the fields listed in the update mask, a listed field left empty is cleared.
+type UserPatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Subscribers []*User `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
-	TotalCount  int32   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Fname    string         `protobuf:"bytes,1,opt,name=fname,proto3" json:"fname,omitempty"`
+	Lname    string         `protobuf:"bytes,2,opt,name=lname,proto3" json:"lname,omitempty"`
+	Username string         `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Bio      string         `protobuf:"bytes,4,opt,name=bio,proto3" json:"bio,omitempty"`
+	Links    []string       `protobuf:"bytes,5,rep,name=links,proto3" json:"links,omitempty"`
+	Location string         `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	Birthday *BirthdayPatch `protobuf:"bytes,7,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	Pronouns string         `protobuf:"bytes,8,opt,name=pronouns,proto3" json:"pronouns,omitempty"`
+	Cover    string         `protobuf:"bytes,9,opt,name=cover,proto3" json:"cover,omitempty"`
 }
 
-func (x *GetSubscribersRDO) Reset() {
-	*x = GetSubscribersRDO{}
+func (x *UserPatch) Reset() {
+	*x = UserPatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[8]
+		mi := &file_users_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSubscribersRDO) String() string {
+func (x *UserPatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSubscribersRDO) ProtoMessage() {}
+func (*UserPatch) ProtoMessage() {}
 
-func (x *GetSubscribersRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[8]
+func (x *UserPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,114 +1175,101 @@ func (x *GetSubscribersRDO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSubscribersRDO.ProtoReflect.Descriptor instead.
-func (*GetSubscribersRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use UserPatch.ProtoReflect.Descriptor instead.
+func (*UserPatch) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GetSubscribersRDO) GetSubscribers() []*User {
+func (x *UserPatch) GetFname() string {
 	if x != nil {
-		return x.Subscribers
+		return x.Fname
 	}
-	return nil
+	return ""
 }
 
-func (x *GetSubscribersRDO) GetTotalCount() int32 {
+func (x *UserPatch) GetLname() string {
 	if x != nil {
-		return x.TotalCount
+		return x.Lname
 	}
-	return 0
-}
-
-type GetSubscriptionsDTO struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
-	Page         int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	Size         int32  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	return ""
 }
 
-func (x *GetSubscriptionsDTO) Reset() {
-	*x = GetSubscriptionsDTO{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *UserPatch) GetUsername() string {
+	if x != nil {
+		return x.Username
 	}
+	return ""
 }
 
-func (x *GetSubscriptionsDTO) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *UserPatch) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
 }
 
-func (*GetSubscriptionsDTO) ProtoMessage() {}
-
-func (x *GetSubscriptionsDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *UserPatch) GetLinks() []string {
+	if x != nil {
+		return x.Links
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GetSubscriptionsDTO.ProtoReflect.Descriptor instead.
-func (*GetSubscriptionsDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{9}
+func (x *UserPatch) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
 }
 
-func (x *GetSubscriptionsDTO) GetSubscriberId() string {
+func (x *UserPatch) GetBirthday() *BirthdayPatch {
 	if x != nil {
-		return x.SubscriberId
+		return x.Birthday
 	}
-	return ""
+	return nil
 }
 
-func (x *GetSubscriptionsDTO) GetPage() int32 {
+func (x *UserPatch) GetPronouns() string {
 	if x != nil {
-		return x.Page
+		return x.Pronouns
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSubscriptionsDTO) GetSize() int32 {
+func (x *UserPatch) GetCover() string {
 	if x != nil {
-		return x.Size
+		return x.Cover
 	}
-	return 0
+	return ""
 }
 
-type GetSubscriptionsRDO struct {
+type BirthdayPatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Subscriptions []*User `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
-	TotalCount    int32   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// date is YYYY-MM-DD
+	Date       string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Visibility string `protobuf:"bytes,2,opt,name=visibility,proto3" json:"visibility,omitempty"`
 }
 
-func (x *GetSubscriptionsRDO) Reset() {
-	*x = GetSubscriptionsRDO{}
+func (x *BirthdayPatch) Reset() {
+	*x = BirthdayPatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[10]
+		mi := &file_users_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSubscriptionsRDO) String() string {
+func (x *BirthdayPatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSubscriptionsRDO) ProtoMessage() {}
+func (*BirthdayPatch) ProtoMessage() {}
 
-func (x *GetSubscriptionsRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[10]
+func (x *BirthdayPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -640,51 +1280,56 @@ func (x *GetSubscriptionsRDO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSubscriptionsRDO.ProtoReflect.Descriptor instead.
-func (*GetSubscriptionsRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use BirthdayPatch.ProtoReflect.Descriptor instead.
+func (*BirthdayPatch) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *GetSubscriptionsRDO) GetSubscriptions() []*User {
+func (x *BirthdayPatch) GetDate() string {
 	if x != nil {
-		return x.Subscriptions
+		return x.Date
 	}
-	return nil
+	return ""
 }
 
-func (x *GetSubscriptionsRDO) GetTotalCount() int32 {
+func (x *BirthdayPatch) GetVisibility() string {
 	if x != nil {
-		return x.TotalCount
+		return x.Visibility
 	}
-	return 0
+	return ""
 }
 
-type UpdateUserDTO struct {
+// UpdateUserV2DTO updates the paths of update_mask only, e.g. "bio", "links" or "birthday.visibility",
+// the "birthday" path updates both the date and the visibility.
+type UpdateUserV2DTO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id         string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UpdateData map[string]string `protobuf:"bytes,2,rep,name=updateData,proto3" json:"updateData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User       *UserPatch             `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// expected_version fails the update with ABORTED when the user has been updated since, 0 skips the check
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
 }
 
-func (x *UpdateUserDTO) Reset() {
-	*x = UpdateUserDTO{}
+func (x *UpdateUserV2DTO) Reset() {
+	*x = UpdateUserV2DTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[11]
+		mi := &file_users_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UpdateUserDTO) String() string {
+func (x *UpdateUserV2DTO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserDTO) ProtoMessage() {}
+func (*UpdateUserV2DTO) ProtoMessage() {}
 
-func (x *UpdateUserDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[11]
+func (x *UpdateUserV2DTO) ProtoReflect() protoreflect.Message {
+	mi := &file_users_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -695,25 +1340,39 @@ func (x *UpdateUserDTO) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserDTO.ProtoReflect.Descriptor instead.
-func (*UpdateUserDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use UpdateUserV2DTO.ProtoReflect.Descriptor instead.
+func (*UpdateUserV2DTO) Descriptor() ([]byte, []int) {
+	return file_users_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *UpdateUserDTO) GetId() string {
+func (x *UpdateUserV2DTO) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *UpdateUserDTO) GetUpdateData() map[string]string {
+func (x *UpdateUserV2DTO) GetUser() *UserPatch {
 	if x != nil {
-		return x.UpdateData
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateUserV2DTO) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
 	}
 	return nil
 }
 
+func (x *UpdateUserV2DTO) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type UpdateUserRDO struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -725,7 +1384,7 @@ type UpdateUserRDO struct {
 func (x *UpdateUserRDO) Reset() {
 	*x = UpdateUserRDO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[12]
+		mi := &file_users_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -738,7 +1397,7 @@ func (x *UpdateUserRDO) String() string {
 func (*UpdateUserRDO) ProtoMessage() {}
 
 func (x *UpdateUserRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[12]
+	mi := &file_users_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -751,7 +1410,7 @@ func (x *UpdateUserRDO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserRDO.ProtoReflect.Descriptor instead.
 func (*UpdateUserRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{12}
+	return file_users_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *UpdateUserRDO) GetUser() *User {
@@ -772,7 +1431,7 @@ type DeleteUserDTO struct {
 func (x *DeleteUserDTO) Reset() {
 	*x = DeleteUserDTO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[13]
+		mi := &file_users_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -785,7 +1444,7 @@ func (x *DeleteUserDTO) String() string {
 func (*DeleteUserDTO) ProtoMessage() {}
 
 func (x *DeleteUserDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[13]
+	mi := &file_users_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -798,7 +1457,7 @@ func (x *DeleteUserDTO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteUserDTO.ProtoReflect.Descriptor instead.
 func (*DeleteUserDTO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{13}
+	return file_users_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *DeleteUserDTO) GetId() string {
@@ -819,7 +1478,7 @@ type DeleteUserRDO struct {
 func (x *DeleteUserRDO) Reset() {
 	*x = DeleteUserRDO{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_users_proto_msgTypes[14]
+		mi := &file_users_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -832,7 +1491,7 @@ func (x *DeleteUserRDO) String() string {
 func (*DeleteUserRDO) ProtoMessage() {}
 
 func (x *DeleteUserRDO) ProtoReflect() protoreflect.Message {
-	mi := &file_users_proto_msgTypes[14]
+	mi := &file_users_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -845,7 +1504,7 @@ func (x *DeleteUserRDO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteUserRDO.ProtoReflect.Descriptor instead.
 func (*DeleteUserRDO) Descriptor() ([]byte, []int) {
-	return file_users_proto_rawDescGZIP(), []int{14}
+	return file_users_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *DeleteUserRDO) GetIsDeleted() bool {
@@ -859,117 +1518,219 @@ var File_users_proto protoreflect.FileDescriptor
 
 var file_users_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x75,
-	0x73, 0x65, 0x72, 0x73, 0x22, 0x8f, 0x01, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a,
-	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d,
-	0x61, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x76, 0x61, 0x74, 0x61,
-	0x72, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x76, 0x61,
-	0x74, 0x61, 0x72, 0x4d, 0x69, 0x6e, 0x22, 0x40, 0x0a, 0x0f, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
-	0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65,
-	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0x71, 0x0a, 0x0f, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x17, 0x0a, 0x07, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x88, 0x03, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x76, 0x61,
+	0x74, 0x61, 0x72, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x76, 0x61, 0x74, 0x61, 0x72, 0x4d, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x69, 0x6f, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x62, 0x69, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x69, 0x72, 0x74,
+	0x68, 0x64, 0x61, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x69, 0x72, 0x74,
+	0x68, 0x64, 0x61, 0x79, 0x12, 0x2f, 0x0a, 0x13, 0x62, 0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79,
+	0x5f, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x12, 0x62, 0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79, 0x56, 0x69, 0x73, 0x69, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75, 0x6e,
+	0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75, 0x6e,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x1f, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x44, 0x54, 0x4f,
+	0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69,
+	0x64, 0x73, 0x22, 0x54, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x44,
+	0x4f, 0x12, 0x21, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x05, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0d, 0x6e, 0x6f, 0x74, 0x5f, 0x66, 0x6f, 0x75, 0x6e,
+	0x64, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x6f, 0x74,
+	0x46, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x73, 0x22, 0x6b, 0x0a, 0x0f, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x17, 0x0a, 0x07, 0x75,
 	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x5f, 0x75,
-	0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72,
-	0x55, 0x72, 0x6c, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x5f, 0x6d, 0x69,
-	0x6e, 0x69, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x76,
-	0x61, 0x74, 0x61, 0x72, 0x4d, 0x69, 0x6e, 0x69, 0x55, 0x72, 0x6c, 0x22, 0x52, 0x0a, 0x0c, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
-	0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75,
-	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x22,
-	0x31, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x44, 0x4f, 0x12,
-	0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x62, 0x65, 0x22, 0x1c, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0x2d, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x1f,
-	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75,
-	0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22,
-	0x5a, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
-	0x73, 0x44, 0x54, 0x4f, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x63, 0x0a, 0x11, 0x47,
-	0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x52, 0x44, 0x4f,
-	0x12, 0x2d, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73,
-	0x65, 0x72, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x12,
-	0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x22, 0x62, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x44, 0x54, 0x4f, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x8b, 0x01, 0x0a, 0x0f, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x55, 0x72,
+	0x6c, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x5f, 0x6d, 0x69, 0x6e, 0x69,
+	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x76, 0x61, 0x74,
+	0x61, 0x72, 0x4d, 0x69, 0x6e, 0x69, 0x55, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0x52, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x44, 0x54, 0x4f, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x52, 0x44, 0x4f, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69,
+	0x73, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x22, 0x1c, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2d, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x32, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x54, 0x4f, 0x12,
+	0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x58, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x52, 0x44, 0x4f, 0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x52, 0x65, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x22, 0x37, 0x0a, 0x19, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x44,
+	0x54, 0x4f, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x78,
+	0x0a, 0x19, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41,
+	0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x44, 0x4f, 0x12, 0x21, 0x0a, 0x0c, 0x69,
+	0x73, 0x5f, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x69, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x5a, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x44, 0x54, 0x4f, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
 	0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65,
 	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x73, 0x69, 0x7a, 0x65, 0x22, 0x69, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x31, 0x0a, 0x0d, 0x73,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52,
-	0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f,
-	0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22,
-	0xa4, 0x01, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54,
+	0x73, 0x69, 0x7a, 0x65, 0x22, 0x63, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x2d, 0x0a, 0x0b, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x0b, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x62, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x54, 0x4f,
+	0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x69, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x44, 0x4f, 0x12, 0x31, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73,
+	0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xcf, 0x01, 0x0a, 0x0d, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x44, 0x0a, 0x0a, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x44, 0x54, 0x4f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x3d, 0x0a, 0x0f, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xfb, 0x01, 0x0a, 0x09, 0x55,
+	0x73, 0x65, 0x72, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x62, 0x69, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62,
+	0x69, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x08, 0x62, 0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x42,
+	0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x08, 0x62, 0x69,
+	0x72, 0x74, 0x68, 0x64, 0x61, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75,
+	0x6e, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75,
+	0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x22, 0x43, 0x0a, 0x0d, 0x42, 0x69, 0x72, 0x74,
+	0x68, 0x64, 0x61, 0x79, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0xaf, 0x01,
+	0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x56, 0x32, 0x44, 0x54,
 	0x4f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x44, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x2e, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x75, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x3d, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x30, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73,
-	0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1f, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2e, 0x0a, 0x0d, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73,
-	0x5f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
-	0x69, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x32, 0xf5, 0x03, 0x0a, 0x0c, 0x55, 0x73,
-	0x65, 0x72, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x47, 0x65,
-	0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65,
-	0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x1a, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73,
-	0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x35, 0x0a, 0x09, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73,
-	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x44, 0x54, 0x4f, 0x1a, 0x13, 0x2e,
-	0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52,
-	0x44, 0x4f, 0x12, 0x37, 0x0a, 0x0b, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
-	0x65, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x62, 0x65, 0x44, 0x54, 0x4f, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x44, 0x4f, 0x12, 0x44, 0x0a, 0x0e, 0x47,
-	0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x12, 0x18, 0x2e,
-	0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x62, 0x65, 0x72, 0x73, 0x44, 0x54, 0x4f, 0x1a, 0x18, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e,
-	0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x52, 0x44,
-	0x4f, 0x12, 0x4a, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65,
-	0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x54,
-	0x4f, 0x1a, 0x1a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x38, 0x0a,
-	0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x75, 0x73,
-	0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54,
-	0x4f, 0x1a, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x38, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x1a, 0x14, 0x2e, 0x75, 0x73,
-	0x65, 0x72, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44,
-	0x4f, 0x12, 0x3e, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61,
-	0x72, 0x12, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
-	0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x44, 0x54, 0x4f, 0x1a, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72,
-	0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x44,
-	0x4f, 0x42, 0x14, 0x5a, 0x12, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x3b,
-	0x75, 0x73, 0x65, 0x72, 0x73, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x12, 0x24, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x4d, 0x61, 0x73, 0x6b, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x30, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f,
+	0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x22, 0x1f, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44,
+	0x54, 0x4f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x2e, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x44, 0x4f, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x32, 0x94, 0x06, 0x0a, 0x0c, 0x55, 0x73, 0x65, 0x72, 0x73, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x11,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54,
+	0x4f, 0x1a, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x44, 0x4f, 0x12, 0x32, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73,
+	0x12, 0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x73, 0x44, 0x54, 0x4f, 0x1a, 0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x35, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x44, 0x54, 0x4f, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x44, 0x4f, 0x12,
+	0x37, 0x0a, 0x0b, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x13,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x44, 0x54, 0x4f, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x44, 0x4f, 0x12, 0x44, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x12, 0x18, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
+	0x73, 0x44, 0x54, 0x4f, 0x1a, 0x18, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x4a,
+	0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x1a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x54, 0x4f, 0x1a, 0x1a,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x44, 0x4f, 0x12, 0x38, 0x0a, 0x0a, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x1a, 0x14,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x44, 0x4f, 0x12, 0x3c, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x56, 0x32, 0x12, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x56, 0x32, 0x44, 0x54, 0x4f, 0x1a, 0x14, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x44, 0x4f, 0x12, 0x38, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x44, 0x54, 0x4f, 0x1a, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x3e, 0x0a, 0x0c,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x16, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61,
+	0x72, 0x44, 0x54, 0x4f, 0x1a, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x44, 0x4f, 0x12, 0x4d, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x54, 0x4f, 0x1a, 0x1b,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x42, 0x79,
+	0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x44, 0x4f, 0x12, 0x5c, 0x0a, 0x16, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x20, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x76, 0x61, 0x69, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x44, 0x54, 0x4f, 0x1a, 0x20, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2e,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x44, 0x4f, 0x42, 0x14, 0x5a, 0x12, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x73, 0x3b, 0x75, 0x73, 0x65, 0x72, 0x73, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -984,52 +1745,75 @@ func file_users_proto_rawDescGZIP() []byte {
 	return file_users_proto_rawDescData
 }
 
-var file_users_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
-var file_users_proto_goTypes = []interface{}{
-	(*User)(nil),                // 0: users.User
-	(*UploadAvatarDTO)(nil),     // 1: users.UploadAvatarDTO
-	(*UploadAvatarRDO)(nil),     // 2: users.UploadAvatarRDO
-	(*SubscribeDTO)(nil),        // 3: users.SubscribeDTO
-	(*SubscribeRDO)(nil),        // 4: users.SubscribeRDO
-	(*GetUserDTO)(nil),          // 5: users.GetUserDTO
-	(*GetUserRDO)(nil),          // 6: users.GetUserRDO
-	(*GetSubscribersDTO)(nil),   // 7: users.GetSubscribersDTO
-	(*GetSubscribersRDO)(nil),   // 8: users.GetSubscribersRDO
-	(*GetSubscriptionsDTO)(nil), // 9: users.GetSubscriptionsDTO
-	(*GetSubscriptionsRDO)(nil), // 10: users.GetSubscriptionsRDO
-	(*UpdateUserDTO)(nil),       // 11: users.UpdateUserDTO
-	(*UpdateUserRDO)(nil),       // 12: users.UpdateUserRDO
-	(*DeleteUserDTO)(nil),       // 13: users.DeleteUserDTO
-	(*DeleteUserRDO)(nil),       // 14: users.DeleteUserRDO
-	nil,                         // 15: users.UpdateUserDTO.UpdateDataEntry
+var file_users_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_users_proto_goTypes = []any{
+	(*User)(nil),                      // 0: users.User
+	(*GetUsersDTO)(nil),               // 1: users.GetUsersDTO
+	(*GetUsersRDO)(nil),               // 2: users.GetUsersRDO
+	(*UploadAvatarDTO)(nil),           // 3: users.UploadAvatarDTO
+	(*UploadAvatarRDO)(nil),           // 4: users.UploadAvatarRDO
+	(*SubscribeDTO)(nil),              // 5: users.SubscribeDTO
+	(*SubscribeRDO)(nil),              // 6: users.SubscribeRDO
+	(*GetUserDTO)(nil),                // 7: users.GetUserDTO
+	(*GetUserRDO)(nil),                // 8: users.GetUserRDO
+	(*GetUserByUsernameDTO)(nil),      // 9: users.GetUserByUsernameDTO
+	(*GetUserByUsernameRDO)(nil),      // 10: users.GetUserByUsernameRDO
+	(*CheckUsernameAvailableDTO)(nil), // 11: users.CheckUsernameAvailableDTO
+	(*CheckUsernameAvailableRDO)(nil), // 12: users.CheckUsernameAvailableRDO
+	(*GetSubscribersDTO)(nil),         // 13: users.GetSubscribersDTO
+	(*GetSubscribersRDO)(nil),         // 14: users.GetSubscribersRDO
+	(*GetSubscriptionsDTO)(nil),       // 15: users.GetSubscriptionsDTO
+	(*GetSubscriptionsRDO)(nil),       // 16: users.GetSubscriptionsRDO
+	(*UpdateUserDTO)(nil),             // 17: users.UpdateUserDTO
+	(*UserPatch)(nil),                 // 18: users.UserPatch
+	(*BirthdayPatch)(nil),             // 19: users.BirthdayPatch
+	(*UpdateUserV2DTO)(nil),           // 20: users.UpdateUserV2DTO
+	(*UpdateUserRDO)(nil),             // 21: users.UpdateUserRDO
+	(*DeleteUserDTO)(nil),             // 22: users.DeleteUserDTO
+	(*DeleteUserRDO)(nil),             // 23: users.DeleteUserRDO
+	nil,                               // 24: users.UpdateUserDTO.UpdateDataEntry
+	(*fieldmaskpb.FieldMask)(nil),     // 25: google.protobuf.FieldMask
 }
 var file_users_proto_depIdxs = []int32{
-	0,  // 0: users.GetUserRDO.user:type_name -> users.User
-	0,  // 1: users.GetSubscribersRDO.subscribers:type_name -> users.User
-	0,  // 2: users.GetSubscriptionsRDO.subscriptions:type_name -> users.User
-	15, // 3: users.UpdateUserDTO.updateData:type_name -> users.UpdateUserDTO.UpdateDataEntry
-	0,  // 4: users.UpdateUserRDO.user:type_name -> users.User
-	5,  // 5: users.UsersService.GetUser:input_type -> users.GetUserDTO
-	3,  // 6: users.UsersService.Subscribe:input_type -> users.SubscribeDTO
-	3,  // 7: users.UsersService.Unsubscribe:input_type -> users.SubscribeDTO
-	7,  // 8: users.UsersService.GetSubscribers:input_type -> users.GetSubscribersDTO
-	9,  // 9: users.UsersService.GetSubscriptions:input_type -> users.GetSubscriptionsDTO
-	11, // 10: users.UsersService.UpdateUser:input_type -> users.UpdateUserDTO
-	13, // 11: users.UsersService.DeleteUser:input_type -> users.DeleteUserDTO
-	1,  // 12: users.UsersService.UploadAvatar:input_type -> users.UploadAvatarDTO
-	6,  // 13: users.UsersService.GetUser:output_type -> users.GetUserRDO
-	4,  // 14: users.UsersService.Subscribe:output_type -> users.SubscribeRDO
-	4,  // 15: users.UsersService.Unsubscribe:output_type -> users.SubscribeRDO
-	8,  // 16: users.UsersService.GetSubscribers:output_type -> users.GetSubscribersRDO
-	10, // 17: users.UsersService.GetSubscriptions:output_type -> users.GetSubscriptionsRDO
-	12, // 18: users.UsersService.UpdateUser:output_type -> users.UpdateUserRDO
-	14, // 19: users.UsersService.DeleteUser:output_type -> users.DeleteUserRDO
-	2,  // 20: users.UsersService.UploadAvatar:output_type -> users.UploadAvatarRDO
-	13, // [13:21] is the sub-list for method output_type
-	5,  // [5:13] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	0,  // 0: users.GetUsersRDO.users:type_name -> users.User
+	0,  // 1: users.GetUserRDO.user:type_name -> users.User
+	0,  // 2: users.GetUserByUsernameRDO.user:type_name -> users.User
+	0,  // 3: users.GetSubscribersRDO.subscribers:type_name -> users.User
+	0,  // 4: users.GetSubscriptionsRDO.subscriptions:type_name -> users.User
+	24, // 5: users.UpdateUserDTO.updateData:type_name -> users.UpdateUserDTO.UpdateDataEntry
+	19, // 6: users.UserPatch.birthday:type_name -> users.BirthdayPatch
+	18, // 7: users.UpdateUserV2DTO.user:type_name -> users.UserPatch
+	25, // 8: users.UpdateUserV2DTO.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 9: users.UpdateUserRDO.user:type_name -> users.User
+	7,  // 10: users.UsersService.GetUser:input_type -> users.GetUserDTO
+	1,  // 11: users.UsersService.GetUsers:input_type -> users.GetUsersDTO
+	5,  // 12: users.UsersService.Subscribe:input_type -> users.SubscribeDTO
+	5,  // 13: users.UsersService.Unsubscribe:input_type -> users.SubscribeDTO
+	13, // 14: users.UsersService.GetSubscribers:input_type -> users.GetSubscribersDTO
+	15, // 15: users.UsersService.GetSubscriptions:input_type -> users.GetSubscriptionsDTO
+	17, // 16: users.UsersService.UpdateUser:input_type -> users.UpdateUserDTO
+	20, // 17: users.UsersService.UpdateUserV2:input_type -> users.UpdateUserV2DTO
+	22, // 18: users.UsersService.DeleteUser:input_type -> users.DeleteUserDTO
+	3,  // 19: users.UsersService.UploadAvatar:input_type -> users.UploadAvatarDTO
+	9,  // 20: users.UsersService.GetUserByUsername:input_type -> users.GetUserByUsernameDTO
+	11, // 21: users.UsersService.CheckUsernameAvailable:input_type -> users.CheckUsernameAvailableDTO
+	8,  // 22: users.UsersService.GetUser:output_type -> users.GetUserRDO
+	2,  // 23: users.UsersService.GetUsers:output_type -> users.GetUsersRDO
+	6,  // 24: users.UsersService.Subscribe:output_type -> users.SubscribeRDO
+	6,  // 25: users.UsersService.Unsubscribe:output_type -> users.SubscribeRDO
+	14, // 26: users.UsersService.GetSubscribers:output_type -> users.GetSubscribersRDO
+	16, // 27: users.UsersService.GetSubscriptions:output_type -> users.GetSubscriptionsRDO
+	21, // 28: users.UsersService.UpdateUser:output_type -> users.UpdateUserRDO
+	21, // 29: users.UsersService.UpdateUserV2:output_type -> users.UpdateUserRDO
+	23, // 30: users.UsersService.DeleteUser:output_type -> users.DeleteUserRDO
+	4,  // 31: users.UsersService.UploadAvatar:output_type -> users.UploadAvatarRDO
+	10, // 32: users.UsersService.GetUserByUsername:output_type -> users.GetUserByUsernameRDO
+	12, // 33: users.UsersService.CheckUsernameAvailable:output_type -> users.CheckUsernameAvailableRDO
+	22, // [22:34] is the sub-list for method output_type
+	10, // [10:22] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_users_proto_init() }
@@ -1038,7 +1822,7 @@ func file_users_proto_init() {
 		return
 	}
 	if !protoimpl.UnsafeEnabled {
-		file_users_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[0].Exporter = func(v any, i int) any {
 			switch v := v.(*User); i {
 			case 0:
 				return &v.state
@@ -1050,7 +1834,31 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUsersDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUsersRDO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[3].Exporter = func(v any, i int) any {
 			switch v := v.(*UploadAvatarDTO); i {
 			case 0:
 				return &v.state
@@ -1062,7 +1870,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[4].Exporter = func(v any, i int) any {
 			switch v := v.(*UploadAvatarRDO); i {
 			case 0:
 				return &v.state
@@ -1074,7 +1882,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[5].Exporter = func(v any, i int) any {
 			switch v := v.(*SubscribeDTO); i {
 			case 0:
 				return &v.state
@@ -1086,7 +1894,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[6].Exporter = func(v any, i int) any {
 			switch v := v.(*SubscribeRDO); i {
 			case 0:
 				return &v.state
@@ -1098,7 +1906,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[7].Exporter = func(v any, i int) any {
 			switch v := v.(*GetUserDTO); i {
 			case 0:
 				return &v.state
@@ -1110,7 +1918,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[8].Exporter = func(v any, i int) any {
 			switch v := v.(*GetUserRDO); i {
 			case 0:
 				return &v.state
@@ -1122,7 +1930,55 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserByUsernameDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserByUsernameRDO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CheckUsernameAvailableDTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*CheckUsernameAvailableRDO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[13].Exporter = func(v any, i int) any {
 			switch v := v.(*GetSubscribersDTO); i {
 			case 0:
 				return &v.state
@@ -1134,7 +1990,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[14].Exporter = func(v any, i int) any {
 			switch v := v.(*GetSubscribersRDO); i {
 			case 0:
 				return &v.state
@@ -1146,7 +2002,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[15].Exporter = func(v any, i int) any {
 			switch v := v.(*GetSubscriptionsDTO); i {
 			case 0:
 				return &v.state
@@ -1158,7 +2014,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[16].Exporter = func(v any, i int) any {
 			switch v := v.(*GetSubscriptionsRDO); i {
 			case 0:
 				return &v.state
@@ -1170,7 +2026,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[17].Exporter = func(v any, i int) any {
 			switch v := v.(*UpdateUserDTO); i {
 			case 0:
 				return &v.state
@@ -1182,7 +2038,43 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*UserPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*BirthdayPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateUserV2DTO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_users_proto_msgTypes[21].Exporter = func(v any, i int) any {
 			switch v := v.(*UpdateUserRDO); i {
 			case 0:
 				return &v.state
@@ -1194,7 +2086,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[22].Exporter = func(v any, i int) any {
 			switch v := v.(*DeleteUserDTO); i {
 			case 0:
 				return &v.state
@@ -1206,7 +2098,7 @@ func file_users_proto_init() {
 				return nil
 			}
 		}
-		file_users_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_users_proto_msgTypes[23].Exporter = func(v any, i int) any {
 			switch v := v.(*DeleteUserRDO); i {
 			case 0:
 				return &v.state
@@ -1225,7 +2117,7 @@ func file_users_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_users_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
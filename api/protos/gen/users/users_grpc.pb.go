@@ -21,14 +21,18 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	UsersService_GetUser_FullMethodName          = "/users.UsersService/GetUser"
-	UsersService_Subscribe_FullMethodName        = "/users.UsersService/Subscribe"
-	UsersService_Unsubscribe_FullMethodName      = "/users.UsersService/Unsubscribe"
-	UsersService_GetSubscribers_FullMethodName   = "/users.UsersService/GetSubscribers"
-	UsersService_GetSubscriptions_FullMethodName = "/users.UsersService/GetSubscriptions"
-	UsersService_UpdateUser_FullMethodName       = "/users.UsersService/UpdateUser"
-	UsersService_DeleteUser_FullMethodName       = "/users.UsersService/DeleteUser"
-	UsersService_UploadAvatar_FullMethodName     = "/users.UsersService/UploadAvatar"
+	UsersService_GetUser_FullMethodName                = "/users.UsersService/GetUser"
+	UsersService_GetUsers_FullMethodName               = "/users.UsersService/GetUsers"
+	UsersService_Subscribe_FullMethodName              = "/users.UsersService/Subscribe"
+	UsersService_Unsubscribe_FullMethodName            = "/users.UsersService/Unsubscribe"
+	UsersService_GetSubscribers_FullMethodName         = "/users.UsersService/GetSubscribers"
+	UsersService_GetSubscriptions_FullMethodName       = "/users.UsersService/GetSubscriptions"
+	UsersService_UpdateUser_FullMethodName             = "/users.UsersService/UpdateUser"
+	UsersService_UpdateUserV2_FullMethodName           = "/users.UsersService/UpdateUserV2"
+	UsersService_DeleteUser_FullMethodName             = "/users.UsersService/DeleteUser"
+	UsersService_UploadAvatar_FullMethodName           = "/users.UsersService/UploadAvatar"
+	UsersService_GetUserByUsername_FullMethodName      = "/users.UsersService/GetUserByUsername"
+	UsersService_CheckUsernameAvailable_FullMethodName = "/users.UsersService/CheckUsernameAvailable"
 )
 
 // UsersServiceClient is the client API for UsersService service.
@@ -36,13 +40,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UsersServiceClient interface {
 	GetUser(ctx context.Context, in *GetUserDTO, opts ...grpc.CallOption) (*GetUserRDO, error)
+	GetUsers(ctx context.Context, in *GetUsersDTO, opts ...grpc.CallOption) (*GetUsersRDO, error)
 	Subscribe(ctx context.Context, in *SubscribeDTO, opts ...grpc.CallOption) (*SubscribeRDO, error)
 	Unsubscribe(ctx context.Context, in *SubscribeDTO, opts ...grpc.CallOption) (*SubscribeRDO, error)
 	GetSubscribers(ctx context.Context, in *GetSubscribersDTO, opts ...grpc.CallOption) (*GetSubscribersRDO, error)
 	GetSubscriptions(ctx context.Context, in *GetSubscriptionsDTO, opts ...grpc.CallOption) (*GetSubscriptionsRDO, error)
 	UpdateUser(ctx context.Context, in *UpdateUserDTO, opts ...grpc.CallOption) (*UpdateUserRDO, error)
+	UpdateUserV2(ctx context.Context, in *UpdateUserV2DTO, opts ...grpc.CallOption) (*UpdateUserRDO, error)
 	DeleteUser(ctx context.Context, in *DeleteUserDTO, opts ...grpc.CallOption) (*DeleteUserRDO, error)
 	UploadAvatar(ctx context.Context, in *UploadAvatarDTO, opts ...grpc.CallOption) (*UploadAvatarRDO, error)
+	GetUserByUsername(ctx context.Context, in *GetUserByUsernameDTO, opts ...grpc.CallOption) (*GetUserByUsernameRDO, error)
+	CheckUsernameAvailable(ctx context.Context, in *CheckUsernameAvailableDTO, opts ...grpc.CallOption) (*CheckUsernameAvailableRDO, error)
 }
 
 type usersServiceClient struct {
@@ -63,6 +71,16 @@ func (c *usersServiceClient) GetUser(ctx context.Context, in *GetUserDTO, opts .
 	return out, nil
 }
 
+func (c *usersServiceClient) GetUsers(ctx context.Context, in *GetUsersDTO, opts ...grpc.CallOption) (*GetUsersRDO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsersRDO)
+	err := c.cc.Invoke(ctx, UsersService_GetUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersServiceClient) Subscribe(ctx context.Context, in *SubscribeDTO, opts ...grpc.CallOption) (*SubscribeRDO, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SubscribeRDO)
@@ -113,6 +131,16 @@ func (c *usersServiceClient) UpdateUser(ctx context.Context, in *UpdateUserDTO,
 	return out, nil
 }
 
+func (c *usersServiceClient) UpdateUserV2(ctx context.Context, in *UpdateUserV2DTO, opts ...grpc.CallOption) (*UpdateUserRDO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateUserRDO)
+	err := c.cc.Invoke(ctx, UsersService_UpdateUserV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersServiceClient) DeleteUser(ctx context.Context, in *DeleteUserDTO, opts ...grpc.CallOption) (*DeleteUserRDO, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteUserRDO)
@@ -133,18 +161,42 @@ func (c *usersServiceClient) UploadAvatar(ctx context.Context, in *UploadAvatarD
 	return out, nil
 }
 
+func (c *usersServiceClient) GetUserByUsername(ctx context.Context, in *GetUserByUsernameDTO, opts ...grpc.CallOption) (*GetUserByUsernameRDO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserByUsernameRDO)
+	err := c.cc.Invoke(ctx, UsersService_GetUserByUsername_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) CheckUsernameAvailable(ctx context.Context, in *CheckUsernameAvailableDTO, opts ...grpc.CallOption) (*CheckUsernameAvailableRDO, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckUsernameAvailableRDO)
+	err := c.cc.Invoke(ctx, UsersService_CheckUsernameAvailable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UsersServiceServer is the server API for UsersService service.
 // All implementations must embed UnimplementedUsersServiceServer
 // for forward compatibility
 type UsersServiceServer interface {
 	GetUser(context.Context, *GetUserDTO) (*GetUserRDO, error)
+	GetUsers(context.Context, *GetUsersDTO) (*GetUsersRDO, error)
 	Subscribe(context.Context, *SubscribeDTO) (*SubscribeRDO, error)
 	Unsubscribe(context.Context, *SubscribeDTO) (*SubscribeRDO, error)
 	GetSubscribers(context.Context, *GetSubscribersDTO) (*GetSubscribersRDO, error)
 	GetSubscriptions(context.Context, *GetSubscriptionsDTO) (*GetSubscriptionsRDO, error)
 	UpdateUser(context.Context, *UpdateUserDTO) (*UpdateUserRDO, error)
+	UpdateUserV2(context.Context, *UpdateUserV2DTO) (*UpdateUserRDO, error)
 	DeleteUser(context.Context, *DeleteUserDTO) (*DeleteUserRDO, error)
 	UploadAvatar(context.Context, *UploadAvatarDTO) (*UploadAvatarRDO, error)
+	GetUserByUsername(context.Context, *GetUserByUsernameDTO) (*GetUserByUsernameRDO, error)
+	CheckUsernameAvailable(context.Context, *CheckUsernameAvailableDTO) (*CheckUsernameAvailableRDO, error)
 	mustEmbedUnimplementedUsersServiceServer()
 }
 
@@ -155,6 +207,9 @@ type UnimplementedUsersServiceServer struct {
 func (UnimplementedUsersServiceServer) GetUser(context.Context, *GetUserDTO) (*GetUserRDO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
 }
+func (UnimplementedUsersServiceServer) GetUsers(context.Context, *GetUsersDTO) (*GetUsersRDO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsers not implemented")
+}
 func (UnimplementedUsersServiceServer) Subscribe(context.Context, *SubscribeDTO) (*SubscribeRDO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
@@ -170,12 +225,21 @@ func (UnimplementedUsersServiceServer) GetSubscriptions(context.Context, *GetSub
 func (UnimplementedUsersServiceServer) UpdateUser(context.Context, *UpdateUserDTO) (*UpdateUserRDO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
 }
+func (UnimplementedUsersServiceServer) UpdateUserV2(context.Context, *UpdateUserV2DTO) (*UpdateUserRDO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserV2 not implemented")
+}
 func (UnimplementedUsersServiceServer) DeleteUser(context.Context, *DeleteUserDTO) (*DeleteUserRDO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
 func (UnimplementedUsersServiceServer) UploadAvatar(context.Context, *UploadAvatarDTO) (*UploadAvatarRDO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
 }
+func (UnimplementedUsersServiceServer) GetUserByUsername(context.Context, *GetUserByUsernameDTO) (*GetUserByUsernameRDO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByUsername not implemented")
+}
+func (UnimplementedUsersServiceServer) CheckUsernameAvailable(context.Context, *CheckUsernameAvailableDTO) (*CheckUsernameAvailableRDO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckUsernameAvailable not implemented")
+}
 func (UnimplementedUsersServiceServer) mustEmbedUnimplementedUsersServiceServer() {}
 
 // UnsafeUsersServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -207,6 +271,24 @@ func _UsersService_GetUser_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UsersService_GetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsersDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).GetUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_GetUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).GetUsers(ctx, req.(*GetUsersDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UsersService_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SubscribeDTO)
 	if err := dec(in); err != nil {
@@ -297,6 +379,24 @@ func _UsersService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UsersService_UpdateUserV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserV2DTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).UpdateUserV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_UpdateUserV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).UpdateUserV2(ctx, req.(*UpdateUserV2DTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UsersService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteUserDTO)
 	if err := dec(in); err != nil {
@@ -333,6 +433,42 @@ func _UsersService_UploadAvatar_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UsersService_GetUserByUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserByUsernameDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).GetUserByUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_GetUserByUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).GetUserByUsername(ctx, req.(*GetUserByUsernameDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_CheckUsernameAvailable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckUsernameAvailableDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).CheckUsernameAvailable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_CheckUsernameAvailable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).CheckUsernameAvailable(ctx, req.(*CheckUsernameAvailableDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UsersService_ServiceDesc is the grpc.ServiceDesc for UsersService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -344,6 +480,10 @@ var UsersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUser",
 			Handler:    _UsersService_GetUser_Handler,
 		},
+		{
+			MethodName: "GetUsers",
+			Handler:    _UsersService_GetUsers_Handler,
+		},
 		{
 			MethodName: "Subscribe",
 			Handler:    _UsersService_Subscribe_Handler,
@@ -364,6 +504,10 @@ var UsersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateUser",
 			Handler:    _UsersService_UpdateUser_Handler,
 		},
+		{
+			MethodName: "UpdateUserV2",
+			Handler:    _UsersService_UpdateUserV2_Handler,
+		},
 		{
 			MethodName: "DeleteUser",
 			Handler:    _UsersService_DeleteUser_Handler,
@@ -372,6 +516,14 @@ var UsersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadAvatar",
 			Handler:    _UsersService_UploadAvatar_Handler,
 		},
+		{
+			MethodName: "GetUserByUsername",
+			Handler:    _UsersService_GetUserByUsername_Handler,
+		},
+		{
+			MethodName: "CheckUsernameAvailable",
+			Handler:    _UsersService_CheckUsernameAvailable_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "users.proto",
@@ -8,18 +8,24 @@ import (
 	"github.com/KBcHMFollower/blog_user_service/internal/app/store_app"
 	"github.com/KBcHMFollower/blog_user_service/internal/app/workers_app"
 	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache/memory"
+	oidcclient "github.com/KBcHMFollower/blog_user_service/internal/clients/oidc"
 	"github.com/KBcHMFollower/blog_user_service/internal/config"
 	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
 	amqphandlers "github.com/KBcHMFollower/blog_user_service/internal/handlers/amqp"
 	"github.com/KBcHMFollower/blog_user_service/internal/interceptors"
 	"github.com/KBcHMFollower/blog_user_service/internal/lib"
 	"github.com/KBcHMFollower/blog_user_service/internal/lib/circuid_breaker"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
+	secretshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/secrets"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
 	"github.com/KBcHMFollower/blog_user_service/internal/lib/validators"
 	"github.com/KBcHMFollower/blog_user_service/internal/logger"
 	"github.com/KBcHMFollower/blog_user_service/internal/repository"
 	authservice "github.com/KBcHMFollower/blog_user_service/internal/services"
 	"github.com/KBcHMFollower/blog_user_service/internal/workers"
 	"google.golang.org/grpc"
+	"net/http"
 	"time"
 )
 
@@ -41,13 +47,56 @@ func New(
 	rabbitMqApp, err := amqp_app.NewAmqpApp(cfg.RabbitMq, log)
 	lib.ContinueOrPanic(err)
 
-	vldor, err := validators.NewValidator()
+	passwordPolicy, err := newPasswordPolicy(cfg.Password.Policy)
+	lib.ContinueOrPanic(err)
+
+	vldor, err := validators.NewValidator(passwordPolicy)
+	lib.ContinueOrPanic(err)
+
+	tokenKeys, err := loadTokenKeys(cfg.JWT)
+	lib.ContinueOrPanic(err)
+
+	totpCipher, err := secretshelper.NewAesCipher(cfg.Mfa.EncryptionKey)
+	lib.ContinueOrPanic(err)
+
+	passwordHasher, err := newPasswordHasher(cfg.Password.Hashing)
+	lib.ContinueOrPanic(err)
+
+	oidcVerifier, err := newOidcVerifier(cfg.Oidc)
 	lib.ContinueOrPanic(err)
 
 	eventRepository := repository.NewEventRepository(storageApp.PostgresStore.Store)
 	subsRepository := repository.NewSubscriberRepository(storageApp.PostgresStore.Store)
 	userRepository := repository.NewUserRepository(storageApp.PostgresStore.Store, storageApp.RedisStore)
 	reqRepository := repository.NewRequestsRepository(storageApp.PostgresStore.Store)
+	refreshTokensRepository := repository.NewRefreshTokensRepository(storageApp.PostgresStore.Store)
+	revocationsRepository := repository.NewTokenRevocationsRepository(storageApp.PostgresStore.Store, storageApp.RedisStore)
+	usernameHistoryRepository := repository.NewUsernameHistoryRepository(storageApp.PostgresStore.Store)
+	oneTimeTokensRepository := repository.NewOneTimeTokensRepository(storageApp.PostgresStore.Store)
+	totpRepository := repository.NewTotpRepository(storageApp.PostgresStore.Store)
+	sessionsRepository := repository.NewSessionsRepository(storageApp.PostgresStore.Store, storageApp.RedisStore)
+	rolesRepository := repository.NewRolesRepository(storageApp.PostgresStore.Store, storageApp.RedisStore)
+	apiKeysRepository := repository.NewApiKeysRepository(storageApp.PostgresStore.Store, storageApp.RedisStore)
+	identitiesRepository := repository.NewExternalIdentitiesRepository(storageApp.PostgresStore.Store)
+	auditLogRepository := repository.NewAuditLogRepository(storageApp.PostgresStore.Store)
+	loginAttemptsRepository := repository.NewLoginAttemptsRepository(storageApp.RedisStore, memory.NewMemoryCache(cfg.Redis.CacheTTL))
+
+	jwtOpts := tokenshelper.JwtOptions{
+		Keys:     tokenKeys,
+		Issuer:   cfg.JWT.Issuer,
+		Audience: cfg.JWT.Audience,
+		TTL:      cfg.JWT.TokenTTL,
+	}
+	emailVerificationOpts := authservice.EmailVerificationOptions{
+		TokenTTL: cfg.Email.VerificationTokenTTL,
+		LinkBase: cfg.Email.VerificationLinkBase,
+		Required: cfg.Email.VerificationRequired,
+	}
+	totpOpts := authservice.TotpOptions{
+		Issuer:       cfg.Mfa.Issuer,
+		ChallengeTTL: cfg.Mfa.ChallengeTTL,
+		Cipher:       totpCipher,
+	}
 
 	userService := authservice.NewUserService(
 		log,
@@ -55,14 +104,112 @@ func New(
 		userRepository,
 		eventRepository,
 		storageApp.S3Client,
+		revocationsRepository,
+		usernameHistoryRepository,
+		authservice.UsernameOptions{
+			RedirectTTL: cfg.Username.RedirectTTL,
+		},
+		authservice.UsersBatchOptions{
+			Limit: cfg.Users.BatchLimit,
+		},
 	)
+	lockoutOpts := authservice.LockoutOptions{
+		MaxEmailAttempts: cfg.Lockout.MaxEmailAttempts,
+		MaxIpAttempts:    cfg.Lockout.MaxIpAttempts,
+		MaxMfaAttempts:   cfg.Lockout.MaxMfaAttempts,
+		Window:           cfg.Lockout.Window,
+		Duration:         cfg.Lockout.Duration,
+		BaseDelay:        cfg.Lockout.BaseDelay,
+		MaxDelay:         cfg.Lockout.MaxDelay,
+	}
 	authService := authservice.NewAuthService(
 		userRepository,
+		refreshTokensRepository,
+		sessionsRepository,
+		rolesRepository,
+		revocationsRepository,
+		oneTimeTokensRepository,
+		eventRepository,
+		totpRepository,
+		loginAttemptsRepository,
+		identitiesRepository,
+		auditLogRepository,
+		log,
+		passwordHasher,
+		passwordPolicy,
+		oidcVerifier,
+		jwtOpts,
+		cfg.JWT.ReissueWindow,
+		cfg.JWT.RefreshTokenTTL,
+		emailVerificationOpts,
+		totpOpts,
+		lockoutOpts,
+		authservice.RegisterOptions{
+			Silent: cfg.Register.Silent,
+		},
+		authservice.MagicLinkOptions{
+			TokenTTL: cfg.MagicLink.TokenTTL,
+			LinkBase: cfg.MagicLink.LinkBase,
+			SignUp:   cfg.MagicLink.SignUp,
+		},
+		authservice.ImpersonationOptions{
+			TokenTTL: cfg.Impersonation.TokenTTL,
+		},
+		storageApp.PostgresStore.Store,
+	)
+	passwordService := authservice.NewPasswordService(
+		userRepository,
+		oneTimeTokensRepository,
+		refreshTokensRepository,
+		sessionsRepository,
+		eventRepository,
+		log,
+		passwordPolicy,
+		passwordHasher,
+		cfg.Password.ResetTokenTTL,
+		storageApp.PostgresStore.Store,
+	)
+	emailService := authservice.NewEmailService(
+		userRepository,
+		oneTimeTokensRepository,
+		eventRepository,
 		log,
-		cfg.JWT.TokenTTL,
-		cfg.JWT.TokenSecret,
+		passwordHasher,
+		emailVerificationOpts,
+		authservice.EmailChangeOptions{
+			TokenTTL: cfg.Email.ChangeTokenTTL,
+			LinkBase: cfg.Email.ChangeLinkBase,
+		},
 		storageApp.PostgresStore.Store,
 	)
+	totpService := authservice.NewTotpService(
+		userRepository,
+		totpRepository,
+		loginAttemptsRepository,
+		log,
+		totpOpts,
+		lockoutOpts,
+		storageApp.PostgresStore.Store,
+	)
+	sessionService := authservice.NewSessionService(
+		sessionsRepository,
+		refreshTokensRepository,
+		log,
+		storageApp.PostgresStore.Store,
+	)
+	roleService := authservice.NewRoleService(
+		userRepository,
+		rolesRepository,
+		log,
+		storageApp.PostgresStore.Store,
+	)
+	apiKeyService := authservice.NewApiKeyService(apiKeysRepository, log)
+	identityService := authservice.NewIdentityService(
+		userRepository,
+		identitiesRepository,
+		oidcVerifier,
+		log,
+	)
 	reqService := authservice.NewRequestsService(reqRepository, log)
 	subsService := authservice.NewSubscribersService(
 		subsRepository,
@@ -80,8 +227,11 @@ func New(
 		options.IgnorableErrors = []error{
 			ctxerrors.ErrNotFound,
 			ctxerrors.ErrUnauthorized,
+			ctxerrors.ErrForbidden,
 			ctxerrors.ErrConflict,
 			ctxerrors.ErrBadRequest,
+			ctxerrors.ErrTooManyRequests,
+			ctxerrors.ErrAborted,
 		}
 		options.OpenConditions = circuid_breaker.OpenCondition{
 			FailuresRate: 40,
@@ -99,6 +249,8 @@ func New(
 	interceptorsChain := grpc.ChainUnaryInterceptor(
 		interceptors.CircuitBreakerInterceptor(circuitBreaker),
 		interceptors.ErrorHandlerInterceptor(),
+		interceptors.ClientIpInterceptor(cfg.GRpc.ClientIpMdKey),
+		interceptors.AuthInterceptor(authService, apiKeyService, roleService, jwtOpts, interceptors.AuthPolicies()),
 		interceptors.ReqLoggingInterceptor(log),
 		interceptors.IdempotencyInterceptor(reqService),
 	)
@@ -109,6 +261,13 @@ func New(
 		cfg.GRpc.Port,
 		userService,
 		authService,
+		passwordService,
+		emailService,
+		totpService,
+		sessionService,
+		roleService,
+		apiKeyService,
+		identityService,
 		subsService,
 		vldor,
 		interceptorsChain,
@@ -125,6 +284,94 @@ func New(
 	}
 }
 
+func loadTokenKeys(jwtCfg config.JWT) (*tokenshelper.KeySet, error) {
+	if len(jwtCfg.Keys) == 0 {
+		return tokenshelper.NewHmacKeySet(jwtCfg.TokenSecret), nil
+	}
+
+	keysInfo := make([]tokenshelper.KeyInfo, 0, len(jwtCfg.Keys))
+	for _, key := range jwtCfg.Keys {
+		keysInfo = append(keysInfo, tokenshelper.KeyInfo{
+			Kid:            key.Kid,
+			Algorithm:      key.Algorithm,
+			PrivateKeyPath: key.PrivateKeyPath,
+			PublicKeyPath:  key.PublicKeyPath,
+		})
+	}
+
+	keySet, err := tokenshelper.LoadKeySet(jwtCfg.ActiveKid, keysInfo)
+	if err != nil {
+		return nil, ctxerrors.Wrap("can`t load jwt keys", err)
+	}
+
+	return keySet, nil
+}
+
+func newOidcVerifier(oidcCfg config.Oidc) (*oidcclient.Verifier, error) {
+	providers := make([]oidcclient.Provider, 0, len(oidcCfg.Providers))
+	for _, provider := range oidcCfg.Providers {
+		providers = append(providers, oidcclient.Provider{
+			Name:      provider.Name,
+			Issuer:    provider.Issuer,
+			ClientIds: provider.ClientIds,
+			JwksUrl:   provider.JwksUrl,
+		})
+	}
+
+	verifier, err := oidcclient.NewVerifier(providers, &http.Client{Timeout: oidcCfg.HttpTimeout}, oidcCfg.JwksCacheTTL)
+	if err != nil {
+		return nil, ctxerrors.Wrap("can`t configure oidc providers", err)
+	}
+
+	return verifier, nil
+}
+
+// newPasswordPolicy loads the breached passwords once, the policy is shared by the validator and the services.
+func newPasswordPolicy(policyCfg config.PasswordPolicy) (passwordshelper.Policy, error) {
+	policy := passwordshelper.Policy{
+		MinLength:            policyCfg.MinLength,
+		MaxLength:            policyCfg.MaxLength,
+		RequireUpper:         policyCfg.RequireUpper,
+		RequireLower:         policyCfg.RequireLower,
+		RequireDigit:         policyCfg.RequireDigit,
+		RequireSpecial:       policyCfg.RequireSpecial,
+		DisallowPersonalInfo: policyCfg.DisallowPersonalInfo,
+	}
+
+	if policyCfg.BreachedListPath != "" {
+		breached, err := passwordshelper.LoadBreachedList(policyCfg.BreachedListPath, policyCfg.BreachedMinCount)
+		if err != nil {
+			return passwordshelper.Policy{}, ctxerrors.Wrap("can`t load breached passwords", err)
+		}
+
+		policy.Breached = breached
+	}
+
+	return policy, nil
+}
+
+// newPasswordHasher keeps the hashes of both algorithms verifiable, so switching the algorithm
+// doesn't lock out users, their hashes are upgraded on login.
+func newPasswordHasher(hashingCfg config.PasswordHashing) (passwordshelper.Hasher, error) {
+	argon2id := passwordshelper.NewArgon2idHasher(passwordshelper.Argon2idParams{
+		Memory:      hashingCfg.Argon2id.Memory,
+		Iterations:  hashingCfg.Argon2id.Iterations,
+		Parallelism: hashingCfg.Argon2id.Parallelism,
+		SaltLength:  hashingCfg.Argon2id.SaltLength,
+		KeyLength:   hashingCfg.Argon2id.KeyLength,
+	})
+	bcrypt := passwordshelper.NewBcryptHasher(hashingCfg.BcryptCost)
+
+	switch hashingCfg.Algorithm {
+	case "argon2id":
+		return passwordshelper.NewMultiHasher(argon2id, bcrypt), nil
+	case "bcrypt":
+		return passwordshelper.NewMultiHasher(bcrypt, argon2id), nil
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm: %s", hashingCfg.Algorithm)
+	}
+}
+
 func (a *App) Run() {
 	err := a.storeApp.Run()
 	lib.ContinueOrPanic(err)
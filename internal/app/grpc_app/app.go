@@ -23,13 +23,20 @@ func New(
 	port int,
 	userService servicesinterfaces.UserService,
 	authService servicesinterfaces.AuthService,
+	passwordService servicesinterfaces.PasswordService,
+	emailService servicesinterfaces.EmailService,
+	totpService servicesinterfaces.TotpService,
+	sessionService servicesinterfaces.SessionService,
+	roleService servicesinterfaces.RoleService,
+	apiKeyService servicesinterfaces.ApiKeyService,
+	identityService servicesinterfaces.IdentityService,
 	subsService servicesinterfaces.SubsService,
 	validator handlersdep.Validator,
 	interceptor grpc.ServerOption,
 ) *App {
 	gRpcServer := grpc.NewServer(interceptor)
 
-	grpcservers2.RegisterAuthServer(gRpcServer, authService, validator, log)
+	grpcservers2.RegisterAuthServer(gRpcServer, authService, passwordService, emailService, totpService, sessionService, roleService, apiKeyService, identityService, validator, log)
 	grpcservers2.RegisterUserServer(gRpcServer, userService, subsService, log, validator)
 
 	return &App{
@@ -5,6 +5,16 @@ import "context"
 const (
 	PostsDeletedEventKey = "posts-deleted-feedback"
 	UserDeletedEventKey  = "user-deleted"
+
+	PasswordResetRequestedEventKey = "password-reset-requested"
+	PasswordChangedEventKey        = "user-password-changed"
+
+	EmailVerificationRequestedEventKey = "email-verification-requested"
+	EmailChangeRequestedEventKey       = "email-change-requested"
+	EmailChangeNoticeEventKey          = "email-change-notice"
+
+	RegistrationAttemptNoticeEventKey = "registration-attempt-notice"
+	MagicLinkRequestedEventKey        = "magic-link-requested"
 )
 
 type AmqpSender interface {
@@ -0,0 +1,23 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// EmailChangeRequestedMessage is sent to the new address to confirm it.
+type EmailChangeRequestedMessage struct {
+	EventId   uuid.UUID `json:"event_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EmailChangeNoticeMessage warns the current address that the email change is requested.
+type EmailChangeNoticeMessage struct {
+	EventId  uuid.UUID `json:"event_id"`
+	UserId   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	NewEmail string    `json:"new_email"`
+}
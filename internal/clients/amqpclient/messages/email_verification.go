@@ -0,0 +1,14 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type EmailVerificationRequestedMessage struct {
+	EventId   uuid.UUID `json:"event_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
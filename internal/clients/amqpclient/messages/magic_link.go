@@ -0,0 +1,16 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// MagicLinkRequestedMessage carries the login link, UserId is empty when the link signs a new user up.
+type MagicLinkRequestedMessage struct {
+	EventId   uuid.UUID `json:"event_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Link      string    `json:"link"`
+	SignUp    bool      `json:"sign_up"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
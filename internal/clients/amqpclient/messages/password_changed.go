@@ -0,0 +1,13 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordChangedMessage struct {
+	EventId   uuid.UUID `json:"event_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
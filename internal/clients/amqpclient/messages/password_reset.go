@@ -0,0 +1,14 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordResetRequestedMessage struct {
+	EventId   uuid.UUID `json:"event_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,15 @@
+package messages
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RegistrationAttemptNoticeMessage tells the owner of an email that someone tried to register with it.
+// It is sent instead of a conflict error in the silent registration mode.
+type RegistrationAttemptNoticeMessage struct {
+	EventId     uuid.UUID `json:"event_id"`
+	UserId      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
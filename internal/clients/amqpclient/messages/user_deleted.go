@@ -6,15 +6,26 @@ import (
 )
 
 type UserMessage struct {
-	Id          uuid.UUID `json:"id"`
-	Email       string    `json:"email"`
-	FName       string    `json:"fname"`
-	LName       string    `json:"lname"`
-	Avatar      string    `json:"avatar"`
-	AvatarMin   string    `json:"avatar_min"`
-	PassHash    []byte    `json:"pass_hash"`
-	CreatedDate time.Time `json:"created_date"`
-	UpdatedDate time.Time `json:"updated_date"`
+	Id                 uuid.UUID  `json:"id"`
+	Email              string     `json:"email"`
+	Username           string     `json:"username,omitempty"`
+	FName              string     `json:"fname"`
+	LName              string     `json:"lname"`
+	Avatar             string     `json:"avatar"`
+	AvatarMin          string     `json:"avatar_min"`
+	Bio                string     `json:"bio"`
+	Links              []string   `json:"links"`
+	Location           string     `json:"location"`
+	Birthday           *time.Time `json:"birthday,omitempty"`
+	BirthdayVisibility string     `json:"birthday_visibility"`
+	Pronouns           string     `json:"pronouns"`
+	Cover              string     `json:"cover"`
+	Version            int64      `json:"version"`
+	PassHash           []byte     `json:"pass_hash"`
+	TokenVersion       int        `json:"token_version"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at,omitempty"`
+	CreatedDate        time.Time  `json:"created_date"`
+	UpdatedDate        time.Time  `json:"updated_date"`
 }
 
 type UserDeletedMessage struct {
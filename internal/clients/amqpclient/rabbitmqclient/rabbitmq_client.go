@@ -16,6 +16,24 @@ const (
 	UserPostsDeletedQueue = amqpclient.PostsDeletedEventKey
 )
 
+// UserNotificationsExchange carries the events a mailer delivers to users, every event type has its own queue.
+const (
+	UserNotificationsExchange   = "direct-user-notifications"
+	PasswordResetRequestedQueue = amqpclient.PasswordResetRequestedEventKey
+	PasswordChangedQueue        = amqpclient.PasswordChangedEventKey
+	EmailVerificationQueue      = amqpclient.EmailVerificationRequestedEventKey
+	EmailChangeQueue            = amqpclient.EmailChangeRequestedEventKey
+	EmailChangeNoticeQueue      = amqpclient.EmailChangeNoticeEventKey
+)
+
+var notificationQueues = []string{
+	PasswordResetRequestedQueue,
+	PasswordChangedQueue,
+	EmailVerificationQueue,
+	EmailChangeQueue,
+	EmailChangeNoticeQueue,
+}
+
 const (
 	queueLogKey   = "queue"
 	messageLogKey = "message"
@@ -161,6 +179,19 @@ func DeclareExchanges(ch *amqp.Channel) error {
 		return ctxerrors.Wrap("failed to declare DeleteUser exchange", err)
 	}
 
+	err = ch.ExchangeDeclare(
+		UserNotificationsExchange,
+		"direct",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return ctxerrors.Wrap("failed to declare UserNotifications exchange", err)
+	}
+
 	return nil
 }
 
@@ -209,5 +240,29 @@ func DeclareQueues(ch *amqp.Channel) error {
 		return ctxerrors.Wrap("failed to bind DeleteUser posts queue", err)
 	}
 
+	for _, queue := range notificationQueues {
+		q, err = ch.QueueDeclare(
+			queue,
+			true,
+			false,
+			false,
+			false,
+			nil,
+		)
+		if err != nil {
+			return ctxerrors.Wrap(fmt.Sprintf("failed to declare %s queue", queue), err)
+		}
+
+		if err = ch.QueueBind(
+			q.Name,
+			queue,
+			UserNotificationsExchange,
+			false,
+			nil,
+		); err != nil {
+			return ctxerrors.Wrap(fmt.Sprintf("failed to bind %s queue", queue), err)
+		}
+	}
+
 	return nil
 }
@@ -15,6 +15,9 @@ func NewSendersStore(ch *amqp.Channel) *SendersStore {
 	sendersMap := map[string]amqpclient.AmqpSender{
 		"user-deleted": &UserDeletedSender{ch: ch},
 	}
+	for _, queue := range notificationQueues {
+		sendersMap[queue] = &NotificationSender{ch: ch, queue: queue}
+	}
 
 	return &SendersStore{
 		ch:         ch,
@@ -51,3 +54,25 @@ func (uds *UserDeletedSender) Send(message []byte) error {
 
 	return nil
 }
+
+type NotificationSender struct {
+	ch    *amqp.Channel
+	queue string
+}
+
+func (ns *NotificationSender) Send(message []byte) error {
+	if err := ns.ch.Publish(
+		UserNotificationsExchange,
+		ns.queue,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        message,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to send message: %s", err)
+	}
+
+	return nil
+}
@@ -2,11 +2,22 @@ package cache
 
 import (
 	"context"
+	"time"
 )
 
 type CacheStorage interface {
 	Set(ctx context.Context, key string, value interface{}) error
+	// SetWithTTL is Set with a key specific ttl instead of the storage default one.
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// SetNX is Set that leaves an existing value untouched, it reports whether the value was written.
+	SetNX(ctx context.Context, key string, value interface{}) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
+	// MGet reads the keys in one round trip, the keys missing in the cache are left out of the result.
+	MGet(ctx context.Context, keys ...string) (map[string]string, error)
+	// SetMany writes the values in one round trip with the storage default ttl.
+	SetMany(ctx context.Context, values map[string]interface{}) error
+	// Incr increments the counter stored at key, ttl is applied only when the counter is created.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
 	Stop() error
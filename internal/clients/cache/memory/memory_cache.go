@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	cleanupInterval = time.Minute
+)
+
+var ErrKeyNotFound = errors.New("key not found")
+
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (i memoryItem) isExpired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// MemoryCache is a process local CacheStorage. It is used where redis is optional,
+// so its values are not shared between service instances.
+type MemoryCache struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	ttl   time.Duration
+	stop  chan struct{}
+}
+
+func NewMemoryCache(ttl time.Duration) cache.CacheStorage {
+	mc := &MemoryCache{
+		items: make(map[string]memoryItem),
+		ttl:   ttl,
+		stop:  make(chan struct{}),
+	}
+
+	go mc.cleanup()
+
+	return mc
+}
+
+func (mc *MemoryCache) Stop() error {
+	close(mc.stop)
+	return nil
+}
+
+func (mc *MemoryCache) Set(ctx context.Context, key string, value interface{}) error {
+	return mc.SetWithTTL(ctx, key, value, mc.ttl)
+}
+
+func (mc *MemoryCache) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.items[key] = newMemoryItem(fmt.Sprint(value), ttl)
+
+	return nil
+}
+
+func (mc *MemoryCache) SetNX(_ context.Context, key string, value interface{}) (bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if _, ok := mc.item(key); ok {
+		return false, nil
+	}
+	mc.items[key] = newMemoryItem(fmt.Sprint(value), mc.ttl)
+
+	return true, nil
+}
+
+func (mc *MemoryCache) Get(_ context.Context, key string) (string, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	item, ok := mc.item(key)
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	return item.value, nil
+}
+
+func (mc *MemoryCache) MGet(_ context.Context, keys ...string) (map[string]string, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	found := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if item, ok := mc.item(key); ok {
+			found[key] = item.value
+		}
+	}
+
+	return found, nil
+}
+
+func (mc *MemoryCache) SetMany(_ context.Context, values map[string]interface{}) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	for key, value := range values {
+		mc.items[key] = newMemoryItem(fmt.Sprint(value), mc.ttl)
+	}
+
+	return nil
+}
+
+func (mc *MemoryCache) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	item, ok := mc.item(key)
+	if !ok {
+		item = newMemoryItem("0", ttl)
+	}
+
+	val, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of `%s` is not an integer: %w", key, err)
+	}
+
+	val++
+	item.value = strconv.FormatInt(val, 10)
+	mc.items[key] = item
+
+	return val, nil
+}
+
+func (mc *MemoryCache) Delete(_ context.Context, key string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	delete(mc.items, key)
+
+	return nil
+}
+
+func (mc *MemoryCache) Exists(_ context.Context, key string) (bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	_, ok := mc.item(key)
+
+	return ok, nil
+}
+
+// item must be called with mu held, expired items are removed on access.
+func (mc *MemoryCache) item(key string) (memoryItem, bool) {
+	item, ok := mc.items[key]
+	if !ok {
+		return memoryItem{}, false
+	}
+	if item.isExpired(time.Now()) {
+		delete(mc.items, key)
+		return memoryItem{}, false
+	}
+
+	return item, true
+}
+
+func (mc *MemoryCache) cleanup() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mc.stop:
+			return
+		case now := <-ticker.C:
+			mc.mu.Lock()
+			for key, item := range mc.items {
+				if item.isExpired(now) {
+					delete(mc.items, key)
+				}
+			}
+			mc.mu.Unlock()
+		}
+	}
+}
+
+func newMemoryItem(value string, ttl time.Duration) memoryItem {
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+
+	return item
+}
@@ -7,6 +7,16 @@ import (
 	"time"
 )
 
+// incrScript increments the counter and sets the ttl in one step, a counter must not be left without ttl
+// when the client fails between the two commands. The ttl is set on a counter that has none yet.
+var incrScript = redis.NewScript(`
+local val = redis.call("INCR", KEYS[1])
+if tonumber(ARGV[1]) > 0 and redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return val
+`)
+
 type RedisCache struct {
 	client *redis.Client
 	ttl    time.Duration
@@ -37,10 +47,53 @@ func (rc *RedisCache) Set(ctx context.Context, key string, value interface{}) er
 	return rc.client.Set(ctx, key, value, rc.ttl).Err()
 }
 
+func (rc *RedisCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return rc.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (rc *RedisCache) SetNX(ctx context.Context, key string, value interface{}) (bool, error) {
+	return rc.client.SetNX(ctx, key, value, rc.ttl).Result()
+}
+
 func (rc *RedisCache) Get(ctx context.Context, key string) (string, error) {
 	return rc.client.Get(ctx, key).Result()
 }
 
+func (rc *RedisCache) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	found := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	values, err := rc.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			found[keys[i]] = s
+		}
+	}
+	return found, nil
+}
+
+func (rc *RedisCache) SetMany(ctx context.Context, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := rc.client.Pipeline()
+	for key, value := range values {
+		pipe.Set(ctx, key, value, rc.ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (rc *RedisCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, rc.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
 func (rc *RedisCache) Delete(ctx context.Context, key string) error {
 	return rc.client.Del(ctx, key).Err()
 }
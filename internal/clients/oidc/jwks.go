@@ -0,0 +1,252 @@
+package oidcclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// refreshInterval limits the key set fetches caused by unknown kids.
+	refreshInterval = time.Minute
+
+	maxResponseSize = 1 << 20
+)
+
+// FetchError means the provider's discovery document or key set can't be fetched.
+type FetchError struct {
+	Url string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("can`t fetch %s: %s", e.Url, e.Err.Error())
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+type verificationKey struct {
+	publicKey interface{}
+	methods   []jwt.SigningMethod
+}
+
+func (k verificationKey) accepts(method jwt.SigningMethod) bool {
+	for _, accepted := range k.methods {
+		if accepted.Alg() == method.Alg() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// providerKeys caches the key set of a provider, it is fetched again after the cache ttl
+// or when a token is signed with an unknown key.
+type providerKeys struct {
+	provider  Provider
+	mu        sync.Mutex
+	jwksUrl   string
+	keys      map[string]verificationKey
+	fetchedAt time.Time
+}
+
+func newProviderKeys(provider Provider) *providerKeys {
+	return &providerKeys{
+		provider: provider,
+		jwksUrl:  provider.JwksUrl,
+	}
+}
+
+func (p *providerKeys) key(ctx context.Context, client HTTPClient, cacheTTL time.Duration, kid string) (verificationKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	since := time.Since(p.fetchedAt)
+	key, ok := p.keys[kid]
+
+	if p.keys == nil || since > cacheTTL || (!ok && since > refreshInterval) {
+		if err := p.refresh(ctx, client); err != nil {
+			return verificationKey{}, err
+		}
+
+		key, ok = p.keys[kid]
+	}
+
+	if !ok {
+		return verificationKey{}, fmt.Errorf("unknown key id: %s", kid)
+	}
+
+	return key, nil
+}
+
+func (p *providerKeys) refresh(ctx context.Context, client HTTPClient) error {
+	if p.jwksUrl == "" {
+		jwksUrl, err := p.discover(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		p.jwksUrl = jwksUrl
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJson(ctx, client, p.jwksUrl, &jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]verificationKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.verificationKey()
+		if err != nil {
+			// keys of unsupported types don't prevent using the others
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	p.keys = keys
+	p.fetchedAt = time.Now()
+
+	return nil
+}
+
+func (p *providerKeys) discover(ctx context.Context, client HTTPClient) (string, error) {
+	configUrl := strings.TrimSuffix(p.provider.Issuer, "/") + discoveryPath
+
+	var config struct {
+		Issuer  string `json:"issuer"`
+		JwksUri string `json:"jwks_uri"`
+	}
+	if err := getJson(ctx, client, configUrl, &config); err != nil {
+		return "", err
+	}
+
+	if config.Issuer != p.provider.Issuer {
+		return "", &FetchError{Url: configUrl, Err: fmt.Errorf("issuer mismatch: %s", config.Issuer)}
+	}
+	if config.JwksUri == "" {
+		return "", &FetchError{Url: configUrl, Err: fmt.Errorf("jwks_uri is empty")}
+	}
+
+	return config.JwksUri, nil
+}
+
+func getJson(ctx context.Context, client HTTPClient, url string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return &FetchError{Url: url, Err: err}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &FetchError{Url: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &FetchError{Url: url, Err: fmt.Errorf("unexpected status: %d", resp.StatusCode)}
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(dest); err != nil {
+		return &FetchError{Url: url, Err: err}
+	}
+
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) verificationKey() (verificationKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return verificationKey{}, err
+		}
+
+		return verificationKey{
+			publicKey: &rsa.PublicKey{N: n, E: int(e.Int64())},
+			methods:   []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodRS384, jwt.SigningMethodRS512},
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var method jwt.SigningMethod
+		switch k.Crv {
+		case "P-256":
+			curve, method = elliptic.P256(), jwt.SigningMethodES256
+		case "P-384":
+			curve, method = elliptic.P384(), jwt.SigningMethodES384
+		case "P-521":
+			curve, method = elliptic.P521(), jwt.SigningMethodES512
+		default:
+			return verificationKey{}, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return verificationKey{}, fmt.Errorf("point is not on the curve")
+		}
+
+		return verificationKey{
+			publicKey: &ecdsa.PublicKey{Curve: curve, X: x, Y: y},
+			methods:   []jwt.SigningMethod{method},
+		}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBigInt(encoded string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("can`t decode key: %w", err)
+	}
+	if len(decoded) == 0 {
+		return nil, fmt.Errorf("key component is empty")
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
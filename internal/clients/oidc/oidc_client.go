@@ -0,0 +1,173 @@
+package oidcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	ErrInvalidToken    = errors.New("invalid id token")
+)
+
+// HTTPClient fetches the discovery documents and the key sets, *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Provider is an issuer the id tokens are accepted from. JwksUrl is discovered from
+// the issuer's openid-configuration when it is empty.
+type Provider struct {
+	Name      string
+	Issuer    string
+	ClientIds []string
+	JwksUrl   string
+}
+
+// Identity is the user an id token is issued for, Subject is unique within the provider.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FName         string
+	LName         string
+}
+
+type Verifier struct {
+	providers  map[string]*providerKeys
+	httpClient HTTPClient
+	cacheTTL   time.Duration
+}
+
+func NewVerifier(providers []Provider, httpClient HTTPClient, cacheTTL time.Duration) (*Verifier, error) {
+	verifier := &Verifier{
+		providers:  make(map[string]*providerKeys, len(providers)),
+		httpClient: httpClient,
+		cacheTTL:   cacheTTL,
+	}
+
+	for _, provider := range providers {
+		if provider.Name == "" || provider.Issuer == "" {
+			return nil, fmt.Errorf("provider name and issuer are required")
+		}
+		if len(provider.ClientIds) == 0 {
+			return nil, fmt.Errorf("provider `%s` has no client ids", provider.Name)
+		}
+		if _, ok := verifier.providers[provider.Name]; ok {
+			return nil, fmt.Errorf("duplicated provider `%s`", provider.Name)
+		}
+
+		verifier.providers[provider.Name] = newProviderKeys(provider)
+	}
+
+	return verifier, nil
+}
+
+// Verify checks the signature and the claims of the id token issued by the provider.
+func (v *Verifier) Verify(ctx context.Context, providerName string, rawToken string) (*Identity, error) {
+	provider, ok := v.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, err := provider.key(ctx, v.httpClient, v.cacheTTL, kid)
+		if err != nil {
+			return nil, err
+		}
+		if !key.accepts(token.Method) {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+
+		return key.publicKey, nil
+	})
+	if err != nil {
+		// an unavailable provider doesn't make the token invalid
+		if validationErr, ok := err.(*jwt.ValidationError); ok {
+			if fetchErr, ok := validationErr.Inner.(*FetchError); ok {
+				return nil, fetchErr
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	if err := provider.checkClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err.Error())
+	}
+
+	identity := &Identity{
+		Provider: providerName,
+		Subject:  claims["sub"].(string),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.FName, _ = claims["given_name"].(string)
+	identity.LName, _ = claims["family_name"].(string)
+
+	// some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = strings.EqualFold(verified, "true")
+	}
+
+	return identity, nil
+}
+
+// checkClaims is called after jwt-go has checked exp, iat and nbf.
+func (p *providerKeys) checkClaims(claims jwt.MapClaims) error {
+	if iss, _ := claims["iss"].(string); iss != p.provider.Issuer {
+		return fmt.Errorf("unexpected issuer: %s", iss)
+	}
+	if _, ok := claims["exp"]; !ok {
+		return fmt.Errorf("token has no expiration")
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return fmt.Errorf("token has no subject")
+	}
+
+	if !p.acceptsAudience(claims["aud"]) {
+		return fmt.Errorf("unexpected audience")
+	}
+	if azp, ok := claims["azp"].(string); ok && !p.acceptsClient(azp) {
+		return fmt.Errorf("unexpected authorized party: %s", azp)
+	}
+
+	return nil
+}
+
+func (p *providerKeys) acceptsAudience(aud interface{}) bool {
+	switch aud := aud.(type) {
+	case string:
+		return p.acceptsClient(aud)
+	case []interface{}:
+		for _, item := range aud {
+			if clientId, ok := item.(string); ok && p.acceptsClient(clientId) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *providerKeys) acceptsClient(clientId string) bool {
+	for _, accepted := range p.provider.ClientIds {
+		if accepted == clientId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,225 @@
+package oidcclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	testProvider = "test"
+	testClientId = "client-id"
+)
+
+// fakeProvider serves the discovery document and the key set of an identity provider,
+// the keys can be rotated while the verifier is running.
+type fakeProvider struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	keys   map[string]*rsa.PrivateKey
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+
+	fp := &fakeProvider{keys: map[string]*rsa.PrivateKey{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   fp.issuer(),
+			"jwks_uri": fp.issuer() + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fp.mu.Lock()
+		defer fp.mu.Unlock()
+
+		keys := make([]jwk, 0, len(fp.keys))
+		for kid, key := range fp.keys {
+			keys = append(keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+
+	fp.server = httptest.NewServer(mux)
+	t.Cleanup(fp.server.Close)
+
+	return fp
+}
+
+func (fp *fakeProvider) issuer() string {
+	return fp.server.URL
+}
+
+// rotate replaces the key set with a single new key.
+func (fp *fakeProvider) rotate(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("can`t generate key: %v", err)
+	}
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	fp.keys = map[string]*rsa.PrivateKey{kid: key}
+
+	return key
+}
+
+func (fp *fakeProvider) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            fp.issuer(),
+		"aud":            testClientId,
+		"sub":            "subject",
+		"email":          "user@example.com",
+		"email_verified": "true",
+		"given_name":     "First",
+		"family_name":    "Last",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func newTestVerifier(t *testing.T, fp *fakeProvider) *Verifier {
+	t.Helper()
+
+	verifier, err := NewVerifier([]Provider{{
+		Name:      testProvider,
+		Issuer:    fp.issuer(),
+		ClientIds: []string{testClientId},
+	}}, fp.server.Client(), time.Hour)
+	if err != nil {
+		t.Fatalf("can`t create verifier: %v", err)
+	}
+
+	return verifier
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("can`t sign token: %v", err)
+	}
+
+	return signed
+}
+
+func TestVerifyClaims(t *testing.T) {
+	fp := newFakeProvider(t)
+	key := fp.rotate(t, "kid-1")
+	verifier := newTestVerifier(t, fp)
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+		valid  bool
+	}{
+		{name: "valid", modify: func(jwt.MapClaims) {}, valid: true},
+		{name: "audience list", modify: func(c jwt.MapClaims) { c["aud"] = []string{"other", testClientId} }, valid: true},
+		{name: "foreign issuer", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "foreign audience", modify: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "foreign authorized party", modify: func(c jwt.MapClaims) { c["azp"] = "other" }},
+		{name: "no audience", modify: func(c jwt.MapClaims) { delete(c, "aud") }},
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := fp.claims()
+			tt.modify(claims)
+
+			identity, err := verifier.Verify(context.Background(), testProvider, sign(t, jwt.SigningMethodRS256, "kid-1", key, claims))
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if identity.Subject != "subject" || identity.Email != "user@example.com" || !identity.EmailVerified {
+				t.Errorf("Verify identity = %+v", identity)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyRotation(t *testing.T) {
+	fp := newFakeProvider(t)
+	oldKey := fp.rotate(t, "kid-1")
+	verifier := newTestVerifier(t, fp)
+
+	if _, err := verifier.Verify(context.Background(), testProvider, sign(t, jwt.SigningMethodRS256, "kid-1", oldKey, fp.claims())); err != nil {
+		t.Fatalf("Verify with the first key: %v", err)
+	}
+
+	newKey := fp.rotate(t, "kid-2")
+	newToken := sign(t, jwt.SigningMethodRS256, "kid-2", newKey, fp.claims())
+
+	// unknown kids don't refetch the key set more often than refreshInterval
+	if _, err := verifier.Verify(context.Background(), testProvider, newToken); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify right after the rotation err = %v, want ErrInvalidToken", err)
+	}
+
+	verifier.providers[testProvider].fetchedAt = time.Now().Add(-2 * refreshInterval)
+
+	if _, err := verifier.Verify(context.Background(), testProvider, newToken); err != nil {
+		t.Fatalf("Verify with the rotated key: %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), testProvider, sign(t, jwt.SigningMethodRS256, "kid-1", oldKey, fp.claims())); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify with the removed key err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestVerifyAlgMismatch(t *testing.T) {
+	fp := newFakeProvider(t)
+	key := fp.rotate(t, "kid-1")
+	verifier := newTestVerifier(t, fp)
+
+	// the public key used as a hmac secret must not be accepted
+	hmacSecret := key.PublicKey.N.Bytes()
+	if _, err := verifier.Verify(context.Background(), testProvider, sign(t, jwt.SigningMethodHS256, "kid-1", hmacSecret, fp.claims())); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify of hs256 token err = %v, want ErrInvalidToken", err)
+	}
+
+	if _, err := verifier.Verify(context.Background(), testProvider, sign(t, jwt.SigningMethodRS512, "kid-1", key, fp.claims())); err != nil {
+		t.Fatalf("Verify of rs512 token: %v", err)
+	}
+}
+
+func TestVerifyUnknownProvider(t *testing.T) {
+	fp := newFakeProvider(t)
+	key := fp.rotate(t, "kid-1")
+	verifier := newTestVerifier(t, fp)
+
+	if _, err := verifier.Verify(context.Background(), "other", sign(t, jwt.SigningMethodRS256, "kid-1", key, fp.claims())); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("Verify err = %v, want ErrUnknownProvider", err)
+	}
+}
@@ -8,13 +8,23 @@ import (
 )
 
 type Config struct {
-	Env      string   `yaml:"env" env-default:"local"`
-	GRpc     GRPC     `yaml:"grpc" env-required:"true"`
-	Storage  Storage  `yaml:"storage" env-required:"true"`
-	JWT      JWT      `yaml:"jwt" env-required:"true"`
-	Minio    Minio    `yaml:"minio" env-required:"true"`
-	Redis    Redis    `yaml:"redis" env-required:"true"`
-	RabbitMq RabbitMq `yaml:"rabbitmq" env-required:"true"`
+	Env           string        `yaml:"env" env-default:"local"`
+	GRpc          GRPC          `yaml:"grpc" env-required:"true"`
+	Storage       Storage       `yaml:"storage" env-required:"true"`
+	JWT           JWT           `yaml:"jwt" env-required:"true"`
+	Password      Password      `yaml:"password"`
+	Email         Email         `yaml:"email"`
+	Mfa           Mfa           `yaml:"mfa"`
+	Lockout       Lockout       `yaml:"lockout"`
+	Register      Register      `yaml:"register"`
+	MagicLink     MagicLink     `yaml:"magic_link"`
+	Oidc          Oidc          `yaml:"oidc"`
+	Impersonation Impersonation `yaml:"impersonation"`
+	Username      Username      `yaml:"username"`
+	Users         Users         `yaml:"users"`
+	Minio         Minio         `yaml:"minio" env-required:"true"`
+	Redis         Redis         `yaml:"redis" env-required:"true"`
+	RabbitMq      RabbitMq      `yaml:"rabbitmq" env-required:"true"`
 }
 
 type Minio struct {
@@ -24,14 +34,140 @@ type Minio struct {
 	Bucket    string `yaml:"bucket" env-required:"true"`
 }
 
+// GRPC ClientIpMdKey names the metadata key the gateway puts the client address into,
+// the address of the connected peer is used when it is empty.
 type GRPC struct {
-	Port    int           `yaml:"port" env-default:"4041"`
-	Timeout time.Duration `yaml:"timeout" env-default:"4s"`
+	Port          int           `yaml:"port" env-default:"4041"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"4s"`
+	ClientIpMdKey string        `yaml:"client_ip_md_key" env-default:""`
 }
 
 type JWT struct {
-	TokenTTL    time.Duration `yaml:"token_ttl" env-default:"1h"`
-	TokenSecret string        `yaml:"token_secret" env-default:"secret"`
+	TokenTTL        time.Duration `yaml:"token_ttl" env-default:"1h"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	TokenSecret     string        `yaml:"token_secret" env-default:"secret"`
+	Issuer          string        `yaml:"issuer" env-default:"blog-user-service"`
+	Audience        string        `yaml:"audience" env-default:"blog"`
+	ReissueWindow   time.Duration `yaml:"reissue_window" env-default:"10m"`
+	ActiveKid       string        `yaml:"active_kid" env-default:""`
+	Keys            []JWTKey      `yaml:"keys"`
+}
+
+// JWTKey is a signing key loaded from pem files. TokenSecret (HS256) is used only when no keys are configured,
+// keys without private_key_path are kept to verify tokens issued before a rotation.
+type JWTKey struct {
+	Kid            string `yaml:"kid" env-required:"true"`
+	Algorithm      string `yaml:"alg" env-required:"true"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	PublicKeyPath  string `yaml:"public_key_path"`
+}
+
+type Password struct {
+	ResetTokenTTL time.Duration   `yaml:"reset_token_ttl" env-default:"1h"`
+	Policy        PasswordPolicy  `yaml:"policy"`
+	Hashing       PasswordHashing `yaml:"hashing"`
+}
+
+// PasswordHashing selects the algorithm new password hashes are produced with. Hashes of the other algorithm
+// and hashes with outdated parameters are still accepted and replaced on the next successful login.
+type PasswordHashing struct {
+	Algorithm  string         `yaml:"algorithm" env-default:"argon2id"`
+	Argon2id   Argon2idParams `yaml:"argon2id"`
+	BcryptCost int            `yaml:"bcrypt_cost" env-default:"10"`
+}
+
+type Argon2idParams struct {
+	Memory      uint32 `yaml:"memory" env-default:"65536"`
+	Iterations  uint32 `yaml:"iterations" env-default:"3"`
+	Parallelism uint8  `yaml:"parallelism" env-default:"2"`
+	SaltLength  uint32 `yaml:"salt_length" env-default:"16"`
+	KeyLength   uint32 `yaml:"key_length" env-default:"32"`
+}
+
+// PasswordPolicy is checked for every new password. MaxLength defaults to the bcrypt input limit.
+// BreachedListPath is a directory of HIBP range files, the breached passwords check is off without it.
+type PasswordPolicy struct {
+	MinLength            int    `yaml:"min_length" env-default:"8"`
+	MaxLength            int    `yaml:"max_length" env-default:"72"`
+	RequireUpper         bool   `yaml:"require_upper" env-default:"true"`
+	RequireLower         bool   `yaml:"require_lower" env-default:"true"`
+	RequireDigit         bool   `yaml:"require_digit" env-default:"true"`
+	RequireSpecial       bool   `yaml:"require_special" env-default:"false"`
+	DisallowPersonalInfo bool   `yaml:"disallow_personal_info" env-default:"true"`
+	BreachedListPath     string `yaml:"breached_list_path" env-default:""`
+	BreachedMinCount     int    `yaml:"breached_min_count" env-default:"1"`
+}
+
+// Email configures verification links. With verification_required users can't log in until the email is verified,
+// otherwise it is only reported with the email_verified claim.
+type Email struct {
+	VerificationTokenTTL time.Duration `yaml:"verification_token_ttl" env-default:"24h"`
+	VerificationLinkBase string        `yaml:"verification_link_base" env-default:"http://localhost:3000/verify-email?token="`
+	VerificationRequired bool          `yaml:"verification_required" env-default:"false"`
+	ChangeTokenTTL       time.Duration `yaml:"change_token_ttl" env-default:"1h"`
+	ChangeLinkBase       string        `yaml:"change_link_base" env-default:"http://localhost:3000/confirm-email?token="`
+}
+
+// Mfa configures totp second factor. EncryptionKey is a base64 encoded 32 bytes key the totp secrets are encrypted with,
+// it is a secret and is read from MFA_ENCRYPTION_KEY outside of local runs.
+type Mfa struct {
+	Issuer        string        `yaml:"issuer" env-default:"blog-user-service"`
+	ChallengeTTL  time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+	EncryptionKey string        `yaml:"encryption_key" env:"MFA_ENCRYPTION_KEY" env-required:"true"`
+}
+
+// Lockout configures the login brute-force protection, see services.LockoutOptions.
+type Lockout struct {
+	MaxEmailAttempts int64         `yaml:"max_email_attempts" env-default:"5"`
+	MaxIpAttempts    int64         `yaml:"max_ip_attempts" env-default:"0"`
+	MaxMfaAttempts   int64         `yaml:"max_mfa_attempts" env-default:"5"`
+	Window           time.Duration `yaml:"window" env-default:"15m"`
+	Duration         time.Duration `yaml:"duration" env-default:"15m"`
+	BaseDelay        time.Duration `yaml:"base_delay" env-default:"100ms"`
+	MaxDelay         time.Duration `yaml:"max_delay" env-default:"3s"`
+}
+
+// Register with silent enabled doesn't reveal registered emails: registering an existing email notifies its owner
+// instead of returning a conflict, and no tokens are issued on registration, users log in after it.
+type Register struct {
+	Silent bool `yaml:"silent" env-default:"false"`
+}
+
+// MagicLink configures the passwordless login, with sign_up the link sent to a not registered email creates the account.
+type MagicLink struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"15m"`
+	LinkBase string        `yaml:"link_base" env-default:"http://localhost:3000/magic-link?token="`
+	SignUp   bool          `yaml:"sign_up" env-default:"false"`
+}
+
+// Oidc lists the providers whose id tokens are exchanged for our tokens. The providers' key sets are cached
+// for jwks_cache_ttl, jwks_url is discovered from the issuer when it is empty.
+type Oidc struct {
+	JwksCacheTTL time.Duration  `yaml:"jwks_cache_ttl" env-default:"1h"`
+	HttpTimeout  time.Duration  `yaml:"http_timeout" env-default:"5s"`
+	Providers    []OidcProvider `yaml:"providers"`
+}
+
+type OidcProvider struct {
+	Name      string   `yaml:"name" env-required:"true"`
+	Issuer    string   `yaml:"issuer" env-required:"true"`
+	ClientIds []string `yaml:"client_ids" env-required:"true"`
+	JwksUrl   string   `yaml:"jwks_url"`
+}
+
+// Impersonation configures the tokens admins act as users with, they are not reissued and have no refresh token.
+type Impersonation struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"15m"`
+}
+
+// Username configures renames, the former username redirects to the user and is held for RedirectTTL.
+type Username struct {
+	RedirectTTL time.Duration `yaml:"redirect_ttl" env-default:"720h"`
+}
+
+// Users limits the number of ids of a GetUsers batch.
+type Users struct {
+	BatchLimit int `yaml:"batch_limit" env-default:"100"`
 }
 
 type Redis struct {
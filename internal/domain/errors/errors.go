@@ -12,6 +12,8 @@ var (
 	ErrForbidden           = errors.New("forbidden")
 	ErrUnauthorized        = errors.New("unauthorized")
 	ErrConflict            = errors.New("conflict")
+	ErrTooManyRequests     = errors.New("too many requests")
+	ErrAborted             = errors.New("aborted")
 	ErrInternalServerError = errors.New("internal server error")
 )
 
@@ -0,0 +1,29 @@
+package repositories_transfer
+
+import (
+	"github.com/google/uuid"
+)
+
+type ApiKeyFieldTarget string
+
+const (
+	ApiKeyIdCondition             ApiKeyFieldTarget = "id"
+	ApiKeyPrefixCondition         ApiKeyFieldTarget = "prefix"
+	ApiKeyServiceAccountCondition ApiKeyFieldTarget = "service_account"
+)
+
+type CreateApiKeyInfo struct {
+	ServiceAccount string
+	Prefix         string
+	SecretHash     []byte
+	Scopes         []string
+	CreatedBy      uuid.NullUUID
+}
+
+type GetApiKeysInfo struct {
+	Condition map[ApiKeyFieldTarget]any
+}
+
+type RevokeApiKeysInfo struct {
+	Condition map[ApiKeyFieldTarget]any
+}
@@ -0,0 +1,14 @@
+package repositories_transfer
+
+import (
+	"github.com/google/uuid"
+)
+
+type CreateAuditEntryInfo struct {
+	Action       string
+	ActorId      uuid.NullUUID
+	TargetUserId uuid.NullUUID
+	TokenId      uuid.NullUUID
+	Reason       string
+	IpAddress    string
+}
@@ -0,0 +1,28 @@
+package repositories_transfer
+
+import (
+	"github.com/google/uuid"
+)
+
+type ExternalIdentityFieldTarget string
+
+const (
+	ExternalIdentityUserIdCondition   ExternalIdentityFieldTarget = "user_id"
+	ExternalIdentityProviderCondition ExternalIdentityFieldTarget = "provider"
+	ExternalIdentitySubjectCondition  ExternalIdentityFieldTarget = "subject"
+)
+
+type CreateExternalIdentityInfo struct {
+	UserId   uuid.UUID
+	Provider string
+	Subject  string
+	Email    string
+}
+
+type GetExternalIdentityInfo struct {
+	Condition map[ExternalIdentityFieldTarget]any
+}
+
+type DeleteExternalIdentitiesInfo struct {
+	Condition map[ExternalIdentityFieldTarget]any
+}
@@ -0,0 +1,28 @@
+package repositories_transfer
+
+import "time"
+
+type LoginAttemptsTarget string
+
+const (
+	EmailAttemptsTarget LoginAttemptsTarget = "email"
+	IpAttemptsTarget    LoginAttemptsTarget = "ip"
+	// MfaChallengeAttemptsTarget counts the wrong codes sent with one mfa challenge.
+	MfaChallengeAttemptsTarget LoginAttemptsTarget = "mfa-challenge"
+)
+
+// LoginAttemptsKey is the subject failed logins are counted for.
+type LoginAttemptsKey struct {
+	Target LoginAttemptsTarget
+	Value  string
+}
+
+type RegisterLoginFailureInfo struct {
+	Key    LoginAttemptsKey
+	Window time.Duration
+}
+
+type LockLoginInfo struct {
+	Key      LoginAttemptsKey
+	Duration time.Duration
+}
@@ -0,0 +1,45 @@
+package repositories_transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OneTimeTokenPurpose string
+
+const (
+	PasswordResetPurpose     OneTimeTokenPurpose = "password-reset"
+	EmailVerificationPurpose OneTimeTokenPurpose = "email-verification"
+	EmailChangePurpose       OneTimeTokenPurpose = "email-change"
+	MfaChallengePurpose      OneTimeTokenPurpose = "mfa-challenge"
+	MagicLinkPurpose         OneTimeTokenPurpose = "magic-link"
+)
+
+type OneTimeTokenFieldTarget string
+
+const (
+	OneTimeTokenIdCondition      OneTimeTokenFieldTarget = "id"
+	OneTimeTokenUserIdCondition  OneTimeTokenFieldTarget = "user_id"
+	OneTimeTokenPurposeCondition OneTimeTokenFieldTarget = "purpose"
+	OneTimeTokenHashCondition    OneTimeTokenFieldTarget = "token_hash"
+	OneTimeTokenEmailCondition   OneTimeTokenFieldTarget = "email"
+)
+
+// CreateOneTimeTokenInfo with an empty UserId creates a token bound to the Email only.
+type CreateOneTimeTokenInfo struct {
+	UserId  uuid.UUID
+	Purpose OneTimeTokenPurpose
+	// Email is the address the token is bound to, empty if the purpose does not need one.
+	Email     string
+	TokenHash []byte
+	ExpiresAt time.Time
+}
+
+type GetOneTimeTokenInfo struct {
+	Condition map[OneTimeTokenFieldTarget]any
+}
+
+type UseOneTimeTokensInfo struct {
+	Condition map[OneTimeTokenFieldTarget]any
+}
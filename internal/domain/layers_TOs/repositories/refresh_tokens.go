@@ -0,0 +1,32 @@
+package repositories_transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshTokenFieldTarget string
+
+const (
+	RefreshTokenIdCondition     RefreshTokenFieldTarget = "id"
+	RefreshTokenHashCondition   RefreshTokenFieldTarget = "token_hash"
+	RefreshTokenFamilyCondition RefreshTokenFieldTarget = "family_id"
+	RefreshTokenUserCondition   RefreshTokenFieldTarget = "user_id"
+)
+
+type CreateRefreshTokenInfo struct {
+	UserId    uuid.UUID
+	FamilyId  uuid.UUID
+	TokenHash []byte
+	ExpiresAt time.Time
+}
+
+type GetRefreshTokenInfo struct {
+	Condition map[RefreshTokenFieldTarget]any
+}
+
+type RevokeRefreshTokensInfo struct {
+	Condition  map[RefreshTokenFieldTarget]any
+	ReplacedBy uuid.NullUUID
+}
@@ -0,0 +1,8 @@
+package repositories_transfer
+
+import "github.com/google/uuid"
+
+type UserRoleInfo struct {
+	UserId uuid.UUID
+	Role   string
+}
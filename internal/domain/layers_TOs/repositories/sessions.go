@@ -0,0 +1,29 @@
+package repositories_transfer
+
+import (
+	"github.com/google/uuid"
+)
+
+type SessionFieldTarget string
+
+const (
+	SessionIdCondition     SessionFieldTarget = "id"
+	SessionUserIdCondition SessionFieldTarget = "user_id"
+)
+
+type CreateSessionInfo struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	UserAgent string
+	IpAddress string
+}
+
+type GetSessionsInfo struct {
+	Condition map[SessionFieldTarget]any
+}
+
+// RevokeSessionsInfo revokes the active sessions matching Condition except the one with ExceptId.
+type RevokeSessionsInfo struct {
+	Condition map[SessionFieldTarget]any
+	ExceptId  uuid.NullUUID
+}
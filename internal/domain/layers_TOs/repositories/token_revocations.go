@@ -0,0 +1,18 @@
+package repositories_transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevokeTokenInfo struct {
+	Jti       uuid.UUID
+	UserId    uuid.UUID
+	ExpiresAt time.Time
+}
+
+type RevokeUserTokensInfo struct {
+	UserId        uuid.UUID
+	RevokedBefore time.Time
+}
@@ -0,0 +1,23 @@
+package repositories_transfer
+
+import "github.com/google/uuid"
+
+type SaveTotpSecretInfo struct {
+	UserId          uuid.UUID
+	EncryptedSecret []byte
+}
+
+type UseTotpStepInfo struct {
+	UserId uuid.UUID
+	Step   int64
+}
+
+type ReplaceRecoveryCodesInfo struct {
+	UserId     uuid.UUID
+	CodeHashes [][]byte
+}
+
+type UseRecoveryCodeInfo struct {
+	UserId   uuid.UUID
+	CodeHash []byte
+}
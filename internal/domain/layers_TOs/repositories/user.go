@@ -23,9 +23,16 @@ type GetUserInfo struct {
 	Condition map[UserFieldTarget]interface{}
 }
 
+// UpdateUserInfo with ExpectedVersion updates the user only when its version has not changed, 0 skips the check.
 type UpdateUserInfo struct {
-	Id         uuid.UUID
-	UpdateInfo map[string]any
+	Id              uuid.UUID
+	UpdateInfo      map[string]any
+	ExpectedVersion int64
+}
+
+type UpdatePasswordInfo struct {
+	Id       uuid.UUID
+	PassHash []byte
 }
 
 type CreateUserInfo struct {
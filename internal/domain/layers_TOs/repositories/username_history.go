@@ -0,0 +1,13 @@
+package repositories_transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateUsernameHistoryInfo struct {
+	Username  string
+	UserId    uuid.UUID
+	ExpiresAt time.Time
+}
@@ -0,0 +1,64 @@
+package services_transfer
+
+import (
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateApiKeyInfo struct {
+	ServiceAccount string   `validate:"required,max=64"`
+	Scopes         []string `validate:"dive,required"`
+	CreatedBy      uuid.NullUUID
+}
+
+// CreateApiKeyResult carries the whole key, it is not stored and can't be shown again.
+type CreateApiKeyResult struct {
+	Id     uuid.UUID
+	Prefix string
+	Key    string
+}
+
+type ListApiKeysInfo struct {
+	ServiceAccount string `validate:"max=64"`
+}
+
+type RevokeApiKeyInfo struct {
+	Id uuid.UUID `validate:"required,uuid"`
+}
+
+type ApiKeyResult struct {
+	Id             uuid.UUID
+	ServiceAccount string
+	Prefix         string
+	Scopes         []string
+	LastUsedAt     time.Time
+	CreatedAt      time.Time
+}
+
+type ListApiKeysResult struct {
+	ApiKeys []ApiKeyResult
+}
+
+func GetApiKeyResultFromModel(apiKey *models.ApiKey) *ApiKeyResult {
+	return &ApiKeyResult{
+		Id:             apiKey.Id,
+		ServiceAccount: apiKey.ServiceAccount,
+		Prefix:         apiKey.Prefix,
+		Scopes:         apiKey.Scopes,
+		LastUsedAt:     apiKey.LastUsedAt.Time,
+		CreatedAt:      apiKey.CreatedDate,
+	}
+}
+
+func GetListApiKeysResultFromModels(apiKeys []*models.ApiKey) *ListApiKeysResult {
+	results := make([]ApiKeyResult, 0, len(apiKeys))
+
+	for _, apiKey := range apiKeys {
+		results = append(results, *GetApiKeyResultFromModel(apiKey))
+	}
+
+	return &ListApiKeysResult{
+		ApiKeys: results,
+	}
+}
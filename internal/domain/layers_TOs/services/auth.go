@@ -1,21 +1,119 @@
 package services_transfer
 
+import (
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	"github.com/google/uuid"
+	"time"
+)
+
 type RegisterInfo struct {
 	Email    string `validate:"required,email"`
-	Password string `validate:"required,min=8"`
+	Password string `validate:"required,password"`
 	FName    string `validate:"required,alpha"`
 	LName    string `validate:"required,alpha"`
+	Client   ClientInfo
 }
 
 type LoginInfo struct {
 	Email    string `validate:"required,email"`
 	Password string `validate:"required,min=8"`
+	Client   ClientInfo
 }
 
 type CheckAuthInfo struct {
 	AccessToken string
 }
 
+type RefreshTokenInfo struct {
+	RefreshToken string `validate:"required"`
+}
+
+type LogoutInfo struct {
+	RefreshToken string `validate:"required"`
+	AllSessions  bool
+}
+
+type RevokeTokenInfo struct {
+	AccessToken string `validate:"required"`
+}
+
+type RevokeUserTokensInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+}
+
+type UnlockAccountInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+}
+
+// TokenResult has no tokens when MfaRequired is set, the login is completed with MfaToken by VerifyMfa.
 type TokenResult struct {
+	AccessToken  string
+	RefreshToken string
+	MfaRequired  bool
+	MfaToken     string
+}
+
+type ClaimsResult struct {
+	UserId        uuid.UUID
+	Email         string
+	Roles         []string
+	SessionId     uuid.UUID
+	EmailVerified bool
+	ActorId       uuid.UUID
+	IssuedAt      time.Time
+	ExpiresAt     time.Time
+}
+
+type CheckAuthResult struct {
 	AccessToken string
+	Claims      ClaimsResult
+}
+
+func GetClaimsResultFromToken(claims tokenshelper.TokenClaims) ClaimsResult {
+	return ClaimsResult{
+		UserId:        claims.Id,
+		Email:         claims.Email,
+		Roles:         claims.Roles,
+		SessionId:     claims.SessionId,
+		EmailVerified: claims.EmailVerified,
+		ActorId:       claims.ActorId,
+		IssuedAt:      claims.IssuedAt,
+		ExpiresAt:     claims.ExpiresAt,
+	}
+}
+
+type JWKResult struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+type JWKSResult struct {
+	Keys []JWKResult
+}
+
+func GetJWKSResultFromKeys(keys []tokenshelper.JWK) *JWKSResult {
+	results := make([]JWKResult, 0, len(keys))
+
+	for _, key := range keys {
+		results = append(results, JWKResult{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+
+	return &JWKSResult{
+		Keys: results,
+	}
 }
@@ -2,13 +2,16 @@ package services_transfer
 
 import "github.com/google/uuid"
 
+// UploadAvatarInfo has ExpectedVersion like UpdateUserInfo.
 type UploadAvatarInfo struct {
-	UserId uuid.UUID `validate:"required,uuid"`
-	Image  []byte    `validate:"required"`
+	UserId          uuid.UUID `validate:"required,uuid"`
+	Image           []byte    `validate:"required"`
+	ExpectedVersion int64     `validate:"gte=0"`
 }
 
 type AvatarResult struct {
 	UserId     uuid.UUID
 	Avatar     string
 	AvatarMini string
+	Version    int64
 }
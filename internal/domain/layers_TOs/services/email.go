@@ -0,0 +1,21 @@
+package services_transfer
+
+import "github.com/google/uuid"
+
+type SendVerificationEmailInfo struct {
+	Email string `validate:"required,email"`
+}
+
+type VerifyEmailInfo struct {
+	Token string `validate:"required"`
+}
+
+type RequestEmailChangeInfo struct {
+	UserId   uuid.UUID `validate:"required,uuid"`
+	NewEmail string    `validate:"required,email"`
+	Password string    `validate:"required"`
+}
+
+type ConfirmEmailChangeInfo struct {
+	Token string `validate:"required"`
+}
@@ -0,0 +1,20 @@
+package services_transfer
+
+import "github.com/google/uuid"
+
+type ExchangeExternalTokenInfo struct {
+	Provider string `validate:"required"`
+	IdToken  string `validate:"required"`
+	Client   ClientInfo
+}
+
+type LinkIdentityInfo struct {
+	UserId   uuid.UUID `validate:"required,uuid"`
+	Provider string    `validate:"required"`
+	IdToken  string    `validate:"required"`
+}
+
+type UnlinkIdentityInfo struct {
+	UserId   uuid.UUID `validate:"required,uuid"`
+	Provider string    `validate:"required"`
+}
@@ -0,0 +1,27 @@
+package services_transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonateInfo is filled from the caller, the impersonation token shares the actor's session,
+// so it stops working when the actor logs out.
+type ImpersonateInfo struct {
+	ActorId        uuid.UUID `validate:"required,uuid"`
+	ActorSessionId uuid.UUID `validate:"required,uuid"`
+	UserId         uuid.UUID `validate:"required,uuid"`
+	Reason         string    `validate:"required,max=500"`
+	Client         ClientInfo
+}
+
+type StopImpersonationInfo struct {
+	AccessToken string `validate:"required"`
+	Client      ClientInfo
+}
+
+type ImpersonationResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,10 @@
+package services_transfer
+
+type RequestMagicLinkInfo struct {
+	Email string `validate:"required,email"`
+}
+
+type RedeemMagicLinkInfo struct {
+	Token  string `validate:"required"`
+	Client ClientInfo
+}
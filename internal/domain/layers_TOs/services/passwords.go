@@ -0,0 +1,18 @@
+package services_transfer
+
+import "github.com/google/uuid"
+
+type RequestPasswordResetInfo struct {
+	Email string `validate:"required,email"`
+}
+
+type ConfirmPasswordResetInfo struct {
+	Token       string `validate:"required"`
+	NewPassword string `validate:"required,password"`
+}
+
+type ChangePasswordInfo struct {
+	UserId      uuid.UUID `validate:"required,uuid"`
+	OldPassword string    `validate:"required"`
+	NewPassword string    `validate:"required,password"`
+}
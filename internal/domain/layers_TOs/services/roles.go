@@ -0,0 +1,13 @@
+package services_transfer
+
+import "github.com/google/uuid"
+
+type GrantRoleInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+	Role   string    `validate:"required"`
+}
+
+type RevokeRoleInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+	Role   string    `validate:"required"`
+}
@@ -0,0 +1,60 @@
+package services_transfer
+
+import (
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ClientInfo describes the device a session is created from.
+type ClientInfo struct {
+	UserAgent string
+	IpAddress string
+}
+
+type ListSessionsInfo struct {
+	UserId           uuid.UUID `validate:"required,uuid"`
+	CurrentSessionId uuid.UUID
+}
+
+type RevokeSessionInfo struct {
+	UserId    uuid.UUID `validate:"required,uuid"`
+	SessionId uuid.UUID `validate:"required,uuid"`
+}
+
+type RevokeOtherSessionsInfo struct {
+	UserId           uuid.UUID `validate:"required,uuid"`
+	CurrentSessionId uuid.UUID `validate:"required,uuid"`
+}
+
+type SessionResult struct {
+	Id         uuid.UUID
+	UserAgent  string
+	IpAddress  string
+	LastSeenAt time.Time
+	CreatedAt  time.Time
+	Current    bool
+}
+
+type ListSessionsResult struct {
+	Sessions []SessionResult
+}
+
+func GetListSessionsResultFromModels(sessions []*models.Session, currentSessionId uuid.UUID) *ListSessionsResult {
+	results := make([]SessionResult, 0, len(sessions))
+
+	for _, session := range sessions {
+		results = append(results, SessionResult{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			LastSeenAt: session.LastSeenAt,
+			CreatedAt:  session.CreatedDate,
+			Current:    session.Id == currentSessionId,
+		})
+	}
+
+	return &ListSessionsResult{
+		Sessions: results,
+	}
+}
@@ -0,0 +1,35 @@
+package services_transfer
+
+import "github.com/google/uuid"
+
+type EnrollTotpInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+}
+
+type ConfirmTotpInfo struct {
+	UserId uuid.UUID `validate:"required,uuid"`
+	Code   string    `validate:"required,numeric,len=6"`
+}
+
+type DisableTotpInfo struct {
+	UserId       uuid.UUID `validate:"required,uuid"`
+	Code         string    `validate:"required_without=RecoveryCode"`
+	RecoveryCode string
+	Client       ClientInfo
+}
+
+type VerifyMfaInfo struct {
+	MfaToken     string `validate:"required"`
+	Code         string `validate:"required_without=RecoveryCode"`
+	RecoveryCode string
+	Client       ClientInfo
+}
+
+type EnrollTotpResult struct {
+	Secret          string
+	ProvisioningUri string
+}
+
+type ConfirmTotpResult struct {
+	RecoveryCodes []string
+}
@@ -9,14 +9,40 @@ import (
 type UserFieldTarget string
 
 const (
-	UserEmailUpdateTarget UserFieldTarget = "email"
 	UserFNameUpdateTarget UserFieldTarget = "fname"
 	UserLNameUpdateTarget UserFieldTarget = "lname"
+	// UserUsernameUpdateTarget renames the user, the old username redirects to the user for a grace period.
+	UserUsernameUpdateTarget UserFieldTarget = "username"
+
+	UserBioUpdateTarget      UserFieldTarget = "bio"
+	UserLinksUpdateTarget    UserFieldTarget = "links"
+	UserLocationUpdateTarget UserFieldTarget = "location"
+	// UserBirthdayUpdateTarget is a date in the models.BirthdayLayout format, an empty value clears the birthday.
+	UserBirthdayUpdateTarget           UserFieldTarget = "birthday"
+	UserBirthdayVisibilityUpdateTarget UserFieldTarget = "birthday_visibility"
+	UserPronounsUpdateTarget           UserFieldTarget = "pronouns"
+	UserCoverUpdateTarget              UserFieldTarget = "cover"
 )
 
+// UpdateUserInfo values are strings but the links, they are a []string. See UpdateFieldsFromPatch.
+// ExpectedVersion is the version of the user the client has read, 0 updates any version.
 type UpdateUserInfo struct {
-	Id           uuid.UUID               `validate:"required,uuid"`
-	UpdateFields map[UserFieldTarget]any `validate:"required,mapkeys-user-update"`
+	Id              uuid.UUID               `validate:"required,uuid"`
+	UpdateFields    map[UserFieldTarget]any `validate:"required,mapkeys-user-update,mapvalues-user-update"`
+	ExpectedVersion int64                   `validate:"gte=0"`
+}
+
+type GetUsersInfo struct {
+	Ids []uuid.UUID `validate:"required,min=1"`
+}
+
+type GetUserByUsernameInfo struct {
+	Username string `validate:"required,username"`
+}
+
+// CheckUsernameInfo is not validated with the username tag, a malformed username is reported as unavailable.
+type CheckUsernameInfo struct {
+	Username string `validate:"required,max=64"`
 }
 
 type DeleteUserInfo struct {
@@ -24,12 +50,30 @@ type DeleteUserInfo struct {
 }
 
 type UserResult struct {
-	Email      string
-	FName      string
-	LName      string
-	Avatar     string
-	AvatarMini string
-	Id         uuid.UUID
+	Email              string
+	Username           string
+	FName              string
+	LName              string
+	Avatar             string
+	AvatarMini         string
+	Bio                string
+	Links              []string
+	Location           string
+	Birthday           string
+	BirthdayVisibility models.BirthdayVisibility
+	Pronouns           string
+	Cover              string
+	Version            int64
+	Id                 uuid.UUID
+}
+
+// VisibleTo hides the private birthday from everyone but the user, viewerId is uuid.Nil for anonymous viewers.
+func (u UserResult) VisibleTo(viewerId uuid.UUID) UserResult {
+	if u.BirthdayVisibility != models.BirthdayPublic && viewerId != u.Id {
+		u.Birthday = ""
+	}
+
+	return u
 }
 
 type GetUserResult struct {
@@ -40,24 +84,65 @@ type UpdateUserResult struct {
 	User UserResult
 }
 
+// GetUsersResult has the found users in the order of the requested ids, duplicated ids are returned once.
+type GetUsersResult struct {
+	Users       []UserResult
+	NotFoundIds []uuid.UUID
+}
+
+// GetUserByUsernameResult has Redirected set when the username is a former one of the user.
+type GetUserByUsernameResult struct {
+	User       UserResult
+	Redirected bool
+}
+
+type UsernameAvailabilityResult struct {
+	Available   bool
+	Reason      string
+	Suggestions []string
+}
+
 func GetUserResultFromModel(user *models.User) UserResult {
+	birthday := ""
+	if user.Birthday.Valid {
+		birthday = user.Birthday.Time.Format(models.BirthdayLayout)
+	}
+
 	return UserResult{
-		Email:      user.Email,
-		FName:      user.FName,
-		LName:      user.LName,
-		Avatar:     user.Avatar,
-		Id:         user.Id,
-		AvatarMini: user.AvatarMin,
+		Email:              user.Email,
+		Username:           user.Username.String,
+		FName:              user.FName,
+		LName:              user.LName,
+		Avatar:             user.Avatar,
+		Id:                 user.Id,
+		AvatarMini:         user.AvatarMin,
+		Bio:                user.Bio,
+		Links:              user.Links,
+		Location:           user.Location,
+		Birthday:           birthday,
+		BirthdayVisibility: user.BirthdayVisibility,
+		Pronouns:           user.Pronouns,
+		Cover:              user.Cover,
+		Version:            user.Version,
 	}
 }
 
 func ConvertUserResToProto(user *UserResult) *usersv1.User {
 	return &usersv1.User{
-		Id:        user.Id.String(),
-		Email:     user.Email,
-		Fname:     user.FName,
-		Lname:     user.LName,
-		Avatar:    user.Avatar,
-		AvatarMin: user.AvatarMini,
+		Id:                 user.Id.String(),
+		Email:              user.Email,
+		Username:           user.Username,
+		Fname:              user.FName,
+		Lname:              user.LName,
+		Avatar:             user.Avatar,
+		AvatarMin:          user.AvatarMini,
+		Bio:                user.Bio,
+		Links:              user.Links,
+		Location:           user.Location,
+		Birthday:           user.Birthday,
+		Pronouns:           user.Pronouns,
+		Cover:              user.Cover,
+		Version:            user.Version,
+		BirthdayVisibility: string(user.BirthdayVisibility),
 	}
 }
@@ -0,0 +1,99 @@
+package services_transfer
+
+import (
+	"errors"
+	"fmt"
+	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"maps"
+	"strings"
+)
+
+var (
+	ErrEmptyUpdateMask   = errors.New("update mask is empty")
+	ErrUnknownUpdatePath = errors.New("unknown update mask paths")
+)
+
+// userPatchPaths pick the fields set by the update mask paths from the patch.
+var userPatchPaths = map[string]func(patch *usersv1.UserPatch) map[UserFieldTarget]any{
+	"fname": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserFNameUpdateTarget: patch.GetFname()}
+	},
+	"lname": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserLNameUpdateTarget: patch.GetLname()}
+	},
+	"username": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserUsernameUpdateTarget: patch.GetUsername()}
+	},
+	"bio": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserBioUpdateTarget: patch.GetBio()}
+	},
+	"links": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserLinksUpdateTarget: append([]string{}, patch.GetLinks()...)}
+	},
+	"location": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserLocationUpdateTarget: patch.GetLocation()}
+	},
+	"birthday": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{
+			UserBirthdayUpdateTarget:           patch.GetBirthday().GetDate(),
+			UserBirthdayVisibilityUpdateTarget: patch.GetBirthday().GetVisibility(),
+		}
+	},
+	"birthday.date": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserBirthdayUpdateTarget: patch.GetBirthday().GetDate()}
+	},
+	"birthday.visibility": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserBirthdayVisibilityUpdateTarget: patch.GetBirthday().GetVisibility()}
+	},
+	"pronouns": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserPronounsUpdateTarget: patch.GetPronouns()}
+	},
+	"cover": func(patch *usersv1.UserPatch) map[UserFieldTarget]any {
+		return map[UserFieldTarget]any{UserCoverUpdateTarget: patch.GetCover()}
+	},
+}
+
+// UpdateFieldsFromPatch picks the fields listed in the update mask from the patch, the links are a []string.
+// A listed field left empty in the patch is cleared, unknown paths are rejected.
+func UpdateFieldsFromPatch(patch *usersv1.UserPatch, paths []string) (map[UserFieldTarget]any, error) {
+	if len(paths) == 0 {
+		return nil, ErrEmptyUpdateMask
+	}
+
+	fields := make(map[UserFieldTarget]any)
+	unknown := make([]string, 0)
+	for _, path := range paths {
+		pick, ok := userPatchPaths[path]
+		if !ok {
+			unknown = append(unknown, path)
+			continue
+		}
+		maps.Copy(fields, pick(patch))
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownUpdatePath, strings.Join(unknown, ", "))
+	}
+
+	return fields, nil
+}
+
+// UpdateFieldsFromMap adapts the update data of the first UpdateUser version to UpdateFieldsFromPatch,
+// the links are a json array there. The keys are checked by the validator.
+func UpdateFieldsFromMap(data map[string]string) (map[UserFieldTarget]any, error) {
+	fields := make(map[UserFieldTarget]any, len(data))
+	for k, v := range data {
+		fields[UserFieldTarget(k)] = v
+	}
+
+	if links, ok := data[string(UserLinksUpdateTarget)]; ok {
+		parsed, err := models.ParseProfileLinks(links)
+		if err != nil {
+			return nil, fmt.Errorf("links must be a json array: %w", err)
+		}
+		fields[UserLinksUpdateTarget] = []string(parsed)
+	}
+
+	return fields, nil
+}
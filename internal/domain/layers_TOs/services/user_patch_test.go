@@ -0,0 +1,137 @@
+package services_transfer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
+)
+
+func TestUpdateFieldsFromPatch(t *testing.T) {
+	patch := &usersv1.UserPatch{
+		Fname:    "First",
+		Lname:    "Last",
+		Bio:      "bio",
+		Links:    []string{"https://example.com"},
+		Birthday: &usersv1.BirthdayPatch{Date: "2000-01-02", Visibility: "public"},
+		Cover:    "https://example.com/cover.png",
+	}
+
+	tests := []struct {
+		name   string
+		patch  *usersv1.UserPatch
+		paths  []string
+		fields map[UserFieldTarget]any
+	}{
+		{
+			name:   "listed fields only",
+			patch:  patch,
+			paths:  []string{"fname", "bio"},
+			fields: map[UserFieldTarget]any{UserFNameUpdateTarget: "First", UserBioUpdateTarget: "bio"},
+		},
+		{
+			name:   "links",
+			patch:  patch,
+			paths:  []string{"links"},
+			fields: map[UserFieldTarget]any{UserLinksUpdateTarget: []string{"https://example.com"}},
+		},
+		{
+			name:  "birthday with visibility",
+			patch: patch,
+			paths: []string{"birthday"},
+			fields: map[UserFieldTarget]any{
+				UserBirthdayUpdateTarget:           "2000-01-02",
+				UserBirthdayVisibilityUpdateTarget: "public",
+			},
+		},
+		{
+			name:   "birthday visibility only",
+			patch:  patch,
+			paths:  []string{"birthday.visibility"},
+			fields: map[UserFieldTarget]any{UserBirthdayVisibilityUpdateTarget: "public"},
+		},
+		{
+			name:  "listed fields missing in the patch are cleared",
+			patch: &usersv1.UserPatch{Fname: "First"},
+			paths: []string{"bio", "location", "cover", "birthday.date", "links"},
+			fields: map[UserFieldTarget]any{
+				UserBioUpdateTarget:      "",
+				UserLocationUpdateTarget: "",
+				UserCoverUpdateTarget:    "",
+				UserBirthdayUpdateTarget: "",
+				UserLinksUpdateTarget:    []string{},
+			},
+		},
+		{
+			name:   "no patch clears the listed fields",
+			patch:  nil,
+			paths:  []string{"pronouns"},
+			fields: map[UserFieldTarget]any{UserPronounsUpdateTarget: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := UpdateFieldsFromPatch(tt.patch, tt.paths)
+			if err != nil {
+				t.Fatalf("UpdateFieldsFromPatch: %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("UpdateFieldsFromPatch = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestUpdateFieldsFromPatchLinksAreCopied(t *testing.T) {
+	patch := &usersv1.UserPatch{Links: []string{"https://example.com"}}
+
+	fields, err := UpdateFieldsFromPatch(patch, []string{"links"})
+	if err != nil {
+		t.Fatalf("UpdateFieldsFromPatch: %v", err)
+	}
+
+	patch.Links[0] = "https://changed.example.com"
+	if links := fields[UserLinksUpdateTarget].([]string); links[0] != "https://example.com" {
+		t.Errorf("links = %v, want them not to share the patch array", links)
+	}
+}
+
+func TestUpdateFieldsFromPatchInvalidMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr error
+		unknown []string
+	}{
+		{name: "no mask", paths: nil, wantErr: ErrEmptyUpdateMask},
+		{name: "empty mask", paths: []string{}, wantErr: ErrEmptyUpdateMask},
+		{name: "unknown path", paths: []string{"email"}, wantErr: ErrUnknownUpdatePath, unknown: []string{"email"}},
+		{
+			name:    "unknown among known paths",
+			paths:   []string{"fname", "pass_hash", "birthday.year"},
+			wantErr: ErrUnknownUpdatePath,
+			unknown: []string{"pass_hash", "birthday.year"},
+		},
+		{name: "column name instead of path", paths: []string{"birthday_visibility"}, wantErr: ErrUnknownUpdatePath, unknown: []string{"birthday_visibility"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := UpdateFieldsFromPatch(&usersv1.UserPatch{Fname: "First"}, tt.paths)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateFieldsFromPatch err = %v, want %v", err, tt.wantErr)
+			}
+			if fields != nil {
+				t.Errorf("UpdateFieldsFromPatch fields = %v, want none", fields)
+			}
+			for _, path := range tt.unknown {
+				if !strings.Contains(err.Error(), path) {
+					t.Errorf("UpdateFieldsFromPatch err = %v, want %s named", err, path)
+				}
+			}
+		})
+	}
+}
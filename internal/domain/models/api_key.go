@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ApiKey is a credential of a service account, only the hash of its secret part is stored.
+type ApiKey struct {
+	Id             uuid.UUID      `db:"id"`
+	ServiceAccount string         `db:"service_account"`
+	Prefix         string         `db:"prefix"`
+	SecretHash     []byte         `db:"secret_hash"`
+	Scopes         pq.StringArray `db:"scopes"`
+	CreatedBy      uuid.NullUUID  `db:"created_by"`
+	LastUsedAt     sql.NullTime   `db:"last_used_at"`
+	RevokedAt      sql.NullTime   `db:"revoked_at"`
+	CreatedDate    time.Time      `db:"created_date"`
+}
+
+func NewApiKeyModel(serviceAccount string, prefix string, secretHash []byte, scopes []string, createdBy uuid.NullUUID) *ApiKey {
+	return &ApiKey{
+		Id:             uuid.New(),
+		ServiceAccount: serviceAccount,
+		Prefix:         prefix,
+		SecretHash:     secretHash,
+		Scopes:         scopes,
+		CreatedBy:      createdBy,
+	}
+}
+
+func (k *ApiKey) IsRevoked() bool {
+	return k.RevokedAt.Valid
+}
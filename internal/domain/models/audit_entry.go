@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonationStopped = "impersonation.stopped"
+)
+
+// AuditEntry records a privileged action. It has no foreign keys, the entries outlive the users.
+type AuditEntry struct {
+	Id           uuid.UUID     `db:"id"`
+	Action       string        `db:"action"`
+	ActorId      uuid.NullUUID `db:"actor_id"`
+	TargetUserId uuid.NullUUID `db:"target_user_id"`
+	TokenId      uuid.NullUUID `db:"token_id"`
+	Reason       string        `db:"reason"`
+	IpAddress    string        `db:"ip_address"`
+	CreatedDate  time.Time     `db:"created_date"`
+}
+
+func NewAuditEntryModel(action string, actorId uuid.NullUUID, targetUserId uuid.NullUUID, tokenId uuid.NullUUID, reason string, ipAddress string) *AuditEntry {
+	return &AuditEntry{
+		Id:           uuid.New(),
+		Action:       action,
+		ActorId:      actorId,
+		TargetUserId: targetUserId,
+		TokenId:      tokenId,
+		Reason:       reason,
+		IpAddress:    ipAddress,
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity links an account of an OIDC provider to a user, a user has at most one identity per provider.
+type ExternalIdentity struct {
+	Id          uuid.UUID `db:"id"`
+	UserId      uuid.UUID `db:"user_id"`
+	Provider    string    `db:"provider"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+func NewExternalIdentityModel(userId uuid.UUID, provider string, subject string, email string) *ExternalIdentity {
+	return &ExternalIdentity{
+		Id:       uuid.New(),
+		UserId:   userId,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OneTimeToken struct {
+	Id          uuid.UUID      `db:"id"`
+	UserId      uuid.UUID      `db:"user_id"`
+	Purpose     string         `db:"purpose"`
+	Email       sql.NullString `db:"email"`
+	TokenHash   []byte         `db:"token_hash"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+	UsedAt      sql.NullTime   `db:"used_at"`
+	CreatedDate time.Time      `db:"created_date"`
+}
+
+func NewOneTimeTokenModel(userId uuid.UUID, purpose string, email string, tokenHash []byte, expiresAt time.Time) *OneTimeToken {
+	return &OneTimeToken{
+		Id:        uuid.New(),
+		UserId:    userId,
+		Purpose:   purpose,
+		Email:     sql.NullString{String: email, Valid: email != ""},
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *OneTimeToken) IsUsed() bool {
+	return t.UsedAt.Valid
+}
+
+func (t *OneTimeToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
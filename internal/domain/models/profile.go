@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type BirthdayVisibility string
+
+const (
+	BirthdayPublic  BirthdayVisibility = "public"
+	BirthdayPrivate BirthdayVisibility = "private"
+)
+
+// BirthdayLayout is the format birthdays are exchanged in, they have no time part.
+const BirthdayLayout = "2006-01-02"
+
+var ErrUnsupportedLinksType = errors.New("unsupported links type")
+
+// ProfileLinks are the website links of the user profile, they are stored as a json array.
+type ProfileLinks []string
+
+// ParseProfileLinks reads the links from a json array, an empty value has no links.
+func ParseProfileLinks(value string) (ProfileLinks, error) {
+	links := ProfileLinks{}
+	if strings.TrimSpace(value) == "" {
+		return links, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (l ProfileLinks) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]string(l))
+}
+
+func (l *ProfileLinks) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*l = ProfileLinks{}
+		return nil
+	default:
+		return ErrUnsupportedLinksType
+	}
+
+	return json.Unmarshal(data, (*[]string)(l))
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	Id          uuid.UUID     `db:"id"`
+	UserId      uuid.UUID     `db:"user_id"`
+	FamilyId    uuid.UUID     `db:"family_id"`
+	TokenHash   []byte        `db:"token_hash"`
+	ExpiresAt   time.Time     `db:"expires_at"`
+	RevokedAt   sql.NullTime  `db:"revoked_at"`
+	ReplacedBy  uuid.NullUUID `db:"replaced_by"`
+	CreatedDate time.Time     `db:"created_date"`
+}
+
+func NewRefreshTokenModel(userId uuid.UUID, familyId uuid.UUID, tokenHash []byte, expiresAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		Id:        uuid.New(),
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt.Valid
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a login of the user, its id is the family id of the refresh tokens issued for it.
+type Session struct {
+	Id          uuid.UUID    `db:"id"`
+	UserId      uuid.UUID    `db:"user_id"`
+	UserAgent   string       `db:"user_agent"`
+	IpAddress   string       `db:"ip_address"`
+	LastSeenAt  time.Time    `db:"last_seen_at"`
+	RevokedAt   sql.NullTime `db:"revoked_at"`
+	CreatedDate time.Time    `db:"created_date"`
+}
+
+func NewSessionModel(id uuid.UUID, userId uuid.UUID, userAgent string, ipAddress string) *Session {
+	return &Session{
+		Id:         id,
+		UserId:     userId,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+		LastSeenAt: time.Now(),
+	}
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt.Valid
+}
@@ -1,31 +1,49 @@
 package models
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type User struct {
-	Id          uuid.UUID `db:"id"`
-	Email       string    `db:"email"`
-	FName       string    `db:"fname"`
-	LName       string    `db:"lname"`
-	Avatar      string    `db:"avatar"`
-	AvatarMin   string    `db:"avatar_min"`
-	PassHash    []byte    `db:"pass_hash"`
-	CreatedDate time.Time `db:"created_date"`
-	UpdatedDate time.Time `db:"updated_date"`
+	Id                 uuid.UUID          `db:"id"`
+	Email              string             `db:"email"`
+	Username           sql.NullString     `db:"username"`
+	FName              string             `db:"fname"`
+	LName              string             `db:"lname"`
+	Avatar             string             `db:"avatar"`
+	AvatarMin          string             `db:"avatar_min"`
+	Bio                string             `db:"bio"`
+	Links              ProfileLinks       `db:"links"`
+	Location           string             `db:"location"`
+	Birthday           sql.NullTime       `db:"birthday"`
+	BirthdayVisibility BirthdayVisibility `db:"birthday_visibility"`
+	Pronouns           string             `db:"pronouns"`
+	Cover              string             `db:"cover"`
+	PassHash           []byte             `db:"pass_hash"`
+	TokenVersion       int                `db:"token_version"`
+	Version            int64              `db:"version"`
+	EmailVerifiedAt    sql.NullTime       `db:"email_verified_at"`
+	CreatedDate        time.Time          `db:"created_date"`
+	UpdatedDate        time.Time          `db:"updated_date"`
 }
 
 func NewUserModel(email string, fName string, lName string, hashPass []byte) *User {
 	return &User{
-		Id:        uuid.New(),
-		Email:     email,
-		PassHash:  hashPass,
-		Avatar:    "defaultAvatar",
-		AvatarMin: "defaultAvatarMin",
-		FName:     fName,
-		LName:     lName,
+		Id:                 uuid.New(),
+		Email:              email,
+		PassHash:           hashPass,
+		Avatar:             "defaultAvatar",
+		AvatarMin:          "defaultAvatarMin",
+		FName:              fName,
+		LName:              lName,
+		Links:              ProfileLinks{},
+		BirthdayVisibility: BirthdayPrivate,
 	}
 }
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt.Valid
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserRole struct {
+	UserId    uuid.UUID `db:"user_id"`
+	Role      string    `db:"role"`
+	GrantedAt time.Time `db:"granted_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTotp struct {
+	UserId       uuid.UUID    `db:"user_id"`
+	Secret       []byte       `db:"secret"`
+	ConfirmedAt  sql.NullTime `db:"confirmed_at"`
+	LastUsedStep int64        `db:"last_used_step"`
+	CreatedDate  time.Time    `db:"created_date"`
+}
+
+func (t *UserTotp) IsConfirmed() bool {
+	return t.ConfirmedAt.Valid
+}
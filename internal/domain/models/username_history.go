@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsernameHistoryEntry is a former username of the user, it redirects to the user until ExpiresAt.
+type UsernameHistoryEntry struct {
+	Username    string    `db:"username"`
+	UserId      uuid.UUID `db:"user_id"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+func (e *UsernameHistoryEntry) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
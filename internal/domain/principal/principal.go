@@ -0,0 +1,40 @@
+package principal
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type principalCtxKey struct{}
+
+// Principal is the authenticated caller of a request, either a user or a service account
+// calling with an api key. Service accounts have Scopes instead of a user id and roles.
+// ActorId is set when an admin acts as the user with an impersonation token.
+type Principal struct {
+	UserId         uuid.UUID
+	Email          string
+	Roles          []string
+	SessionId      uuid.UUID
+	ServiceAccount string
+	Scopes         []string
+	ActorId        uuid.UUID
+}
+
+func (p Principal) IsServiceAccount() bool {
+	return p.ServiceAccount != ""
+}
+
+func (p Principal) IsImpersonated() bool {
+	return p.ActorId != uuid.Nil
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// FromContext returns the caller of the request, ok is false for unauthenticated requests.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
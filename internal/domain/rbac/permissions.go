@@ -0,0 +1,19 @@
+package rbac
+
+type Permission string
+
+// The permissions are granted to roles in the role_permissions table.
+const (
+	UsersUpdateAny   Permission = "users:update:any"
+	UsersDeleteAny   Permission = "users:delete:any"
+	RolesManage      Permission = "roles:manage"
+	AccountsUnlock   Permission = "accounts:unlock"
+	TokensRevokeAny  Permission = "tokens:revoke:any"
+	ApiKeysManage    Permission = "api_keys:manage"
+	UsersImpersonate Permission = "users:impersonate"
+)
+
+const (
+	AdminRole     = "admin"
+	ModeratorRole = "moderator"
+)
@@ -5,28 +5,61 @@ import (
 	authv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/auth"
 	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
 	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/principal"
 	handlersdep "github.com/KBcHMFollower/blog_user_service/internal/handlers/dep"
 	handlersutils "github.com/KBcHMFollower/blog_user_service/internal/handlers/lib"
 	"github.com/KBcHMFollower/blog_user_service/internal/logger"
 	servicesinterfaces "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 )
 
 type GRPCAuth struct {
 	authv1.UnimplementedAuthServer
-	authService servicesinterfaces.AuthService
-	log         logger.Logger
-	validator   handlersdep.Validator
+	authService     servicesinterfaces.AuthService
+	passwordService servicesinterfaces.PasswordService
+	emailService    servicesinterfaces.EmailService
+	totpService     servicesinterfaces.TotpService
+	sessionService  servicesinterfaces.SessionService
+	roleService     servicesinterfaces.RoleService
+	apiKeyService   servicesinterfaces.ApiKeyService
+	identityService servicesinterfaces.IdentityService
+	log             logger.Logger
+	validator       handlersdep.Validator
 }
 
-func RegisterAuthServer(gRPC *grpc.Server, authService servicesinterfaces.AuthService, validator handlersdep.Validator, log logger.Logger) {
-	authv1.RegisterAuthServer(gRPC, &GRPCAuth{authService: authService, log: log, validator: validator})
+func RegisterAuthServer(
+	gRPC *grpc.Server,
+	authService servicesinterfaces.AuthService,
+	passwordService servicesinterfaces.PasswordService,
+	emailService servicesinterfaces.EmailService,
+	totpService servicesinterfaces.TotpService,
+	sessionService servicesinterfaces.SessionService,
+	roleService servicesinterfaces.RoleService,
+	apiKeyService servicesinterfaces.ApiKeyService,
+	identityService servicesinterfaces.IdentityService,
+	validator handlersdep.Validator,
+	log logger.Logger,
+) {
+	authv1.RegisterAuthServer(gRPC, &GRPCAuth{
+		authService:     authService,
+		passwordService: passwordService,
+		emailService:    emailService,
+		totpService:     totpService,
+		sessionService:  sessionService,
+		roleService:     roleService,
+		apiKeyService:   apiKeyService,
+		identityService: identityService,
+		log:             log,
+		validator:       validator,
+	})
 }
 
 func (s *GRPCAuth) Login(ctx context.Context, req *authv1.LoginDTO) (*authv1.LoginRTO, error) {
 	logInfo := servicestransfer.LoginInfo{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   handlersutils.ClientInfo(ctx),
 	}
 
 	if err := s.validator.Struct(logInfo); err != nil {
@@ -41,7 +74,10 @@ func (s *GRPCAuth) Login(ctx context.Context, req *authv1.LoginDTO) (*authv1.Log
 	}
 
 	return &authv1.LoginRTO{
-		Token: token.AccessToken,
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		MfaRequired:  token.MfaRequired,
+		MfaToken:     token.MfaToken,
 	}, nil
 }
 
@@ -51,6 +87,7 @@ func (s *GRPCAuth) Register(ctx context.Context, req *authv1.RegisterDTO) (*auth
 		Password: req.Password,
 		FName:    req.Fname,
 		LName:    req.Lname,
+		Client:   handlersutils.ClientInfo(ctx),
 	}
 
 	if err := s.validator.Struct(&regInfo); err != nil {
@@ -65,7 +102,8 @@ func (s *GRPCAuth) Register(ctx context.Context, req *authv1.RegisterDTO) (*auth
 	}
 
 	return &authv1.RegisterRTO{
-		Token: token.AccessToken,
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
 	}, nil
 }
 
@@ -78,13 +116,921 @@ func (s *GRPCAuth) CheckAuth(ctx context.Context, req *authv1.CheckAuthDTO) (*au
 		return nil, handlersutils.ReturnValidationError(err)
 	}
 
-	token, err := s.authService.CheckAuth(ctx, &checkAuthInfo)
+	res, err := s.authService.CheckAuth(ctx, &checkAuthInfo)
 	if err != nil {
 		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t check", logger.ErrKey, err.Error())
 		return nil, err
 	}
 
 	return &authv1.CheckAuthRTO{
-		Token: token.AccessToken,
+		Token: res.AccessToken,
+		Claims: &authv1.Claims{
+			UserId:        res.Claims.UserId.String(),
+			Email:         res.Claims.Email,
+			Roles:         res.Claims.Roles,
+			IssuedAt:      res.Claims.IssuedAt.Unix(),
+			ExpiresAt:     res.Claims.ExpiresAt.Unix(),
+			SessionId:     res.Claims.SessionId.String(),
+			EmailVerified: res.Claims.EmailVerified,
+			ActorId:       actorId(res.Claims.ActorId),
+		},
+	}, nil
+}
+
+// actorId keeps actor_id empty for tokens of the users themselves.
+func actorId(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func (s *GRPCAuth) RefreshToken(ctx context.Context, req *authv1.RefreshTokenDTO) (*authv1.RefreshTokenRTO, error) {
+	refreshInfo := servicestransfer.RefreshTokenInfo{
+		RefreshToken: req.RefreshToken,
+	}
+
+	if err := s.validator.Struct(refreshInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	token, err := s.authService.RefreshToken(ctx, &refreshInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t refresh token", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.RefreshTokenRTO{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}, nil
+}
+
+func (s *GRPCAuth) Logout(ctx context.Context, req *authv1.LogoutDTO) (*authv1.LogoutRTO, error) {
+	logoutInfo := servicestransfer.LogoutInfo{
+		RefreshToken: req.RefreshToken,
+		AllSessions:  req.AllSessions,
+	}
+
+	if err := s.validator.Struct(logoutInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.Logout(ctx, &logoutInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t logout", logger.ErrKey, err.Error())
+		return &authv1.LogoutRTO{
+			IsLoggedOut: false,
+		}, err
+	}
+
+	return &authv1.LogoutRTO{
+		IsLoggedOut: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeToken(ctx context.Context, req *authv1.RevokeTokenDTO) (*authv1.RevokeTokenRTO, error) {
+	revokeInfo := servicestransfer.RevokeTokenInfo{
+		AccessToken: req.Token,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.RevokeToken(ctx, &revokeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke token", logger.ErrKey, err.Error())
+		return &authv1.RevokeTokenRTO{
+			IsRevoked: false,
+		}, err
+	}
+
+	return &authv1.RevokeTokenRTO{
+		IsRevoked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeUserTokens(ctx context.Context, req *authv1.RevokeUserTokensDTO) (*authv1.RevokeUserTokensRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	revokeInfo := servicestransfer.RevokeUserTokensInfo{
+		UserId: userId,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.RevokeUserTokens(ctx, &revokeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke user tokens", logger.ErrKey, err.Error())
+		return &authv1.RevokeUserTokensRTO{
+			IsRevoked: false,
+		}, err
+	}
+
+	return &authv1.RevokeUserTokensRTO{
+		IsRevoked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) UnlockAccount(ctx context.Context, req *authv1.UnlockAccountDTO) (*authv1.UnlockAccountRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	unlockInfo := servicestransfer.UnlockAccountInfo{
+		UserId: userId,
+	}
+
+	if err := s.validator.Struct(unlockInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.UnlockAccount(ctx, &unlockInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t unlock account", logger.ErrKey, err.Error())
+		return &authv1.UnlockAccountRTO{
+			IsUnlocked: false,
+		}, err
+	}
+
+	return &authv1.UnlockAccountRTO{
+		IsUnlocked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) GrantRole(ctx context.Context, req *authv1.GrantRoleDTO) (*authv1.GrantRoleRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	grantInfo := servicestransfer.GrantRoleInfo{
+		UserId: userId,
+		Role:   req.Role,
+	}
+
+	if err := s.validator.Struct(grantInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.roleService.GrantRole(ctx, &grantInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t grant role", logger.ErrKey, err.Error())
+		return &authv1.GrantRoleRTO{
+			IsGranted: false,
+		}, err
+	}
+
+	return &authv1.GrantRoleRTO{
+		IsGranted: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeRole(ctx context.Context, req *authv1.RevokeRoleDTO) (*authv1.RevokeRoleRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	revokeInfo := servicestransfer.RevokeRoleInfo{
+		UserId: userId,
+		Role:   req.Role,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.roleService.RevokeRole(ctx, &revokeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke role", logger.ErrKey, err.Error())
+		return &authv1.RevokeRoleRTO{
+			IsRevoked: false,
+		}, err
+	}
+
+	return &authv1.RevokeRoleRTO{
+		IsRevoked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) GetJWKS(ctx context.Context, _ *authv1.GetJWKSDTO) (*authv1.GetJWKSRTO, error) {
+	jwks, err := s.authService.GetJWKS(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t get jwks", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	keys := make([]*authv1.Jwk, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		keys = append(keys, &authv1.Jwk{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+
+	return &authv1.GetJWKSRTO{
+		Keys: keys,
+	}, nil
+}
+
+func (s *GRPCAuth) RequestPasswordReset(ctx context.Context, req *authv1.RequestPasswordResetDTO) (*authv1.RequestPasswordResetRTO, error) {
+	requestInfo := servicestransfer.RequestPasswordResetInfo{
+		Email: req.Email,
+	}
+
+	if err := s.validator.Struct(requestInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.passwordService.RequestPasswordReset(ctx, &requestInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t request password reset", logger.ErrKey, err.Error())
+		return &authv1.RequestPasswordResetRTO{
+			IsRequested: false,
+		}, err
+	}
+
+	return &authv1.RequestPasswordResetRTO{
+		IsRequested: true,
+	}, nil
+}
+
+func (s *GRPCAuth) ConfirmPasswordReset(ctx context.Context, req *authv1.ConfirmPasswordResetDTO) (*authv1.ConfirmPasswordResetRTO, error) {
+	confirmInfo := servicestransfer.ConfirmPasswordResetInfo{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}
+
+	if err := s.validator.Struct(confirmInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.passwordService.ConfirmPasswordReset(ctx, &confirmInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t confirm password reset", logger.ErrKey, err.Error())
+		return &authv1.ConfirmPasswordResetRTO{
+			IsReset: false,
+		}, err
+	}
+
+	return &authv1.ConfirmPasswordResetRTO{
+		IsReset: true,
+	}, nil
+}
+
+func (s *GRPCAuth) ChangePassword(ctx context.Context, req *authv1.ChangePasswordDTO) (*authv1.ChangePasswordRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	changeInfo := servicestransfer.ChangePasswordInfo{
+		UserId:      userId,
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	}
+
+	if err := s.validator.Struct(changeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.passwordService.ChangePassword(ctx, &changeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t change password", logger.ErrKey, err.Error())
+		return &authv1.ChangePasswordRTO{
+			IsChanged: false,
+		}, err
+	}
+
+	return &authv1.ChangePasswordRTO{
+		IsChanged: true,
+	}, nil
+}
+
+func (s *GRPCAuth) SendVerificationEmail(ctx context.Context, req *authv1.SendVerificationEmailDTO) (*authv1.SendVerificationEmailRTO, error) {
+	sendInfo := servicestransfer.SendVerificationEmailInfo{
+		Email: req.Email,
+	}
+
+	if err := s.validator.Struct(sendInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.emailService.SendVerificationEmail(ctx, &sendInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t send verification email", logger.ErrKey, err.Error())
+		return &authv1.SendVerificationEmailRTO{
+			IsSent: false,
+		}, err
+	}
+
+	return &authv1.SendVerificationEmailRTO{
+		IsSent: true,
+	}, nil
+}
+
+func (s *GRPCAuth) VerifyEmail(ctx context.Context, req *authv1.VerifyEmailDTO) (*authv1.VerifyEmailRTO, error) {
+	verifyInfo := servicestransfer.VerifyEmailInfo{
+		Token: req.Token,
+	}
+
+	if err := s.validator.Struct(verifyInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.emailService.VerifyEmail(ctx, &verifyInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t verify email", logger.ErrKey, err.Error())
+		return &authv1.VerifyEmailRTO{
+			IsVerified: false,
+		}, err
+	}
+
+	return &authv1.VerifyEmailRTO{
+		IsVerified: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RequestEmailChange(ctx context.Context, req *authv1.RequestEmailChangeDTO) (*authv1.RequestEmailChangeRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	changeInfo := servicestransfer.RequestEmailChangeInfo{
+		UserId:   userId,
+		NewEmail: req.NewEmail,
+		Password: req.Password,
+	}
+
+	if err := s.validator.Struct(changeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.emailService.RequestEmailChange(ctx, &changeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t request email change", logger.ErrKey, err.Error())
+		return &authv1.RequestEmailChangeRTO{
+			IsRequested: false,
+		}, err
+	}
+
+	return &authv1.RequestEmailChangeRTO{
+		IsRequested: true,
+	}, nil
+}
+
+func (s *GRPCAuth) ConfirmEmailChange(ctx context.Context, req *authv1.ConfirmEmailChangeDTO) (*authv1.ConfirmEmailChangeRTO, error) {
+	confirmInfo := servicestransfer.ConfirmEmailChangeInfo{
+		Token: req.Token,
+	}
+
+	if err := s.validator.Struct(confirmInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.emailService.ConfirmEmailChange(ctx, &confirmInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t confirm email change", logger.ErrKey, err.Error())
+		return &authv1.ConfirmEmailChangeRTO{
+			IsChanged: false,
+		}, err
+	}
+
+	return &authv1.ConfirmEmailChangeRTO{
+		IsChanged: true,
+	}, nil
+}
+
+func (s *GRPCAuth) EnrollTotp(ctx context.Context, req *authv1.EnrollTotpDTO) (*authv1.EnrollTotpRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	enrollInfo := servicestransfer.EnrollTotpInfo{
+		UserId: userId,
+	}
+
+	if err := s.validator.Struct(enrollInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.totpService.EnrollTotp(ctx, &enrollInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t enroll totp", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.EnrollTotpRTO{
+		Secret:          res.Secret,
+		ProvisioningUri: res.ProvisioningUri,
+	}, nil
+}
+
+func (s *GRPCAuth) ConfirmTotp(ctx context.Context, req *authv1.ConfirmTotpDTO) (*authv1.ConfirmTotpRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	confirmInfo := servicestransfer.ConfirmTotpInfo{
+		UserId: userId,
+		Code:   req.Code,
+	}
+
+	if err := s.validator.Struct(confirmInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.totpService.ConfirmTotp(ctx, &confirmInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t confirm totp", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.ConfirmTotpRTO{
+		RecoveryCodes: res.RecoveryCodes,
+	}, nil
+}
+
+func (s *GRPCAuth) DisableTotp(ctx context.Context, req *authv1.DisableTotpDTO) (*authv1.DisableTotpRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	disableInfo := servicestransfer.DisableTotpInfo{
+		UserId:       userId,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		Client:       handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(disableInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.totpService.DisableTotp(ctx, &disableInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t disable totp", logger.ErrKey, err.Error())
+		return &authv1.DisableTotpRTO{
+			IsDisabled: false,
+		}, err
+	}
+
+	return &authv1.DisableTotpRTO{
+		IsDisabled: true,
+	}, nil
+}
+
+func (s *GRPCAuth) VerifyMfa(ctx context.Context, req *authv1.VerifyMfaDTO) (*authv1.VerifyMfaRTO, error) {
+	verifyInfo := servicestransfer.VerifyMfaInfo{
+		MfaToken:     req.MfaToken,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		Client:       handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(verifyInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	token, err := s.authService.VerifyMfa(ctx, &verifyInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t verify mfa", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.VerifyMfaRTO{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}, nil
+}
+
+func (s *GRPCAuth) RequestMagicLink(ctx context.Context, req *authv1.RequestMagicLinkDTO) (*authv1.RequestMagicLinkRTO, error) {
+	requestInfo := servicestransfer.RequestMagicLinkInfo{
+		Email: req.Email,
+	}
+
+	if err := s.validator.Struct(requestInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.RequestMagicLink(ctx, &requestInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t request magic link", logger.ErrKey, err.Error())
+		return &authv1.RequestMagicLinkRTO{
+			IsRequested: false,
+		}, err
+	}
+
+	return &authv1.RequestMagicLinkRTO{
+		IsRequested: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RedeemMagicLink(ctx context.Context, req *authv1.RedeemMagicLinkDTO) (*authv1.RedeemMagicLinkRTO, error) {
+	redeemInfo := servicestransfer.RedeemMagicLinkInfo{
+		Token:  req.Token,
+		Client: handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(redeemInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	token, err := s.authService.RedeemMagicLink(ctx, &redeemInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t redeem magic link", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.RedeemMagicLinkRTO{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		MfaRequired:  token.MfaRequired,
+		MfaToken:     token.MfaToken,
+	}, nil
+}
+
+func (s *GRPCAuth) ExchangeExternalToken(ctx context.Context, req *authv1.ExchangeExternalTokenDTO) (*authv1.ExchangeExternalTokenRTO, error) {
+	exchangeInfo := servicestransfer.ExchangeExternalTokenInfo{
+		Provider: req.Provider,
+		IdToken:  req.IdToken,
+		Client:   handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(exchangeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	token, err := s.authService.ExchangeExternalToken(ctx, &exchangeInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t exchange external token", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.ExchangeExternalTokenRTO{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		MfaRequired:  token.MfaRequired,
+		MfaToken:     token.MfaToken,
+	}, nil
+}
+
+func (s *GRPCAuth) LinkIdentity(ctx context.Context, req *authv1.LinkIdentityDTO) (*authv1.LinkIdentityRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	linkInfo := servicestransfer.LinkIdentityInfo{
+		UserId:   userId,
+		Provider: req.Provider,
+		IdToken:  req.IdToken,
+	}
+
+	if err := s.validator.Struct(linkInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.identityService.LinkIdentity(ctx, &linkInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t link identity", logger.ErrKey, err.Error())
+		return &authv1.LinkIdentityRTO{
+			IsLinked: false,
+		}, err
+	}
+
+	return &authv1.LinkIdentityRTO{
+		IsLinked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) UnlinkIdentity(ctx context.Context, req *authv1.UnlinkIdentityDTO) (*authv1.UnlinkIdentityRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	unlinkInfo := servicestransfer.UnlinkIdentityInfo{
+		UserId:   userId,
+		Provider: req.Provider,
+	}
+
+	if err := s.validator.Struct(unlinkInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.identityService.UnlinkIdentity(ctx, &unlinkInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t unlink identity", logger.ErrKey, err.Error())
+		return &authv1.UnlinkIdentityRTO{
+			IsUnlinked: false,
+		}, err
+	}
+
+	return &authv1.UnlinkIdentityRTO{
+		IsUnlinked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) Impersonate(ctx context.Context, req *authv1.ImpersonateDTO) (*authv1.ImpersonateRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	caller, ok := principal.FromContext(ctx)
+	if !ok || caller.IsServiceAccount() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("only users can impersonate", ctxerrors.ErrForbidden))
+	}
+
+	impersonateInfo := servicestransfer.ImpersonateInfo{
+		ActorId:        caller.UserId,
+		ActorSessionId: caller.SessionId,
+		UserId:         userId,
+		Reason:         req.Reason,
+		Client:         handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(impersonateInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.authService.Impersonate(ctx, &impersonateInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t impersonate user", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.ImpersonateRTO{
+		Token:     res.AccessToken,
+		ExpiresAt: res.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *GRPCAuth) StopImpersonation(ctx context.Context, req *authv1.StopImpersonationDTO) (*authv1.StopImpersonationRTO, error) {
+	stopInfo := servicestransfer.StopImpersonationInfo{
+		AccessToken: req.Token,
+		Client:      handlersutils.ClientInfo(ctx),
+	}
+
+	if err := s.validator.Struct(stopInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.authService.StopImpersonation(ctx, &stopInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t stop impersonation", logger.ErrKey, err.Error())
+		return &authv1.StopImpersonationRTO{
+			IsStopped: false,
+		}, err
+	}
+
+	return &authv1.StopImpersonationRTO{
+		IsStopped: true,
+	}, nil
+}
+
+func (s *GRPCAuth) ListSessions(ctx context.Context, req *authv1.ListSessionsDTO) (*authv1.ListSessionsRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	listInfo := servicestransfer.ListSessionsInfo{
+		UserId: userId,
+	}
+	if req.CurrentSessionId != "" {
+		currentSessionId, err := uuid.Parse(req.CurrentSessionId)
+		if err != nil {
+			s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse session uuid", logger.ErrKey, err.Error())
+			return nil, err
+		}
+
+		listInfo.CurrentSessionId = currentSessionId
+	}
+
+	if err := s.validator.Struct(listInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.sessionService.ListSessions(ctx, &listInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t list sessions", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	sessions := make([]*authv1.Session, 0, len(res.Sessions))
+	for _, session := range res.Sessions {
+		sessions = append(sessions, &authv1.Session{
+			Id:         session.Id.String(),
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			LastSeenAt: session.LastSeenAt.Unix(),
+			CreatedAt:  session.CreatedAt.Unix(),
+			Current:    session.Current,
+		})
+	}
+
+	return &authv1.ListSessionsRTO{
+		Sessions: sessions,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeSession(ctx context.Context, req *authv1.RevokeSessionDTO) (*authv1.RevokeSessionRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+	sessionId, err := uuid.Parse(req.SessionId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse session uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	revokeInfo := servicestransfer.RevokeSessionInfo{
+		UserId:    userId,
+		SessionId: sessionId,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.sessionService.RevokeSession(ctx, &revokeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke session", logger.ErrKey, err.Error())
+		return &authv1.RevokeSessionRTO{
+			IsRevoked: false,
+		}, err
+	}
+
+	return &authv1.RevokeSessionRTO{
+		IsRevoked: true,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeAllOtherSessions(ctx context.Context, req *authv1.RevokeAllOtherSessionsDTO) (*authv1.RevokeAllOtherSessionsRTO, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+	currentSessionId, err := uuid.Parse(req.CurrentSessionId)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse session uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	revokeInfo := servicestransfer.RevokeOtherSessionsInfo{
+		UserId:           userId,
+		CurrentSessionId: currentSessionId,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	revoked, err := s.sessionService.RevokeAllOtherSessions(ctx, &revokeInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke other sessions", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.RevokeAllOtherSessionsRTO{
+		RevokedCount: revoked,
+	}, nil
+}
+
+func (s *GRPCAuth) CreateApiKey(ctx context.Context, req *authv1.CreateApiKeyDTO) (*authv1.CreateApiKeyRTO, error) {
+	createInfo := servicestransfer.CreateApiKeyInfo{
+		ServiceAccount: req.ServiceAccount,
+		Scopes:         req.Scopes,
+	}
+	if caller, ok := principal.FromContext(ctx); ok && !caller.IsServiceAccount() {
+		createInfo.CreatedBy = uuid.NullUUID{UUID: caller.UserId, Valid: true}
+	}
+
+	if err := s.validator.Struct(createInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.apiKeyService.CreateApiKey(ctx, &createInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t create api key", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	return &authv1.CreateApiKeyRTO{
+		Id:     res.Id.String(),
+		Prefix: res.Prefix,
+		Key:    res.Key,
+	}, nil
+}
+
+func (s *GRPCAuth) ListApiKeys(ctx context.Context, req *authv1.ListApiKeysDTO) (*authv1.ListApiKeysRTO, error) {
+	listInfo := servicestransfer.ListApiKeysInfo{
+		ServiceAccount: req.ServiceAccount,
+	}
+
+	if err := s.validator.Struct(listInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.apiKeyService.ListApiKeys(ctx, &listInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t list api keys", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	apiKeys := make([]*authv1.ApiKey, 0, len(res.ApiKeys))
+	for _, apiKey := range res.ApiKeys {
+		var lastUsedAt int64
+		if !apiKey.LastUsedAt.IsZero() {
+			lastUsedAt = apiKey.LastUsedAt.Unix()
+		}
+
+		apiKeys = append(apiKeys, &authv1.ApiKey{
+			Id:             apiKey.Id.String(),
+			ServiceAccount: apiKey.ServiceAccount,
+			Prefix:         apiKey.Prefix,
+			Scopes:         apiKey.Scopes,
+			LastUsedAt:     lastUsedAt,
+			CreatedAt:      apiKey.CreatedAt.Unix(),
+		})
+	}
+
+	return &authv1.ListApiKeysRTO{
+		ApiKeys: apiKeys,
+	}, nil
+}
+
+func (s *GRPCAuth) RevokeApiKey(ctx context.Context, req *authv1.RevokeApiKeyDTO) (*authv1.RevokeApiKeyRTO, error) {
+	keyId, err := uuid.Parse(req.Id)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse api key uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	revokeInfo := servicestransfer.RevokeApiKeyInfo{
+		Id: keyId,
+	}
+
+	if err := s.validator.Struct(revokeInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "validation err", logger.ErrKey, err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	if err := s.apiKeyService.RevokeApiKey(ctx, &revokeInfo); err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t revoke api key", logger.ErrKey, err.Error())
+		return &authv1.RevokeApiKeyRTO{
+			IsRevoked: false,
+		}, err
+	}
+
+	return &authv1.RevokeApiKeyRTO{
+		IsRevoked: true,
 	}, nil
 }
@@ -5,6 +5,7 @@ import (
 	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
 	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
 	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/principal"
 	handlersdep "github.com/KBcHMFollower/blog_user_service/internal/handlers/dep"
 	handlersutils "github.com/KBcHMFollower/blog_user_service/internal/handlers/lib"
 	"github.com/KBcHMFollower/blog_user_service/internal/logger"
@@ -49,8 +50,54 @@ func (s *GRPCUsers) GetUser(ctx context.Context, req *usersv1.GetUserDTO) (*user
 		return nil, err
 	}
 
+	visibleUser := user.User.VisibleTo(viewerId(ctx))
+
 	return &usersv1.GetUserRDO{
-		User: servicestransfer.ConvertUserResToProto(&user.User),
+		User: servicestransfer.ConvertUserResToProto(&visibleUser),
+	}, nil
+}
+
+func (s *GRPCUsers) GetUsers(ctx context.Context, req *usersv1.GetUsersDTO) (*usersv1.GetUsersRDO, error) {
+	userIds := make([]uuid.UUID, 0, len(req.Ids))
+	for _, id := range req.Ids {
+		userId, err := uuid.Parse(id)
+		if err != nil {
+			s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+			return nil, handlersutils.ReturnValidationError(err)
+		}
+		userIds = append(userIds, userId)
+	}
+
+	getInfo := servicestransfer.GetUsersInfo{
+		Ids: userIds,
+	}
+
+	if err := s.validator.Struct(getInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.userService.GetUsers(ctx, &getInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to get users", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	viewer := viewerId(ctx)
+	users := make([]*usersv1.User, 0, len(res.Users))
+	for _, user := range res.Users {
+		visibleUser := user.VisibleTo(viewer)
+		users = append(users, servicestransfer.ConvertUserResToProto(&visibleUser))
+	}
+
+	notFoundIds := make([]string, 0, len(res.NotFoundIds))
+	for _, id := range res.NotFoundIds {
+		notFoundIds = append(notFoundIds, id.String())
+	}
+
+	return &usersv1.GetUsersRDO{
+		Users:       users,
+		NotFoundIds: notFoundIds,
 	}, nil
 }
 
@@ -120,16 +167,41 @@ func (s *GRPCUsers) UpdateUser(ctx context.Context, req *usersv1.UpdateUserDTO)
 		return nil, err
 	}
 
-	updateFields := make(map[servicestransfer.UserFieldTarget]any)
-	for k, v := range req.UpdateData {
-		updateFields[servicestransfer.UserFieldTarget(k)] = v
+	updateFields, err := servicestransfer.UpdateFieldsFromMap(req.UpdateData)
+	if err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
 	}
 
-	updateInfo := servicestransfer.UpdateUserInfo{
-		Id:           userId,
-		UpdateFields: updateFields,
+	return s.updateUser(ctx, servicestransfer.UpdateUserInfo{
+		Id:              userId,
+		UpdateFields:    updateFields,
+		ExpectedVersion: req.ExpectedVersion,
+	})
+}
+
+// UpdateUserV2 updates the fields of the update mask paths, UpdateUser is kept as an adapter for older clients.
+func (s *GRPCUsers) UpdateUserV2(ctx context.Context, req *usersv1.UpdateUserV2DTO) (*usersv1.UpdateUserRDO, error) {
+	userId, err := uuid.Parse(req.Id)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to parse user uuid", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	updateFields, err := servicestransfer.UpdateFieldsFromPatch(req.GetUser(), req.GetUpdateMask().GetPaths())
+	if err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
 	}
 
+	return s.updateUser(ctx, servicestransfer.UpdateUserInfo{
+		Id:              userId,
+		UpdateFields:    updateFields,
+		ExpectedVersion: req.ExpectedVersion,
+	})
+}
+
+func (s *GRPCUsers) updateUser(ctx context.Context, updateInfo servicestransfer.UpdateUserInfo) (*usersv1.UpdateUserRDO, error) {
 	if err := s.validator.Struct(updateInfo); err != nil {
 		return nil, handlersutils.ReturnValidationError(err)
 	}
@@ -235,10 +307,18 @@ func (s *GRPCUsers) UploadAvatar(ctx context.Context, req *usersv1.UploadAvatarD
 		return nil, err
 	}
 
-	res, err := s.userService.UploadAvatar(ctx, &servicestransfer.UploadAvatarInfo{
-		UserId: userId,
-		Image:  req.Image,
-	})
+	uploadInfo := servicestransfer.UploadAvatarInfo{
+		UserId:          userId,
+		Image:           req.Image,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+
+	if err := s.validator.Struct(uploadInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.userService.UploadAvatar(ctx, &uploadInfo)
 	if err != nil {
 		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to upload avatar", logger.ErrKey, err.Error())
 		return nil, err
@@ -248,5 +328,62 @@ func (s *GRPCUsers) UploadAvatar(ctx context.Context, req *usersv1.UploadAvatarD
 		UserId:        userId.String(),
 		AvatarUrl:     res.Avatar,
 		AvatarMiniUrl: res.AvatarMini,
+		Version:       res.Version,
+	}, nil
+}
+
+func (s *GRPCUsers) GetUserByUsername(ctx context.Context, req *usersv1.GetUserByUsernameDTO) (*usersv1.GetUserByUsernameRDO, error) {
+	getInfo := servicestransfer.GetUserByUsernameInfo{
+		Username: req.Username,
+	}
+
+	if err := s.validator.Struct(getInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return nil, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.userService.GetUserByUsername(ctx, &getInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to get user by username", logger.ErrKey, err.Error())
+		return nil, err
+	}
+
+	visibleUser := res.User.VisibleTo(viewerId(ctx))
+
+	return &usersv1.GetUserByUsernameRDO{
+		User:       servicestransfer.ConvertUserResToProto(&visibleUser),
+		IsRedirect: res.Redirected,
 	}, nil
 }
+
+func (s *GRPCUsers) CheckUsernameAvailable(ctx context.Context, req *usersv1.CheckUsernameAvailableDTO) (*usersv1.CheckUsernameAvailableRDO, error) {
+	checkInfo := servicestransfer.CheckUsernameInfo{
+		Username: req.Username,
+	}
+
+	if err := s.validator.Struct(checkInfo); err != nil {
+		s.log.DebugContext(ctxerrors.ErrorCtx(ctx, err), err.Error())
+		return &usersv1.CheckUsernameAvailableRDO{IsAvailable: false}, handlersutils.ReturnValidationError(err)
+	}
+
+	res, err := s.userService.CheckUsernameAvailable(ctx, &checkInfo)
+	if err != nil {
+		s.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "Failed to check username availability", logger.ErrKey, err.Error())
+		return &usersv1.CheckUsernameAvailableRDO{IsAvailable: false}, err
+	}
+
+	return &usersv1.CheckUsernameAvailableRDO{
+		IsAvailable: res.Available,
+		Reason:      res.Reason,
+		Suggestions: res.Suggestions,
+	}, nil
+}
+
+// viewerId is uuid.Nil for anonymous callers and service accounts.
+func viewerId(ctx context.Context) uuid.UUID {
+	caller, ok := principal.FromContext(ctx)
+	if !ok {
+		return uuid.Nil
+	}
+	return caller.UserId
+}
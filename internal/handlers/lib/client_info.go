@@ -0,0 +1,62 @@
+package handlers_utils
+
+import (
+	"context"
+	"net"
+
+	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	userAgentMdKey = "user-agent"
+)
+
+type clientIpCtxKey struct{}
+
+// WithClientIp stores the client ip resolved from a trusted source, ClientIp prefers it to the peer address.
+func WithClientIp(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIpCtxKey{}, ip)
+}
+
+// ClientInfo reads the user agent from the request metadata and the address of the connected peer.
+func ClientInfo(ctx context.Context) servicestransfer.ClientInfo {
+	return servicestransfer.ClientInfo{
+		UserAgent: userAgent(ctx),
+		IpAddress: ClientIp(ctx),
+	}
+}
+
+// ClientIp returns the ip stored with WithClientIp, the ip of the connected peer
+// or an empty string when it is unknown.
+func ClientIp(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIpCtxKey{}).(string); ok && ip != "" {
+		return ip
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func userAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if v := md.Get(userAgentMdKey); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
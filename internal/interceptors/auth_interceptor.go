@@ -0,0 +1,198 @@
+package interceptors
+
+import (
+	"context"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/principal"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/rbac"
+	"github.com/KBcHMFollower/blog_user_service/internal/interceptors/interfaces/dep"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"slices"
+	"strings"
+)
+
+const (
+	authorizationMdKey = "authorization"
+	bearerPrefix       = "bearer "
+	apiKeyPrefix       = "apikey "
+)
+
+// MethodPolicy describes who may call a method. Owner returns the id of the user the request acts on,
+// the caller must be that user or have Permission. Without Owner the caller needs Permission only,
+// a policy with neither allows any authenticated caller. Methods without a policy are public.
+// DenyImpersonation rejects impersonation tokens, admins acting as a user can't change its credentials.
+// AllowAnonymous lets callers without credentials through, the given credentials are checked as usual.
+type MethodPolicy struct {
+	Owner             func(req interface{}) string
+	Permission        rbac.Permission
+	DenyImpersonation bool
+	AllowAnonymous    bool
+}
+
+// orPermission lets callers with the permission act on accounts of other users.
+func (p MethodPolicy) orPermission(permission rbac.Permission) MethodPolicy {
+	p.Permission = permission
+	return p
+}
+
+// notImpersonated denies the method to impersonation tokens.
+func (p MethodPolicy) notImpersonated() MethodPolicy {
+	p.DenyImpersonation = true
+	return p
+}
+
+// ownedBy builds a policy for requests of type T acting on the user returned by userId.
+func ownedBy[T any](userId func(req T) string) MethodPolicy {
+	return MethodPolicy{
+		Owner: func(req interface{}) string {
+			typedReq, ok := req.(T)
+			if !ok {
+				return ""
+			}
+			return userId(typedReq)
+		},
+	}
+}
+
+// authenticated is a policy of methods any authenticated caller may use.
+func authenticated() MethodPolicy {
+	return MethodPolicy{}
+}
+
+// anonymous is a policy of public methods whose response depends on the caller, e.g. hides private fields.
+func anonymous() MethodPolicy {
+	return MethodPolicy{AllowAnonymous: true}
+}
+
+// requires builds a policy of methods only callers with the permission may use.
+func requires(permission rbac.Permission) MethodPolicy {
+	return MethodPolicy{Permission: permission}
+}
+
+// AuthInterceptor validates the bearer token or the api key of the methods with a policy
+// and puts the caller into the context.
+func AuthInterceptor(
+	claimsChecker dep.ClaimsChecker,
+	apiKeyAuthenticator dep.ApiKeyAuthenticator,
+	permissionsGetter dep.RolesPermissionsGetter,
+	jwtOpts tokenshelper.JwtOptions,
+	policies map[string]MethodPolicy,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		policy, ok := policies[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if policy.AllowAnonymous && !hasCredentials(ctx) {
+			return handler(ctx, req)
+		}
+
+		var caller principal.Principal
+		if key, ok := credentials(ctx, apiKeyPrefix); ok {
+			apiKey, err := apiKeyAuthenticator.AuthenticateApiKey(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+
+			caller = principal.Principal{
+				ServiceAccount: apiKey.ServiceAccount,
+				Scopes:         apiKey.Scopes,
+			}
+		} else {
+			token, ok := credentials(ctx, bearerPrefix)
+			if !ok {
+				return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("bearer token is missing", ctxerrors.ErrUnauthorized))
+			}
+
+			tokenClaims, err := tokenshelper.Parse(token, jwtOpts)
+			if err != nil {
+				return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t parse token", ctxerrors.ErrUnauthorized))
+			}
+			if err := claimsChecker.CheckClaims(ctx, tokenClaims); err != nil {
+				return nil, err
+			}
+
+			caller = principal.Principal{
+				UserId:    tokenClaims.Id,
+				Email:     tokenClaims.Email,
+				Roles:     tokenClaims.Roles,
+				SessionId: tokenClaims.SessionId,
+				ActorId:   tokenClaims.ActorId,
+			}
+		}
+
+		if err := authorize(ctx, permissionsGetter, policy, caller, req); err != nil {
+			return nil, err
+		}
+
+		return handler(principal.WithPrincipal(ctx, caller), req)
+	}
+}
+
+func authorize(
+	ctx context.Context,
+	permissionsGetter dep.RolesPermissionsGetter,
+	policy MethodPolicy,
+	caller principal.Principal,
+	req interface{},
+) error {
+	if policy.DenyImpersonation && caller.IsImpersonated() {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("method is not allowed to impersonation tokens", ctxerrors.ErrForbidden))
+	}
+	if policy.Owner != nil && !caller.IsServiceAccount() && policy.Owner(req) == caller.UserId.String() {
+		return nil
+	}
+	if policy.Permission == "" {
+		if policy.Owner != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("caller acts on another account", ctxerrors.ErrForbidden))
+		}
+		return nil
+	}
+
+	permissions := caller.Scopes
+	if !caller.IsServiceAccount() {
+		var err error
+		permissions, err = permissionsGetter.RolesPermissions(ctx, caller.Roles)
+		if err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get caller permissions", err))
+		}
+	}
+	if !slices.Contains(permissions, string(policy.Permission)) {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("caller has no permission "+string(policy.Permission), ctxerrors.ErrForbidden))
+	}
+
+	return nil
+}
+
+// credentials returns the authorization metadata value of the given scheme.
+func credentials(ctx context.Context, scheme string) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(authorizationMdKey)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	if len(values[0]) <= len(scheme) || !strings.EqualFold(values[0][:len(scheme)], scheme) {
+		return "", false
+	}
+
+	return strings.TrimSpace(values[0][len(scheme):]), true
+}
+
+func hasCredentials(ctx context.Context) bool {
+	_, hasApiKey := credentials(ctx, apiKeyPrefix)
+	_, hasToken := credentials(ctx, bearerPrefix)
+
+	return hasApiKey || hasToken
+}
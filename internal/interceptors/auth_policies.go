@@ -0,0 +1,44 @@
+package interceptors
+
+import (
+	authv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/auth"
+	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/rbac"
+)
+
+// AuthPolicies lists the methods requiring authentication. Reads of public profiles and
+// the login flows are left public, the profile reads authenticate the optional caller to show it its private fields.
+// Impersonation tokens can't change the credentials or delete the account, and they carry no roles,
+// the permission-gated methods refuse them anyway.
+func AuthPolicies() map[string]MethodPolicy {
+	return map[string]MethodPolicy{
+		usersv1.UsersService_Subscribe_FullMethodName:         ownedBy((*usersv1.SubscribeDTO).GetSubscriberId),
+		usersv1.UsersService_Unsubscribe_FullMethodName:       ownedBy((*usersv1.SubscribeDTO).GetSubscriberId),
+		usersv1.UsersService_UpdateUser_FullMethodName:        ownedBy((*usersv1.UpdateUserDTO).GetId).orPermission(rbac.UsersUpdateAny),
+		usersv1.UsersService_UpdateUserV2_FullMethodName:      ownedBy((*usersv1.UpdateUserV2DTO).GetId).orPermission(rbac.UsersUpdateAny),
+		usersv1.UsersService_DeleteUser_FullMethodName:        ownedBy((*usersv1.DeleteUserDTO).GetId).orPermission(rbac.UsersDeleteAny).notImpersonated(),
+		usersv1.UsersService_UploadAvatar_FullMethodName:      ownedBy((*usersv1.UploadAvatarDTO).GetUserId),
+		usersv1.UsersService_GetUser_FullMethodName:           anonymous(),
+		usersv1.UsersService_GetUsers_FullMethodName:          anonymous(),
+		usersv1.UsersService_GetUserByUsername_FullMethodName: anonymous(),
+
+		authv1.Auth_RevokeUserTokens_FullMethodName:       ownedBy((*authv1.RevokeUserTokensDTO).GetUserId).orPermission(rbac.TokensRevokeAny).notImpersonated(),
+		authv1.Auth_UnlockAccount_FullMethodName:          requires(rbac.AccountsUnlock).notImpersonated(),
+		authv1.Auth_GrantRole_FullMethodName:              requires(rbac.RolesManage).notImpersonated(),
+		authv1.Auth_RevokeRole_FullMethodName:             requires(rbac.RolesManage).notImpersonated(),
+		authv1.Auth_CreateApiKey_FullMethodName:           requires(rbac.ApiKeysManage).notImpersonated(),
+		authv1.Auth_ListApiKeys_FullMethodName:            requires(rbac.ApiKeysManage).notImpersonated(),
+		authv1.Auth_RevokeApiKey_FullMethodName:           requires(rbac.ApiKeysManage).notImpersonated(),
+		authv1.Auth_Impersonate_FullMethodName:            requires(rbac.UsersImpersonate).notImpersonated(),
+		authv1.Auth_ChangePassword_FullMethodName:         ownedBy((*authv1.ChangePasswordDTO).GetUserId).notImpersonated(),
+		authv1.Auth_RequestEmailChange_FullMethodName:     ownedBy((*authv1.RequestEmailChangeDTO).GetUserId).notImpersonated(),
+		authv1.Auth_EnrollTotp_FullMethodName:             ownedBy((*authv1.EnrollTotpDTO).GetUserId).notImpersonated(),
+		authv1.Auth_ConfirmTotp_FullMethodName:            ownedBy((*authv1.ConfirmTotpDTO).GetUserId).notImpersonated(),
+		authv1.Auth_DisableTotp_FullMethodName:            ownedBy((*authv1.DisableTotpDTO).GetUserId).notImpersonated(),
+		authv1.Auth_ListSessions_FullMethodName:           ownedBy((*authv1.ListSessionsDTO).GetUserId),
+		authv1.Auth_RevokeSession_FullMethodName:          ownedBy((*authv1.RevokeSessionDTO).GetUserId),
+		authv1.Auth_RevokeAllOtherSessions_FullMethodName: ownedBy((*authv1.RevokeAllOtherSessionsDTO).GetUserId),
+		authv1.Auth_LinkIdentity_FullMethodName:           ownedBy((*authv1.LinkIdentityDTO).GetUserId).notImpersonated(),
+		authv1.Auth_UnlinkIdentity_FullMethodName:         ownedBy((*authv1.UnlinkIdentityDTO).GetUserId).notImpersonated(),
+	}
+}
@@ -0,0 +1,164 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/auth"
+	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/principal"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+const ownerId = "owner-id"
+
+type expectedPolicy struct {
+	// ownedReq is a request acting on ownerId, nil for policies without an owner
+	ownedReq          interface{}
+	permission        rbac.Permission
+	denyImpersonation bool
+	allowAnonymous    bool
+}
+
+func TestAuthPolicies(t *testing.T) {
+	expected := map[string]expectedPolicy{
+		usersv1.UsersService_Subscribe_FullMethodName:   {ownedReq: &usersv1.SubscribeDTO{SubscriberId: ownerId}},
+		usersv1.UsersService_Unsubscribe_FullMethodName: {ownedReq: &usersv1.SubscribeDTO{SubscriberId: ownerId}},
+		usersv1.UsersService_UpdateUser_FullMethodName: {
+			ownedReq: &usersv1.UpdateUserDTO{Id: ownerId}, permission: rbac.UsersUpdateAny,
+		},
+		usersv1.UsersService_UpdateUserV2_FullMethodName: {
+			ownedReq: &usersv1.UpdateUserV2DTO{Id: ownerId}, permission: rbac.UsersUpdateAny,
+		},
+		usersv1.UsersService_DeleteUser_FullMethodName: {
+			ownedReq: &usersv1.DeleteUserDTO{Id: ownerId}, permission: rbac.UsersDeleteAny, denyImpersonation: true,
+		},
+		usersv1.UsersService_UploadAvatar_FullMethodName:      {ownedReq: &usersv1.UploadAvatarDTO{UserId: ownerId}},
+		usersv1.UsersService_GetUser_FullMethodName:           {allowAnonymous: true},
+		usersv1.UsersService_GetUsers_FullMethodName:          {allowAnonymous: true},
+		usersv1.UsersService_GetUserByUsername_FullMethodName: {allowAnonymous: true},
+
+		authv1.Auth_RevokeUserTokens_FullMethodName: {
+			ownedReq: &authv1.RevokeUserTokensDTO{UserId: ownerId}, permission: rbac.TokensRevokeAny, denyImpersonation: true,
+		},
+		authv1.Auth_UnlockAccount_FullMethodName: {permission: rbac.AccountsUnlock, denyImpersonation: true},
+		authv1.Auth_GrantRole_FullMethodName:     {permission: rbac.RolesManage, denyImpersonation: true},
+		authv1.Auth_RevokeRole_FullMethodName:    {permission: rbac.RolesManage, denyImpersonation: true},
+		authv1.Auth_CreateApiKey_FullMethodName:  {permission: rbac.ApiKeysManage, denyImpersonation: true},
+		authv1.Auth_ListApiKeys_FullMethodName:   {permission: rbac.ApiKeysManage, denyImpersonation: true},
+		authv1.Auth_RevokeApiKey_FullMethodName:  {permission: rbac.ApiKeysManage, denyImpersonation: true},
+		authv1.Auth_Impersonate_FullMethodName:   {permission: rbac.UsersImpersonate, denyImpersonation: true},
+		authv1.Auth_ChangePassword_FullMethodName: {
+			ownedReq: &authv1.ChangePasswordDTO{UserId: ownerId}, denyImpersonation: true,
+		},
+		authv1.Auth_RequestEmailChange_FullMethodName: {
+			ownedReq: &authv1.RequestEmailChangeDTO{UserId: ownerId}, denyImpersonation: true,
+		},
+		authv1.Auth_EnrollTotp_FullMethodName:             {ownedReq: &authv1.EnrollTotpDTO{UserId: ownerId}, denyImpersonation: true},
+		authv1.Auth_ConfirmTotp_FullMethodName:            {ownedReq: &authv1.ConfirmTotpDTO{UserId: ownerId}, denyImpersonation: true},
+		authv1.Auth_DisableTotp_FullMethodName:            {ownedReq: &authv1.DisableTotpDTO{UserId: ownerId}, denyImpersonation: true},
+		authv1.Auth_ListSessions_FullMethodName:           {ownedReq: &authv1.ListSessionsDTO{UserId: ownerId}},
+		authv1.Auth_RevokeSession_FullMethodName:          {ownedReq: &authv1.RevokeSessionDTO{UserId: ownerId}},
+		authv1.Auth_RevokeAllOtherSessions_FullMethodName: {ownedReq: &authv1.RevokeAllOtherSessionsDTO{UserId: ownerId}},
+		authv1.Auth_LinkIdentity_FullMethodName:           {ownedReq: &authv1.LinkIdentityDTO{UserId: ownerId}, denyImpersonation: true},
+		authv1.Auth_UnlinkIdentity_FullMethodName:         {ownedReq: &authv1.UnlinkIdentityDTO{UserId: ownerId}, denyImpersonation: true},
+	}
+
+	policies := AuthPolicies()
+
+	for method := range policies {
+		if _, ok := expected[method]; !ok {
+			t.Errorf("%s has a policy the test doesn't expect", method)
+		}
+	}
+
+	for method, want := range expected {
+		t.Run(method, func(t *testing.T) {
+			policy, ok := policies[method]
+			if !ok {
+				t.Fatalf("policy is missing")
+			}
+
+			if policy.Permission != want.permission {
+				t.Errorf("Permission = %q, want %q", policy.Permission, want.permission)
+			}
+			if policy.DenyImpersonation != want.denyImpersonation {
+				t.Errorf("DenyImpersonation = %t, want %t", policy.DenyImpersonation, want.denyImpersonation)
+			}
+			if policy.AllowAnonymous != want.allowAnonymous {
+				t.Errorf("AllowAnonymous = %t, want %t", policy.AllowAnonymous, want.allowAnonymous)
+			}
+
+			if want.ownedReq == nil {
+				if policy.Owner != nil {
+					t.Errorf("Owner is set, want none")
+				}
+				return
+			}
+			if policy.Owner == nil {
+				t.Fatalf("Owner is not set")
+			}
+			if owner := policy.Owner(want.ownedReq); owner != ownerId {
+				t.Errorf("Owner = %q, want %q", owner, ownerId)
+			}
+			if owner := policy.Owner(struct{}{}); owner != "" {
+				t.Errorf("Owner of a foreign request = %q, want none", owner)
+			}
+		})
+	}
+}
+
+type fakePermissionsGetter map[string][]string
+
+func (f fakePermissionsGetter) RolesPermissions(_ context.Context, roles []string) ([]string, error) {
+	var permissions []string
+	for _, role := range roles {
+		permissions = append(permissions, f[role]...)
+	}
+	return permissions, nil
+}
+
+func TestAuthorizeRoleManagement(t *testing.T) {
+	permissions := fakePermissionsGetter{
+		rbac.AdminRole:     {string(rbac.RolesManage), string(rbac.UsersUpdateAny)},
+		rbac.ModeratorRole: {string(rbac.UsersUpdateAny)},
+	}
+
+	tests := []struct {
+		name    string
+		caller  principal.Principal
+		wantErr error
+	}{
+		{name: "admin", caller: principal.Principal{UserId: uuid.New(), Roles: []string{rbac.AdminRole}}},
+		{name: "moderator", caller: principal.Principal{UserId: uuid.New(), Roles: []string{rbac.ModeratorRole}}, wantErr: ctxerrors.ErrForbidden},
+		{name: "no roles", caller: principal.Principal{UserId: uuid.New()}, wantErr: ctxerrors.ErrForbidden},
+		{
+			name:    "impersonated admin",
+			caller:  principal.Principal{UserId: uuid.New(), Roles: []string{rbac.AdminRole}, ActorId: uuid.New()},
+			wantErr: ctxerrors.ErrForbidden,
+		},
+		{name: "service account with scope", caller: principal.Principal{ServiceAccount: "svc", Scopes: []string{string(rbac.RolesManage)}}},
+		{name: "service account without scope", caller: principal.Principal{ServiceAccount: "svc"}, wantErr: ctxerrors.ErrForbidden},
+	}
+
+	policies := AuthPolicies()
+
+	for _, method := range []string{authv1.Auth_GrantRole_FullMethodName, authv1.Auth_RevokeRole_FullMethodName} {
+		for _, tt := range tests {
+			t.Run(method+"/"+tt.name, func(t *testing.T) {
+				req := &authv1.GrantRoleDTO{UserId: tt.caller.UserId.String(), Role: rbac.AdminRole}
+
+				err := authorize(context.Background(), permissions, policies[method], tt.caller, req)
+				if tt.wantErr == nil && err != nil {
+					t.Fatalf("authorize: %v", err)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("authorize err = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package interceptors
+
+import (
+	"context"
+	handlersutils "github.com/KBcHMFollower/blog_user_service/internal/handlers/lib"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"strings"
+)
+
+// ClientIpInterceptor takes the client ip from the metadata key the gateway sets, behind the gateway
+// the connected peer is the gateway itself. The last address of a list is used, it is the one appended
+// by the gateway, the previous ones come from the client. Nothing is done when trustedMdKey is empty.
+func ClientIpInterceptor(trustedMdKey string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if trustedMdKey == "" {
+			return handler(ctx, req)
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		values := md.Get(trustedMdKey)
+		if len(values) == 0 {
+			return handler(ctx, req)
+		}
+
+		addresses := strings.Split(values[len(values)-1], ",")
+		ip := strings.TrimSpace(addresses[len(addresses)-1])
+		if net.ParseIP(ip) == nil {
+			return handler(ctx, req)
+		}
+
+		return handler(handlersutils.WithClientIp(ctx, ip), req)
+	}
+}
@@ -15,10 +15,13 @@ type ErrorsTransformer struct {
 
 func NewErrorsTransformer() *ErrorsTransformer {
 	return &ErrorsTransformer{errorMap: map[error]error{
-		ctxerrors.ErrBadRequest:   status.Error(codes.InvalidArgument, "bad request"),
-		ctxerrors.ErrUnauthorized: status.Error(codes.Unauthenticated, "unauthorized"),
-		ctxerrors.ErrNotFound:     status.Error(codes.NotFound, "not found"),
-		ctxerrors.ErrConflict:     status.Error(codes.AlreadyExists, "already exists"),
+		ctxerrors.ErrBadRequest:      status.Error(codes.InvalidArgument, "bad request"),
+		ctxerrors.ErrUnauthorized:    status.Error(codes.Unauthenticated, "unauthorized"),
+		ctxerrors.ErrForbidden:       status.Error(codes.PermissionDenied, "permission denied"),
+		ctxerrors.ErrNotFound:        status.Error(codes.NotFound, "not found"),
+		ctxerrors.ErrConflict:        status.Error(codes.AlreadyExists, "already exists"),
+		ctxerrors.ErrTooManyRequests: status.Error(codes.ResourceExhausted, "too many requests"),
+		ctxerrors.ErrAborted:         status.Error(codes.Aborted, "aborted"),
 	}}
 }
 
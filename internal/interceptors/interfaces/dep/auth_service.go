@@ -0,0 +1,15 @@
+package dep
+
+import (
+	"context"
+	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+)
+
+type ClaimsChecker interface {
+	CheckClaims(ctx context.Context, tokenClaims tokenshelper.TokenClaims) error
+}
+
+type ApiKeyAuthenticator interface {
+	AuthenticateApiKey(ctx context.Context, key string) (*servicestransfer.ApiKeyResult, error)
+}
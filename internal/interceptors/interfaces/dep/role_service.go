@@ -0,0 +1,9 @@
+package dep
+
+import (
+	"context"
+)
+
+type RolesPermissionsGetter interface {
+	RolesPermissions(ctx context.Context, roles []string) ([]string, error)
+}
@@ -2,6 +2,7 @@ package interceptors
 
 import (
 	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/principal"
 	"github.com/KBcHMFollower/blog_user_service/internal/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -9,7 +10,8 @@ import (
 )
 
 const (
-	methodLogKey = "method"
+	methodLogKey   = "method"
+	reqActorLogKey = "req-actor"
 )
 
 func ReqLoggingInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
@@ -25,10 +27,19 @@ func ReqLoggingInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
 		}
 
 		reqId := getInfoFromMd(md, "req-id")
-		userId := getInfoFromMd(md, "user-id")
+		userId := "undefined"
+		if caller, ok := principal.FromContext(ctx); ok {
+			userId = caller.UserId.String()
+			if caller.IsServiceAccount() {
+				userId = "service:" + caller.ServiceAccount
+			}
+		}
 
 		ctx = logger.UpdateLoggerCtx(ctx, logger.ReqIdKey, reqId)
 		ctx = logger.UpdateLoggerCtx(ctx, logger.ReqUserKey, userId)
+		if caller, ok := principal.FromContext(ctx); ok && caller.IsImpersonated() {
+			ctx = logger.UpdateLoggerCtx(ctx, reqActorLogKey, caller.ActorId.String())
+		}
 		ctx = logger.UpdateLoggerCtx(ctx, methodLogKey, info.FullMethod)
 
 		log.InfoContext(ctx, "--Method starting execution--", "data", req)
@@ -0,0 +1,98 @@
+package passwords_helper
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	sha1PrefixLength = 5
+	rangeFileExt     = ".txt"
+)
+
+// BreachedList keeps the sha1 hashes of breached passwords in memory. It is loaded from a directory
+// of k-anonymity range files: <5 hex chars prefix>.txt with "<35 hex chars suffix>:<count>" lines,
+// the format of the HIBP range api and of the files its downloader writes.
+type BreachedList struct {
+	hashes map[[sha1.Size]byte]struct{}
+}
+
+// LoadBreachedList reads the range files from dir, hashes seen fewer than minCount times are skipped.
+func LoadBreachedList(dir string, minCount int) (*BreachedList, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+rangeFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("error in listing breached passwords files: %v", err)
+	}
+
+	list := &BreachedList{
+		hashes: make(map[[sha1.Size]byte]struct{}),
+	}
+
+	for _, file := range files {
+		prefix := strings.ToUpper(strings.TrimSuffix(filepath.Base(file), rangeFileExt))
+		if len(prefix) != sha1PrefixLength {
+			continue
+		}
+
+		if err := list.loadRangeFile(file, prefix, minCount); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (l *BreachedList) IsBreached(password string) bool {
+	_, ok := l.hashes[sha1.Sum([]byte(password))]
+	return ok
+}
+
+func (l *BreachedList) loadRangeFile(path string, prefix string, minCount int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error in opening breached passwords file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		suffix, countStr, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("malformed line in breached passwords file %s: %q", path, line)
+		}
+
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return fmt.Errorf("malformed count in breached passwords file %s: %q", path, line)
+		}
+		if count < minCount {
+			continue
+		}
+
+		decoded, err := hex.DecodeString(prefix + suffix)
+		if err != nil || len(decoded) != sha1.Size {
+			return fmt.Errorf("malformed hash in breached passwords file %s: %q", path, line)
+		}
+
+		var hash [sha1.Size]byte
+		copy(hash[:], decoded)
+		l.hashes[hash] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error in reading breached passwords file %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package passwords_helper
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+)
+
+// Hasher hashes new passwords and verifies the stored hashes.
+type Hasher interface {
+	Hash(password string) ([]byte, error)
+	// Verify reports whether the password matches the hash, a malformed hash is an error.
+	Verify(hash []byte, password string) (bool, error)
+	// NeedsRehash reports whether the hash was produced by another algorithm or with outdated parameters.
+	NeedsRehash(hash []byte) bool
+}
+
+// Algorithm is a Hasher that can tell its own hashes apart from the hashes of other algorithms.
+type Algorithm interface {
+	Hasher
+	Recognizes(hash []byte) bool
+}
+
+// MultiHasher hashes new passwords with the primary algorithm and still verifies the hashes
+// of the legacy ones, every legacy hash needs a rehash.
+type MultiHasher struct {
+	primary Algorithm
+	legacy  []Algorithm
+}
+
+func NewMultiHasher(primary Algorithm, legacy ...Algorithm) *MultiHasher {
+	return &MultiHasher{
+		primary: primary,
+		legacy:  legacy,
+	}
+}
+
+func (h *MultiHasher) Hash(password string) ([]byte, error) {
+	return h.primary.Hash(password)
+}
+
+func (h *MultiHasher) Verify(hash []byte, password string) (bool, error) {
+	algorithm, ok := h.algorithm(hash)
+	if !ok {
+		return false, ErrUnknownHashFormat
+	}
+
+	return algorithm.Verify(hash, password)
+}
+
+func (h *MultiHasher) NeedsRehash(hash []byte) bool {
+	return !h.primary.Recognizes(hash) || h.primary.NeedsRehash(hash)
+}
+
+func (h *MultiHasher) algorithm(hash []byte) (Algorithm, bool) {
+	if h.primary.Recognizes(hash) {
+		return h.primary, true
+	}
+
+	for _, algorithm := range h.legacy {
+		if algorithm.Recognizes(hash) {
+			return algorithm, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,150 @@
+package passwords_helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrWeakPassword = errors.New("password does not satisfy the policy")
+)
+
+// minPersonalInfoLength keeps short names from rejecting too many passwords.
+const minPersonalInfoLength = 3
+
+type PolicyRule string
+
+const (
+	MinLengthRule    PolicyRule = "min_length"
+	MaxLengthRule    PolicyRule = "max_length"
+	UpperRule        PolicyRule = "upper"
+	LowerRule        PolicyRule = "lower"
+	DigitRule        PolicyRule = "digit"
+	SpecialRule      PolicyRule = "special"
+	PersonalInfoRule PolicyRule = "personal_info"
+	BreachedRule     PolicyRule = "breached"
+)
+
+// PolicyError is the rule the password breaks, Limit is set for the length rules.
+type PolicyError struct {
+	Rule  PolicyRule
+	Limit int
+}
+
+func (e *PolicyError) Error() string {
+	var reason string
+	switch e.Rule {
+	case MinLengthRule:
+		reason = fmt.Sprintf("must be at least %d characters long", e.Limit)
+	case MaxLengthRule:
+		reason = fmt.Sprintf("must be at most %d characters long", e.Limit)
+	case UpperRule:
+		reason = "must contain an upper case letter"
+	case LowerRule:
+		reason = "must contain a lower case letter"
+	case DigitRule:
+		reason = "must contain a digit"
+	case SpecialRule:
+		reason = "must contain a special character"
+	case PersonalInfoRule:
+		reason = "must not contain the email or the name"
+	case BreachedRule:
+		reason = "is found in a data breach"
+	default:
+		reason = string(e.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrWeakPassword.Error(), reason)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// BreachedChecker reports passwords known from data breaches.
+type BreachedChecker interface {
+	IsBreached(password string) bool
+}
+
+// Policy is checked for every new password. MaxLength bounds the hashing cost of a request,
+// Breached is optional.
+type Policy struct {
+	MinLength            int
+	MaxLength            int
+	RequireUpper         bool
+	RequireLower         bool
+	RequireDigit         bool
+	RequireSpecial       bool
+	DisallowPersonalInfo bool
+	Breached             BreachedChecker
+}
+
+// Validate returns a *PolicyError with the first rule the password breaks. Personal is the email
+// and the names of the user, the password must not contain them when DisallowPersonalInfo is set.
+func (p Policy) Validate(password string, personal ...string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &PolicyError{Rule: MinLengthRule, Limit: p.MinLength}
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &PolicyError{Rule: MaxLengthRule, Limit: p.MaxLength}
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		return &PolicyError{Rule: UpperRule}
+	}
+	if p.RequireLower && !hasLower {
+		return &PolicyError{Rule: LowerRule}
+	}
+	if p.RequireDigit && !hasDigit {
+		return &PolicyError{Rule: DigitRule}
+	}
+	if p.RequireSpecial && !hasSpecial {
+		return &PolicyError{Rule: SpecialRule}
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, personal) {
+		return &PolicyError{Rule: PersonalInfoRule}
+	}
+
+	if p.Breached != nil && p.Breached.IsBreached(password) {
+		return &PolicyError{Rule: BreachedRule}
+	}
+
+	return nil
+}
+
+// containsPersonalInfo compares case-insensitively, only the local part of an email is looked for.
+func containsPersonalInfo(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, info := range personal {
+		info = strings.ToLower(strings.TrimSpace(info))
+		if local, _, ok := strings.Cut(info, "@"); ok {
+			info = local
+		}
+
+		if utf8.RuneCountInString(info) >= minPersonalInfoLength && strings.Contains(lowered, info) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package secrets_helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// AesCipher encrypts secrets stored in the db with AES-256-GCM, the nonce is prepended to the ciphertext.
+type AesCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAesCipher takes a base64 encoded 32 bytes key.
+func NewAesCipher(encodedKey string) (*AesCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("can`t decode key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes long, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("can`t create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("can`t create gcm: %w", err)
+	}
+
+	return &AesCipher{aead: aead}, nil
+}
+
+func (c *AesCipher) Encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("can`t generate nonce: %w", err)
+	}
+
+	return c.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func (c *AesCipher) Decrypt(encrypted []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(encrypted) < nonceSize {
+		return nil, fmt.Errorf("encrypted data is too short")
+	}
+
+	plain, err := c.aead.Open(nil, encrypted[:nonceSize], encrypted[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("can`t decrypt: %w", err)
+	}
+
+	return plain, nil
+}
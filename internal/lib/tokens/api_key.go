@@ -0,0 +1,43 @@
+package tokens_helper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	apiKeyPrefixBytes = 6
+	apiKeySeparator   = "."
+)
+
+// NewApiKey returns the public prefix the key is looked up by and the secret part of a new api key.
+// The key handed to the client is FormatApiKey(prefix, secret).
+func NewApiKey() (string, string, error) {
+	b := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error in generating api key prefix: %v", err)
+	}
+
+	secret, err := NewOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(b), secret, nil
+}
+
+func FormatApiKey(prefix string, secret string) string {
+	return prefix + apiKeySeparator + secret
+}
+
+// ParseApiKey splits the key into its prefix and secret parts, ok is false for malformed keys.
+func ParseApiKey(key string) (string, string, bool) {
+	prefix, secret, ok := strings.Cut(key, apiKeySeparator)
+	if !ok || prefix == "" || secret == "" {
+		return "", "", false
+	}
+
+	return prefix, secret, true
+}
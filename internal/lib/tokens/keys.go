@@ -0,0 +1,262 @@
+package tokens_helper
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	jwkUseSignature = "sig"
+	jwkKtyRSA       = "RSA"
+	jwkKtyOKP       = "OKP"
+	jwkCrvEd25519   = "Ed25519"
+)
+
+// KeyInfo describes a key file pair from the config. Keys without a private key
+// are only used to verify tokens signed before a rotation.
+type KeyInfo struct {
+	Kid            string
+	Algorithm      string
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
+type SigningKey struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type JWK struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewHmacKeySet keeps the legacy behaviour: tokens are signed with a shared secret and have no kid.
+func NewHmacKeySet(secret string) *KeySet {
+	key := &SigningKey{
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+
+	return &KeySet{
+		active: key,
+		keys:   map[string]*SigningKey{key.Kid: key},
+	}
+}
+
+func LoadKeySet(activeKid string, infos []KeyInfo) (*KeySet, error) {
+	keySet := &KeySet{
+		keys: make(map[string]*SigningKey, len(infos)),
+	}
+
+	for _, info := range infos {
+		key, err := loadSigningKey(info)
+		if err != nil {
+			return nil, fmt.Errorf("can`t load key `%s`: %w", info.Kid, err)
+		}
+		if _, ok := keySet.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("duplicated key id `%s`", key.Kid)
+		}
+
+		keySet.keys[key.Kid] = key
+	}
+
+	active, ok := keySet.keys[activeKid]
+	if !ok {
+		return nil, fmt.Errorf("active key `%s` is not configured", activeKid)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active key `%s` has no private key", activeKid)
+	}
+
+	keySet.active = active
+
+	return keySet, nil
+}
+
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+func (ks *KeySet) VerificationKey(kid string) (*SigningKey, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// PublicJWKs returns every asymmetric verification key, shared secrets are never exposed.
+func (ks *KeySet) PublicJWKs() []JWK {
+	jwks := make([]JWK, 0, len(ks.keys))
+
+	for _, key := range ks.keys {
+		switch publicKey := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: jwkKtyRSA,
+				Kid: key.Kid,
+				Use: jwkUseSignature,
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: jwkKtyOKP,
+				Kid: key.Kid,
+				Use: jwkUseSignature,
+				Alg: key.Method.Alg(),
+				Crv: jwkCrvEd25519,
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return jwks
+}
+
+func loadSigningKey(info KeyInfo) (*SigningKey, error) {
+	if info.Kid == "" {
+		return nil, fmt.Errorf("kid is empty")
+	}
+
+	var method jwt.SigningMethod
+	switch info.Algorithm {
+	case AlgRS256:
+		method = jwt.SigningMethodRS256
+	case AlgEdDSA:
+		method = SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm `%s`", info.Algorithm)
+	}
+
+	key := &SigningKey{
+		Kid:    info.Kid,
+		Method: method,
+	}
+
+	if info.PrivateKeyPath != "" {
+		privateKey, err := readPrivateKey(info.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key is not a signer")
+		}
+
+		key.signKey = privateKey
+		key.verifyKey = signer.Public()
+	}
+
+	if info.PublicKeyPath != "" {
+		publicKey, err := readPublicKey(info.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		key.verifyKey = publicKey
+	}
+
+	if key.verifyKey == nil {
+		return nil, fmt.Errorf("neither private nor public key is set")
+	}
+
+	if err := checkKeyType(info.Algorithm, key.verifyKey); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func checkKeyType(alg string, publicKey crypto.PublicKey) error {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if alg == AlgRS256 {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == AlgEdDSA {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key type %T does not match algorithm `%s`", publicKey, alg)
+}
+
+func readPrivateKey(path string) (interface{}, error) {
+	block, err := readPemBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("can`t parse private key: %w", err)
+	}
+
+	return key, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPemBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("can`t parse public key: %w", err)
+	}
+
+	return key, nil
+}
+
+func readPemBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can`t read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem data in `%s`", path)
+	}
+
+	return block, nil
+}
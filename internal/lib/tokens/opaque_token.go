@@ -0,0 +1,25 @@
+package tokens_helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+const opaqueTokenBytes = 32
+
+// NewOpaqueToken returns a random url-safe token. Only its hash (see HashOpaqueToken) is meant to be stored.
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error in generating opaque token: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func HashOpaqueToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
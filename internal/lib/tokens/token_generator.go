@@ -8,20 +8,105 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	kidHeader = "kid"
+)
+
+// JwtOptions are the service wide settings every issued token is signed and checked with.
+type JwtOptions struct {
+	Keys     *KeySet
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+type NewTokenInfo struct {
+	UserId    uuid.UUID
+	Email     string
+	Roles     []string
+	SessionId uuid.UUID
+	Version   int
+	// EmailVerified lets downstream services decide what unverified users may do.
+	EmailVerified bool
+	// ActorId is set for impersonation tokens, it is the admin acting as the user.
+	ActorId uuid.UUID
+	// Jti is generated when it is not set.
+	Jti uuid.UUID
+}
+
 type TokenClaims struct {
-	Email string
-	Id    uuid.UUID
+	Email         string
+	Id            uuid.UUID
+	Roles         []string
+	SessionId     uuid.UUID
+	Jti           uuid.UUID
+	Version       int
+	EmailVerified bool
+	ActorId       uuid.UUID
+	Issuer        string
+	Audience      string
+	IssuedAt      time.Time
+	NotBefore     time.Time
+	ExpiresAt     time.Time
+}
+
+type jwtClaims struct {
+	jwt.StandardClaims
+	UserId        uuid.UUID `json:"user_id"`
+	Email         string    `json:"email"`
+	Roles         []string  `json:"roles,omitempty"`
+	SessionId     uuid.UUID `json:"sid"`
+	Version       int       `json:"ver"`
+	EmailVerified bool      `json:"email_verified"`
+	Act           *actClaim `json:"act,omitempty"`
+}
+
+// actClaim is the actor claim of RFC 8693, sub is the id of the acting user.
+type actClaim struct {
+	Subject string `json:"sub"`
 }
 
-func CreateNewJwt(userId uuid.UUID, email string, tokenTTL time.Duration, tokenSecret string) (string, error) {
+func (tc TokenClaims) IsImpersonation() bool {
+	return tc.ActorId != uuid.Nil
+}
+
+func CreateNewJwt(info NewTokenInfo, opts JwtOptions) (string, error) {
+	signingKey := opts.Keys.Active()
+	now := time.Now()
+
+	jti := info.Jti
+	if jti == uuid.Nil {
+		jti = uuid.New()
+	}
+
+	var act *actClaim
+	if info.ActorId != uuid.Nil {
+		act = &actClaim{Subject: info.ActorId.String()}
+	}
 
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["user_id"] = userId
-	claims["email"] = email
-	claims["exp"] = time.Now().Add(tokenTTL).Unix()
-	tokenString, err := token.SignedString([]byte(tokenSecret))
+	token := jwt.NewWithClaims(signingKey.Method, jwtClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti.String(),
+			Subject:   info.UserId.String(),
+			Issuer:    opts.Issuer,
+			Audience:  opts.Audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(opts.TTL).Unix(),
+		},
+		UserId:        info.UserId,
+		Email:         info.Email,
+		Roles:         info.Roles,
+		SessionId:     info.SessionId,
+		Version:       info.Version,
+		EmailVerified: info.EmailVerified,
+		Act:           act,
+	})
+	if signingKey.Kid != "" {
+		token.Header[kidHeader] = signingKey.Kid
+	}
 
+	tokenString, err := token.SignedString(signingKey.signKey)
 	if err != nil {
 		return "", fmt.Errorf("error in creating  jwt proccess: %v", err)
 	}
@@ -29,47 +114,70 @@ func CreateNewJwt(userId uuid.UUID, email string, tokenTTL time.Duration, tokenS
 	return tokenString, nil
 }
 
-func Parse(tokenString string, tokenSecret string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+// Parse checks the signature and exp/iat/nbf/iss/aud of the token and returns its claims.
+func Parse(tokenString string, opts JwtOptions) (TokenClaims, error) {
+	var claims jwtClaims
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header[kidHeader].(string)
+
+		verificationKey, ok := opts.Keys.VerificationKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %v", kid)
+		}
+
 		// Проверка метода подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != verificationKey.Method.Alg() {
 			return nil, fmt.Errorf("unaviable sign-metod: %v", token.Header["alg"])
 		}
-		return []byte(tokenSecret), nil
+		return verificationKey.verifyKey, nil
 	})
 	if err != nil {
-		return nil, err
+		return TokenClaims{}, err
 	}
-
-	if token.Valid {
-		return token, nil
+	if !token.Valid {
+		return TokenClaims{}, fmt.Errorf("token is invalid")
 	}
-	return nil, err
-}
 
-func GetClaimsValues(token *jwt.Token) (TokenClaims, error) {
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return TokenClaims{}, fmt.Errorf("can`t parse claims")
+	if !claims.VerifyIssuer(opts.Issuer, true) {
+		return TokenClaims{}, fmt.Errorf("unexpected issuer: %s", claims.Issuer)
 	}
-
-	userIdString, ok := claims["user_id"].(string)
-	if !ok {
+	if !claims.VerifyAudience(opts.Audience, true) {
+		return TokenClaims{}, fmt.Errorf("unexpected audience: %s", claims.Audience)
+	}
+	if claims.IssuedAt == 0 || claims.ExpiresAt == 0 {
+		return TokenClaims{}, fmt.Errorf("iat and exp are required")
+	}
+	if claims.UserId == uuid.Nil {
 		return TokenClaims{}, fmt.Errorf("can`t convert  user_id")
 	}
 
-	userId, err := uuid.Parse(userIdString)
+	jti, err := uuid.Parse(claims.Id)
 	if err != nil {
-		return TokenClaims{}, fmt.Errorf("%w", err)
+		return TokenClaims{}, fmt.Errorf("can`t convert jti: %w", err)
 	}
 
-	userEmail, ok := claims["user_id"].(string)
-	if !ok {
-		return TokenClaims{}, fmt.Errorf("%w", err)
+	var actorId uuid.UUID
+	if claims.Act != nil {
+		actorId, err = uuid.Parse(claims.Act.Subject)
+		if err != nil {
+			return TokenClaims{}, fmt.Errorf("can`t convert act: %w", err)
+		}
 	}
 
 	return TokenClaims{
-		Email: userEmail,
-		Id:    userId,
+		Email:         claims.Email,
+		Id:            claims.UserId,
+		Roles:         claims.Roles,
+		SessionId:     claims.SessionId,
+		Jti:           jti,
+		Version:       claims.Version,
+		EmailVerified: claims.EmailVerified,
+		ActorId:       actorId,
+		Issuer:        claims.Issuer,
+		Audience:      claims.Audience,
+		IssuedAt:      time.Unix(claims.IssuedAt, 0),
+		NotBefore:     time.Unix(claims.NotBefore, 0),
+		ExpiresAt:     time.Unix(claims.ExpiresAt, 0),
 	}, nil
 }
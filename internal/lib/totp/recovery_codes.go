@@ -0,0 +1,38 @@
+package totp_helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+const (
+	RecoveryCodesCount = 10
+	recoveryCodeSize   = 5
+)
+
+// GenerateRecoveryCodes returns codes formatted as xxxx-xxxx, they are shown to the user only once.
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodesCount)
+
+	for i := 0; i < RecoveryCodesCount; i++ {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("can`t generate recovery code: %w", err)
+		}
+
+		code := strings.ToLower(b32.EncodeToString(raw))
+		codes = append(codes, fmt.Sprintf("%s-%s", code[:4], code[4:]))
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode ignores case and dashes, so the code can be typed the way the user likes.
+func HashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(normalized))
+
+	return hash[:]
+}
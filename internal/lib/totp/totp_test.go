@@ -0,0 +1,78 @@
+package totp_helper
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of RFC 6238 appendix B.
+var rfcSecret = b32.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC lists 8 digit codes, the 6 digit code is the last 6 digits of them.
+func TestGenerateCodeRfcVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("GenerateCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+
+		step, ok, err := Validate(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Validate(%d): %v", tt.unix, err)
+		}
+		if !ok || step != Step(time.Unix(tt.unix, 0)) {
+			t.Errorf("Validate(%d) = %d, %t, want %d, true", tt.unix, step, ok, Step(time.Unix(tt.unix, 0)))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	tests := []struct {
+		name     string
+		offset   int64
+		accepted bool
+	}{
+		{name: "current step", offset: 0, accepted: true},
+		{name: "previous step", offset: -1, accepted: true},
+		{name: "next step", offset: 1, accepted: true},
+		{name: "two steps back", offset: -2, accepted: false},
+		{name: "two steps ahead", offset: 2, accepted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := GenerateCode(rfcSecret, now.Add(time.Duration(tt.offset)*Period))
+			if err != nil {
+				t.Fatalf("GenerateCode: %v", err)
+			}
+
+			step, ok, err := Validate(rfcSecret, code, now)
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if ok != tt.accepted {
+				t.Fatalf("Validate accepted = %t, want %t", ok, tt.accepted)
+			}
+			if ok && step != Step(now)+tt.offset {
+				t.Errorf("Validate step = %d, want %d", step, Step(now)+tt.offset)
+			}
+		})
+	}
+}
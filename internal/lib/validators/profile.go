@@ -0,0 +1,129 @@
+package validators
+
+import (
+	"fmt"
+	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ProfileLinksMaxCount = 5
+	webLinkMaxLength     = 2048
+
+	birthdayTag           validationTag = "birthday"
+	webLinkTag            validationTag = "weblink"
+	userUpdateValuesTag   validationTag = "mapvalues-user-update"
+	userUpdateValuesError               = "{0} has invalid values of {1}"
+)
+
+var minBirthday = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// userUpdateRules are the rules of the values UpdateUser sets, the username is checked on rename by the service.
+var userUpdateRules = map[servicestransfer.UserFieldTarget]string{
+	servicestransfer.UserBioUpdateTarget:                "max=500",
+	servicestransfer.UserLinksUpdateTarget:              fmt.Sprintf("max=%d,dive,%s", ProfileLinksMaxCount, webLinkTag),
+	servicestransfer.UserLocationUpdateTarget:           "max=100",
+	servicestransfer.UserBirthdayUpdateTarget:           "omitempty," + string(birthdayTag),
+	servicestransfer.UserBirthdayVisibilityUpdateTarget: "oneof=" + string(models.BirthdayPublic) + " " + string(models.BirthdayPrivate),
+	servicestransfer.UserPronounsUpdateTarget:           "max=40",
+	servicestransfer.UserCoverUpdateTarget:              "omitempty," + string(webLinkTag),
+}
+
+// registerProfileValidations registers the profile tags and the per-field rules of the user update map.
+func registerProfileValidations(v *validator.Validate, trans ut.Translator) error {
+	validations := map[validationTag]validator.Func{
+		birthdayTag:         validateBirthday,
+		webLinkTag:          validateWebLink,
+		userUpdateValuesTag: validateMapValues(v, userUpdateRules),
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(string(tag), fn); err != nil {
+			return err
+		}
+	}
+
+	return v.RegisterTranslation(string(userUpdateValuesTag), trans, func(ut ut.Translator) error {
+		return ut.Add(userUpdateValuesTag, userUpdateValuesError, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		fields, _ := fe.Value().(map[servicestransfer.UserFieldTarget]any)
+
+		invalid := make([]string, 0, len(fields))
+		for _, key := range invalidMapValues(v, userUpdateRules, fields) {
+			invalid = append(invalid, string(key))
+		}
+
+		t, _ := ut.T(userUpdateValuesTag, fe.Field(), strings.Join(invalid, ", "))
+		return t
+	})
+}
+
+// validateBirthday accepts dates in the models.BirthdayLayout format between 1900 and today.
+func validateBirthday(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	birthday, err := time.Parse(models.BirthdayLayout, value)
+	if err != nil {
+		return false
+	}
+
+	return !birthday.Before(minBirthday) && !birthday.After(time.Now())
+}
+
+func validateWebLink(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return isWebLink(value)
+}
+
+func isWebLink(link string) bool {
+	if len(link) > webLinkMaxLength {
+		return false
+	}
+
+	u, err := url.ParseRequestURI(link)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateMapValues checks every value of the map with the rule of its key, keys without a rule are not checked.
+func validateMapValues[T ~string](v *validator.Validate, rules map[T]string) func(fl validator.FieldLevel) bool {
+	return func(fl validator.FieldLevel) bool {
+		fields, ok := fl.Field().Interface().(map[T]any)
+		if !ok {
+			return false
+		}
+
+		return len(invalidMapValues(v, rules, fields)) == 0
+	}
+}
+
+func invalidMapValues[T ~string](v *validator.Validate, rules map[T]string, fields map[T]any) []T {
+	invalid := make([]T, 0)
+	for key, value := range fields {
+		rule, ok := rules[key]
+		if !ok {
+			continue
+		}
+		if err := v.Var(value, rule); err != nil {
+			invalid = append(invalid, key)
+		}
+	}
+	slices.Sort(invalid)
+
+	return invalid
+}
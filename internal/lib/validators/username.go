@@ -0,0 +1,55 @@
+package validators
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+	"strings"
+)
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 30
+)
+
+var (
+	ErrUsernameFormat     = errors.New("username must be 3 to 30 latin letters, digits or underscores")
+	ErrUsernameDigitsOnly = errors.New("username must contain a letter")
+	ErrUsernameReserved   = errors.New("username is reserved")
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// reservedUsernames would be confused with the service pages or the staff, they are compared lower cased.
+var reservedUsernames = map[string]struct{}{
+	"admin": {}, "administrator": {}, "root": {}, "system": {}, "support": {}, "help": {},
+	"moderator": {}, "staff": {}, "official": {}, "security": {}, "api": {}, "auth": {},
+	"login": {}, "logout": {}, "register": {}, "signup": {}, "signin": {}, "settings": {},
+	"profile": {}, "account": {}, "users": {}, "user": {}, "me": {}, "www": {}, "mail": {},
+	"blog": {}, "null": {}, "undefined": {}, "anonymous": {}, "deleted": {},
+}
+
+// CheckUsername checks the username format and that it is not reserved. Usernames made of digits only
+// are rejected too, they are easy to mix up with ids.
+func CheckUsername(username string) error {
+	if len(username) < UsernameMinLength || len(username) > UsernameMaxLength || !usernamePattern.MatchString(username) {
+		return ErrUsernameFormat
+	}
+	if strings.Trim(username, "0123456789") == "" {
+		return ErrUsernameDigitsOnly
+	}
+	if _, ok := reservedUsernames[strings.ToLower(username)]; ok {
+		return ErrUsernameReserved
+	}
+
+	return nil
+}
+
+func validateUsername(fl validator.FieldLevel) bool {
+	username, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return CheckUsername(username) == nil
+}
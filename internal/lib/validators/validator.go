@@ -5,9 +5,13 @@ import (
 	"fmt"
 	servicestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
 	"github.com/KBcHMFollower/blog_user_service/internal/lib"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 // todo: нужно будет в файл локализации вынести
@@ -20,6 +24,9 @@ const (
 	uuidTag  validationTag = "uuid"
 	gteTag   validationTag = "gte"
 	lteTag   validationTag = "lte"
+
+	passwordTag validationTag = "password"
+	usernameTag validationTag = "username"
 )
 
 const (
@@ -29,10 +36,26 @@ const (
 	emailErrMessage    = "{0} must be a valid email address"
 	alphaErrMessage    = "{0} must be a valid alpha numeric value"
 	uuidErrMessage     = "{0} must be a valid uuid value"
+	usernameErrMessage = "{0} must be 3 to 30 latin letters, digits or underscores, contain a letter and not be reserved"
 	gteErrMessage      = "{0} must be greater than {1}"
 	lteErrMessage      = "{0} must be less than {1}"
 )
 
+// passwordMessages are keyed by the broken policy rule, {1} is the rule limit.
+var passwordMessages = map[passwordshelper.PolicyRule]string{
+	passwordshelper.MinLengthRule:    "{0} must be at least {1} characters long",
+	passwordshelper.MaxLengthRule:    "{0} must be at most {1} characters long",
+	passwordshelper.UpperRule:        "{0} must contain an upper case letter",
+	passwordshelper.LowerRule:        "{0} must contain a lower case letter",
+	passwordshelper.DigitRule:        "{0} must contain a digit",
+	passwordshelper.SpecialRule:      "{0} must contain a special character",
+	passwordshelper.PersonalInfoRule: "{0} must not contain the email or the name",
+	passwordshelper.BreachedRule:     "{0} is found in a data breach, choose another one",
+}
+
+// personalInfoFields are the sibling fields the password tag compares the password with.
+var personalInfoFields = []string{"Email", "FName", "LName"}
+
 var (
 	messagesWithParams = map[validationTag]string{
 		minTag: minErrMessage,
@@ -41,10 +64,11 @@ var (
 		lteTag: lteErrMessage,
 	}
 	messagesWithoutParams = map[validationTag]string{
-		emailTag: emailErrMessage,
-		alphaTag: alphaErrMessage,
-		uuidTag:  uuidErrMessage,
-		reqTag:   requiredErrMessage,
+		emailTag:    emailErrMessage,
+		alphaTag:    alphaErrMessage,
+		uuidTag:     uuidErrMessage,
+		reqTag:      requiredErrMessage,
+		usernameTag: usernameErrMessage,
 	}
 )
 
@@ -56,9 +80,12 @@ var (
 
 type Validator struct {
 	*validator.Validate
+	trans ut.Translator
 }
 
-func NewValidator() (*Validator, error) {
+// NewValidator registers the password tag checking the policy, see validatePassword, the username tag,
+// see CheckUsername, and the rules of the profile fields, see registerProfileValidations.
+func NewValidator(policy passwordshelper.Policy) (*Validator, error) {
 	valid := validator.New()
 	uni := ut.New(en.New(), en.New())
 	trans, ok := uni.GetTranslator("en")
@@ -68,12 +95,35 @@ func NewValidator() (*Validator, error) {
 
 	if err := valid.RegisterValidation("mapkeys-user-update", validateMapKeys([]servicestransfer.UserFieldTarget{
 		servicestransfer.UserLNameUpdateTarget,
-		servicestransfer.UserEmailUpdateTarget,
 		servicestransfer.UserFNameUpdateTarget,
+		servicestransfer.UserUsernameUpdateTarget,
+		servicestransfer.UserBioUpdateTarget,
+		servicestransfer.UserLinksUpdateTarget,
+		servicestransfer.UserLocationUpdateTarget,
+		servicestransfer.UserBirthdayUpdateTarget,
+		servicestransfer.UserBirthdayVisibilityUpdateTarget,
+		servicestransfer.UserPronounsUpdateTarget,
+		servicestransfer.UserCoverUpdateTarget,
 	})); err != nil {
 		return nil, errors.New(fmt.Sprint("Error registering validation:", err))
 	}
 
+	if err := registerProfileValidations(valid, trans); err != nil {
+		return nil, errors.New(fmt.Sprint("Error registering validation:", err))
+	}
+
+	if err := valid.RegisterValidation(string(usernameTag), validateUsername); err != nil {
+		return nil, errors.New(fmt.Sprint("Error registering validation:", err))
+	}
+
+	if err := valid.RegisterValidation(string(passwordTag), validatePassword(policy)); err != nil {
+		return nil, errors.New(fmt.Sprint("Error registering validation:", err))
+	}
+
+	if err := registerPasswordTranslation(valid, trans, policy); err != nil {
+		return nil, errors.New(fmt.Sprint("Error registering translation:", err))
+	}
+
 	for key, val := range messagesWithParams {
 		if err := registerTranslation(translationInfo{
 			v:          valid,
@@ -98,7 +148,24 @@ func NewValidator() (*Validator, error) {
 		}
 	}
 
-	return &Validator{valid}, nil
+	return &Validator{valid, trans}, nil
+}
+
+// Struct validates the struct and translates every failed rule to a message.
+func (v *Validator) Struct(s any) error {
+	err := v.Validate.Struct(s)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	messages := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		messages = append(messages, fieldErr.Translate(v.trans))
+	}
+
+	return errors.New(strings.Join(messages, "\n"))
 }
 
 type translationInfo struct {
@@ -137,3 +204,59 @@ func validateMapKeys[T ~string](validKeys []T) func(level validator.FieldLevel)
 		return true
 	}
 }
+
+// validatePassword checks the policy, the password is compared with the Email, FName and LName
+// fields of the same struct when it has them.
+func validatePassword(policy passwordshelper.Policy) func(level validator.FieldLevel) bool {
+	return func(fl validator.FieldLevel) bool {
+		password, ok := fl.Field().Interface().(string)
+		if !ok {
+			return false
+		}
+
+		return policy.Validate(password, personalInfo(fl.Parent())...) == nil
+	}
+}
+
+func personalInfo(parent reflect.Value) []string {
+	if parent.Kind() == reflect.Pointer {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return nil
+	}
+
+	personal := make([]string, 0, len(personalInfoFields))
+	for _, name := range personalInfoFields {
+		if field := parent.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+			personal = append(personal, field.String())
+		}
+	}
+
+	return personal
+}
+
+// registerPasswordTranslation picks the message of the broken rule. A field error doesn't keep the rule,
+// so the password is checked again without the personal info: when it passes, the personal info rule was broken.
+func registerPasswordTranslation(v *validator.Validate, trans ut.Translator, policy passwordshelper.Policy) error {
+	return v.RegisterTranslation(string(passwordTag), trans, func(ut ut.Translator) error {
+		for rule, message := range passwordMessages {
+			if err := ut.Add(passwordTranslationKey(rule), message, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		password, _ := fe.Value().(string)
+
+		policyErr := &passwordshelper.PolicyError{Rule: passwordshelper.PersonalInfoRule}
+		errors.As(policy.Validate(password), &policyErr)
+
+		t, _ := ut.T(passwordTranslationKey(policyErr.Rule), fe.Field(), strconv.Itoa(policyErr.Limit))
+		return t
+	})
+}
+
+func passwordTranslationKey(rule passwordshelper.PolicyRule) string {
+	return fmt.Sprintf("%s_%s", passwordTag, rule)
+}
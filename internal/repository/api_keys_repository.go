@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	apiKeysTable = "api_keys"
+)
+
+const (
+	ApiKeyUsedCachePref = "apiKeyUsed-"
+)
+
+const (
+	apiKeysIdCol             = "id"
+	apiKeysAllCol            = "*"
+	apiKeysServiceAccountCol = "service_account"
+	apiKeysPrefixCol         = "prefix"
+	apiKeysSecretHashCol     = "secret_hash"
+	apiKeysScopesCol         = "scopes"
+	apiKeysCreatedByCol      = "created_by"
+	apiKeysLastUsedAtCol     = "last_used_at"
+	apiKeysRevokedAtCol      = "revoked_at"
+	apiKeysCreatedDateCol    = "created_date"
+)
+
+const (
+	apiKeyUsedCacheValue = "1"
+)
+
+// apiKeyTouchInterval limits last_used_at updates to one per interval for a key.
+const apiKeyTouchInterval = time.Minute
+
+type ApiKeysRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+	cache    cache.CacheStorage
+}
+
+func NewApiKeysRepository(db database.DBWrapper, cacheStorage cache.CacheStorage) *ApiKeysRepository {
+	return &ApiKeysRepository{
+		db:       db,
+		cache:    cacheStorage,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *ApiKeysRepository) Create(ctx context.Context, info transfer.CreateApiKeyInfo, tx database.Transaction) (*models.ApiKey, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	apiKey := models.NewApiKeyModel(info.ServiceAccount, info.Prefix, info.SecretHash, info.Scopes, info.CreatedBy)
+
+	query := r.qBuilder.
+		Insert(apiKeysTable).
+		SetMap(map[string]interface{}{
+			apiKeysIdCol:             apiKey.Id,
+			apiKeysServiceAccountCol: apiKey.ServiceAccount,
+			apiKeysPrefixCol:         apiKey.Prefix,
+			apiKeysSecretHashCol:     apiKey.SecretHash,
+			apiKeysScopesCol:         apiKey.Scopes,
+			apiKeysCreatedByCol:      apiKey.CreatedBy,
+		}).
+		Suffix("RETURNING \"created_date\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if err := executor.GetContext(ctx, &apiKey.CreatedDate, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return apiKey, nil
+}
+
+// ApiKeys returns the active keys matching the condition, the newest first.
+func (r *ApiKeysRepository) ApiKeys(ctx context.Context, info transfer.GetApiKeysInfo, tx database.Transaction) ([]*models.ApiKey, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(apiKeysAllCol).
+		From(apiKeysTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{apiKeysRevokedAtCol: nil}).
+		OrderBy(apiKeysCreatedDateCol + " DESC")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	apiKeys := make([]*models.ApiKey, 0)
+	if err := executor.SelectContext(ctx, &apiKeys, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke revokes the active keys matching the condition and returns their number.
+func (r *ApiKeysRepository) Revoke(ctx context.Context, info transfer.RevokeApiKeysInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(apiKeysTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{apiKeysRevokedAtCol: nil}).
+		Set(apiKeysRevokedAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	revoked, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return revoked, nil
+}
+
+// Touch updates last_used_at of the key, it is written at most once per apiKeyTouchInterval.
+func (r *ApiKeysRepository) Touch(ctx context.Context, keyId uuid.UUID) error {
+	usedKey := apiKeyUsedCacheKey(keyId)
+
+	if used, err := r.cache.Exists(ctx, usedKey); err == nil && used {
+		return nil
+	}
+
+	query := r.qBuilder.
+		Update(apiKeysTable).
+		Where(squirrel.Eq{apiKeysIdCol: keyId}).
+		Set(apiKeysLastUsedAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	_ = r.cache.SetWithTTL(ctx, usedKey, apiKeyUsedCacheValue, apiKeyTouchInterval)
+
+	return nil
+}
+
+func apiKeyUsedCacheKey(keyId uuid.UUID) string {
+	return fmt.Sprintf("%s%s", ApiKeyUsedCachePref, keyId.String())
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	auditLogTable = "audit_log"
+)
+
+const (
+	auditLogIdCol           = "id"
+	auditLogActionCol       = "action"
+	auditLogActorIdCol      = "actor_id"
+	auditLogTargetUserIdCol = "target_user_id"
+	auditLogTokenIdCol      = "token_id"
+	auditLogReasonCol       = "reason"
+	auditLogIpAddressCol    = "ip_address"
+)
+
+type AuditLogRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewAuditLogRepository(db database.DBWrapper) *AuditLogRepository {
+	return &AuditLogRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, info transfer.CreateAuditEntryInfo, tx database.Transaction) (*models.AuditEntry, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	entry := models.NewAuditEntryModel(info.Action, info.ActorId, info.TargetUserId, info.TokenId, info.Reason, info.IpAddress)
+
+	query := r.qBuilder.
+		Insert(auditLogTable).
+		SetMap(map[string]interface{}{
+			auditLogIdCol:           entry.Id,
+			auditLogActionCol:       entry.Action,
+			auditLogActorIdCol:      entry.ActorId,
+			auditLogTargetUserIdCol: entry.TargetUserId,
+			auditLogTokenIdCol:      entry.TokenId,
+			auditLogReasonCol:       entry.Reason,
+			auditLogIpAddressCol:    entry.IpAddress,
+		}).
+		Suffix("RETURNING \"created_date\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if err := executor.GetContext(ctx, &entry.CreatedDate, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	externalIdentitiesTable = "external_identities"
+)
+
+const (
+	externalIdentitiesIdCol       = "id"
+	externalIdentitiesAllCol      = "*"
+	externalIdentitiesUserIdCol   = "user_id"
+	externalIdentitiesProviderCol = "provider"
+	externalIdentitiesSubjectCol  = "subject"
+	externalIdentitiesEmailCol    = "email"
+)
+
+type ExternalIdentitiesRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewExternalIdentitiesRepository(db database.DBWrapper) *ExternalIdentitiesRepository {
+	return &ExternalIdentitiesRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// Create links the identity, an identity linked to another user or a second identity
+// of the provider for the user is a conflict.
+func (r *ExternalIdentitiesRepository) Create(ctx context.Context, info transfer.CreateExternalIdentityInfo, tx database.Transaction) (*models.ExternalIdentity, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	identity := models.NewExternalIdentityModel(info.UserId, info.Provider, info.Subject, info.Email)
+
+	query := r.qBuilder.
+		Insert(externalIdentitiesTable).
+		SetMap(map[string]interface{}{
+			externalIdentitiesIdCol:       identity.Id,
+			externalIdentitiesUserIdCol:   identity.UserId,
+			externalIdentitiesProviderCol: identity.Provider,
+			externalIdentitiesSubjectCol:  identity.Subject,
+			externalIdentitiesEmailCol:    identity.Email,
+		}).
+		Suffix("RETURNING \"created_date\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if err := executor.GetContext(ctx, &identity.CreatedDate, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return identity, nil
+}
+
+func (r *ExternalIdentitiesRepository) Identity(ctx context.Context, info transfer.GetExternalIdentityInfo, tx database.Transaction) (*models.ExternalIdentity, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(externalIdentitiesAllCol).
+		From(externalIdentitiesTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition)))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var identity models.ExternalIdentity
+	if err := executor.GetContext(ctx, &identity, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &identity, nil
+}
+
+// Delete removes the identities matching the condition and returns their number.
+func (r *ExternalIdentitiesRepository) Delete(ctx context.Context, info transfer.DeleteExternalIdentitiesInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Delete(externalIdentitiesTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition)))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+)
+
+const (
+	LoginFailuresCachePref = "loginFailures-"
+	LoginLocksCachePref    = "loginLock-"
+)
+
+const (
+	loginLockedCacheValue = "1"
+)
+
+// LoginAttemptsRepository counts failed logins and keeps lockouts in redis. Whenever redis fails the
+// in-memory storage is used instead, so the protection degrades to a per instance one rather than disappearing.
+type LoginAttemptsRepository struct {
+	cache    cache.CacheStorage
+	fallback cache.CacheStorage
+}
+
+func NewLoginAttemptsRepository(cacheStorage cache.CacheStorage, fallback cache.CacheStorage) *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{
+		cache:    cacheStorage,
+		fallback: fallback,
+	}
+}
+
+// RegisterFailure returns the number of failures within the window, the window starts with the first failure.
+func (r *LoginAttemptsRepository) RegisterFailure(ctx context.Context, info transfer.RegisterLoginFailureInfo) (int64, error) {
+	key := loginFailuresCacheKey(info.Key)
+
+	failures, err := r.cache.Incr(ctx, key, info.Window)
+	if err != nil {
+		failures, err = r.fallback.Incr(ctx, key, info.Window)
+		if err != nil {
+			return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to count login failure", err))
+		}
+	}
+
+	return failures, nil
+}
+
+func (r *LoginAttemptsRepository) Reset(ctx context.Context, key transfer.LoginAttemptsKey) error {
+	for _, cacheKey := range []string{loginFailuresCacheKey(key), loginLockCacheKey(key)} {
+		// the key may have been written to any of the storages, both are cleared
+		if err := r.fallback.Delete(ctx, cacheKey); err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to delete from cache", err))
+		}
+		if err := r.cache.Delete(ctx, cacheKey); err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to delete from cache", err))
+		}
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptsRepository) Lock(ctx context.Context, info transfer.LockLoginInfo) error {
+	key := loginLockCacheKey(info.Key)
+
+	if err := r.cache.SetWithTTL(ctx, key, loginLockedCacheValue, info.Duration); err != nil {
+		if err := r.fallback.SetWithTTL(ctx, key, loginLockedCacheValue, info.Duration); err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to write to cache", err))
+		}
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptsRepository) IsLocked(ctx context.Context, key transfer.LoginAttemptsKey) (bool, error) {
+	cacheKey := loginLockCacheKey(key)
+
+	if locked, err := r.cache.Exists(ctx, cacheKey); err == nil && locked {
+		return true, nil
+	}
+
+	locked, err := r.fallback.Exists(ctx, cacheKey)
+	if err != nil {
+		return false, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to read from cache", err))
+	}
+
+	return locked, nil
+}
+
+func loginFailuresCacheKey(key transfer.LoginAttemptsKey) string {
+	return fmt.Sprintf("%s%s-%s", LoginFailuresCachePref, key.Target, key.Value)
+}
+
+func loginLockCacheKey(key transfer.LoginAttemptsKey) string {
+	return fmt.Sprintf("%s%s-%s", LoginLocksCachePref, key.Target, key.Value)
+}
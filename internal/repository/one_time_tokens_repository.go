@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	oneTimeTokensTable = "one_time_tokens"
+)
+
+const (
+	otTokensIdCol        = "id"
+	otTokensAllCol       = "*"
+	otTokensUserIdCol    = "user_id"
+	otTokensPurposeCol   = "purpose"
+	otTokensEmailCol     = "email"
+	otTokensTokenHashCol = "token_hash"
+	otTokensExpiresAtCol = "expires_at"
+	otTokensUsedAtCol    = "used_at"
+)
+
+type OneTimeTokensRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewOneTimeTokensRepository(db database.DBWrapper) *OneTimeTokensRepository {
+	return &OneTimeTokensRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *OneTimeTokensRepository) Create(ctx context.Context, info transfer.CreateOneTimeTokenInfo, tx database.Transaction) (uuid.UUID, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	token := models.NewOneTimeTokenModel(info.UserId, string(info.Purpose), info.Email, info.TokenHash, info.ExpiresAt)
+
+	query := r.qBuilder.
+		Insert(oneTimeTokensTable).
+		SetMap(map[string]interface{}{
+			otTokensIdCol:        token.Id,
+			otTokensUserIdCol:    uuid.NullUUID{UUID: token.UserId, Valid: token.UserId != uuid.Nil},
+			otTokensPurposeCol:   token.Purpose,
+			otTokensEmailCol:     token.Email,
+			otTokensTokenHashCol: token.TokenHash,
+			otTokensExpiresAtCol: token.ExpiresAt,
+		}).
+		Suffix("RETURNING \"id\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return uuid.Nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var id uuid.UUID
+	if err := executor.GetContext(ctx, &id, toSql, args...); err != nil {
+		return uuid.Nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return id, nil
+}
+
+func (r *OneTimeTokensRepository) Token(ctx context.Context, info transfer.GetOneTimeTokenInfo, tx database.Transaction) (*models.OneTimeToken, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(otTokensAllCol).
+		From(oneTimeTokensTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition)))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var token models.OneTimeToken
+	if err := executor.GetContext(ctx, &token, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &token, nil
+}
+
+// Use marks every not yet used token matching the condition as used
+// and returns the number of tokens that were actually used by this call.
+func (r *OneTimeTokensRepository) Use(ctx context.Context, info transfer.UseOneTimeTokensInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(oneTimeTokensTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{otTokensUsedAtCol: nil}).
+		Set(otTokensUsedAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	used, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return used, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	refreshTokensTable = "refresh_tokens"
+)
+
+const (
+	rTokensIdCol         = "id"
+	rTokensAllCol        = "*"
+	rTokensUserIdCol     = "user_id"
+	rTokensFamilyIdCol   = "family_id"
+	rTokensTokenHashCol  = "token_hash"
+	rTokensExpiresAtCol  = "expires_at"
+	rTokensRevokedAtCol  = "revoked_at"
+	rTokensReplacedByCol = "replaced_by"
+)
+
+type RefreshTokensRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewRefreshTokensRepository(db database.DBWrapper) *RefreshTokensRepository {
+	return &RefreshTokensRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *RefreshTokensRepository) Create(ctx context.Context, info transfer.CreateRefreshTokenInfo, tx database.Transaction) (uuid.UUID, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	token := models.NewRefreshTokenModel(info.UserId, info.FamilyId, info.TokenHash, info.ExpiresAt)
+
+	query := r.qBuilder.
+		Insert(refreshTokensTable).
+		SetMap(map[string]interface{}{
+			rTokensIdCol:        token.Id,
+			rTokensUserIdCol:    token.UserId,
+			rTokensFamilyIdCol:  token.FamilyId,
+			rTokensTokenHashCol: token.TokenHash,
+			rTokensExpiresAtCol: token.ExpiresAt,
+		}).
+		Suffix("RETURNING \"id\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return uuid.Nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var id uuid.UUID
+	if err := executor.GetContext(ctx, &id, toSql, args...); err != nil {
+		return uuid.Nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return id, nil
+}
+
+func (r *RefreshTokensRepository) Token(ctx context.Context, info transfer.GetRefreshTokenInfo, tx database.Transaction) (*models.RefreshToken, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(rTokensAllCol).
+		From(refreshTokensTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition)))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var token models.RefreshToken
+	if err := executor.GetContext(ctx, &token, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &token, nil
+}
+
+// Revoke marks every not yet revoked token matching the condition as revoked
+// and returns the number of tokens that were actually revoked by this call.
+func (r *RefreshTokensRepository) Revoke(ctx context.Context, info transfer.RevokeRefreshTokensInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	updateData := map[string]interface{}{
+		rTokensRevokedAtCol: time.Now(),
+	}
+	if info.ReplacedBy.Valid {
+		updateData[rTokensReplacedByCol] = info.ReplacedBy.UUID
+	}
+
+	query := r.qBuilder.
+		Update(refreshTokensTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{rTokensRevokedAtCol: nil}).
+		SetMap(updateData)
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	revoked, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"strings"
+)
+
+const (
+	rolesTable           = "roles"
+	rolePermissionsTable = "role_permissions"
+	userRolesTable       = "user_roles"
+)
+
+const (
+	RolePermissionsCachePref = "rolePermissions-"
+)
+
+const (
+	rolesNameCol = "name"
+
+	rolePermissionsRoleCol       = "role"
+	rolePermissionsPermissionCol = "permission"
+
+	userRolesUserIdCol = "user_id"
+	userRolesRoleCol   = "role"
+)
+
+const (
+	permissionsCacheSeparator = ","
+)
+
+// RolesRepository caches the permissions of every role in redis, role_permissions is edited
+// by migrations only, so the cache is never invalidated and expires with the cache ttl.
+type RolesRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+	cache    cache.CacheStorage
+}
+
+func NewRolesRepository(db database.DBWrapper, cacheStorage cache.CacheStorage) *RolesRepository {
+	return &RolesRepository{
+		db:       db,
+		cache:    cacheStorage,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *RolesRepository) RoleExists(ctx context.Context, role string, tx database.Transaction) (bool, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select("COUNT(*)").
+		From(rolesTable).
+		Where(squirrel.Eq{rolesNameCol: role})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return false, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var count int64
+	if err := executor.GetContext(ctx, &count, toSql, args...); err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *RolesRepository) UserRoles(ctx context.Context, userId uuid.UUID, tx database.Transaction) ([]string, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(userRolesRoleCol).
+		From(userRolesTable).
+		Where(squirrel.Eq{userRolesUserIdCol: userId}).
+		OrderBy(userRolesRoleCol)
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	roles := make([]string, 0)
+	if err := executor.SelectContext(ctx, &roles, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return roles, nil
+}
+
+// Grant returns false when the user already has the role.
+func (r *RolesRepository) Grant(ctx context.Context, info transfer.UserRoleInfo, tx database.Transaction) (bool, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(userRolesTable).
+		SetMap(map[string]interface{}{
+			userRolesUserIdCol: info.UserId,
+			userRolesRoleCol:   info.Role,
+		}).
+		Suffix("ON CONFLICT DO NOTHING")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return false, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	granted, err := res.RowsAffected()
+	if err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return granted > 0, nil
+}
+
+// Revoke returns false when the user has no such role.
+func (r *RolesRepository) Revoke(ctx context.Context, info transfer.UserRoleInfo, tx database.Transaction) (bool, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Delete(userRolesTable).
+		Where(squirrel.Eq{
+			userRolesUserIdCol: info.UserId,
+			userRolesRoleCol:   info.Role,
+		})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return false, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	revoked, err := res.RowsAffected()
+	if err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return revoked > 0, nil
+}
+
+// RolesPermissions returns the permissions granted to any of the roles.
+func (r *RolesRepository) RolesPermissions(ctx context.Context, roles []string) ([]string, error) {
+	permissions := make([]string, 0)
+
+	for _, role := range roles {
+		rolePermissions, err := r.rolePermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, rolePermissions...)
+	}
+
+	return permissions, nil
+}
+
+func (r *RolesRepository) rolePermissions(ctx context.Context, role string) ([]string, error) {
+	if cached, err := r.cache.Get(ctx, rolePermissionsCacheKey(role)); err == nil {
+		if cached == "" {
+			return nil, nil
+		}
+		return strings.Split(cached, permissionsCacheSeparator), nil
+	}
+
+	query := r.qBuilder.
+		Select(rolePermissionsPermissionCol).
+		From(rolePermissionsTable).
+		Where(squirrel.Eq{rolePermissionsRoleCol: role})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	permissions := make([]string, 0)
+	if err := r.db.SelectContext(ctx, &permissions, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	_ = r.cache.Set(ctx, rolePermissionsCacheKey(role), strings.Join(permissions, permissionsCacheSeparator))
+
+	return permissions, nil
+}
+
+func rolePermissionsCacheKey(role string) string {
+	return fmt.Sprintf("%s%s", RolePermissionsCachePref, role)
+}
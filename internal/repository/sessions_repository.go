@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	sessionsTable = "sessions"
+)
+
+const (
+	SessionStateCachePref = "sessionState-"
+	SessionSeenCachePref  = "sessionSeen-"
+)
+
+const (
+	sessionsIdCol         = "id"
+	sessionsAllCol        = "*"
+	sessionsUserIdCol     = "user_id"
+	sessionsUserAgentCol  = "user_agent"
+	sessionsIpAddressCol  = "ip_address"
+	sessionsLastSeenAtCol = "last_seen_at"
+	sessionsRevokedAtCol  = "revoked_at"
+)
+
+const (
+	sessionSeenCacheValue     = "1"
+	sessionInactiveCacheValue = "0"
+)
+
+// sessionTouchInterval limits last_seen_at updates to one per interval for a session.
+const sessionTouchInterval = time.Minute
+
+// SessionsRepository caches the revoked session state in redis the same way TokenRevocationsRepository
+// caches revocations, CheckAuth reads it on every call.
+type SessionsRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+	cache    cache.CacheStorage
+}
+
+func NewSessionsRepository(db database.DBWrapper, cacheStorage cache.CacheStorage) *SessionsRepository {
+	return &SessionsRepository{
+		db:       db,
+		cache:    cacheStorage,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *SessionsRepository) Create(ctx context.Context, info transfer.CreateSessionInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	session := models.NewSessionModel(info.Id, info.UserId, info.UserAgent, info.IpAddress)
+
+	query := r.qBuilder.
+		Insert(sessionsTable).
+		SetMap(map[string]interface{}{
+			sessionsIdCol:         session.Id,
+			sessionsUserIdCol:     session.UserId,
+			sessionsUserAgentCol:  session.UserAgent,
+			sessionsIpAddressCol:  session.IpAddress,
+			sessionsLastSeenAtCol: session.LastSeenAt,
+		})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+// Sessions returns the active sessions matching the condition, the recently used ones first.
+func (r *SessionsRepository) Sessions(ctx context.Context, info transfer.GetSessionsInfo, tx database.Transaction) ([]*models.Session, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(sessionsAllCol).
+		From(sessionsTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{sessionsRevokedAtCol: nil}).
+		OrderBy(sessionsLastSeenAtCol + " DESC")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	sessions := make([]*models.Session, 0)
+	if err := executor.SelectContext(ctx, &sessions, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return sessions, nil
+}
+
+// Revoke revokes the active sessions matching the condition and returns their ids.
+func (r *SessionsRepository) Revoke(ctx context.Context, info transfer.RevokeSessionsInfo, tx database.Transaction) ([]uuid.UUID, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(sessionsTable).
+		Where(squirrel.Eq(reputils.ConvertMapKeysToStrings(info.Condition))).
+		Where(squirrel.Eq{sessionsRevokedAtCol: nil}).
+		Set(sessionsRevokedAtCol, time.Now()).
+		Suffix("RETURNING \"id\"")
+	if info.ExceptId.Valid {
+		query = query.Where(squirrel.NotEq{sessionsIdCol: info.ExceptId.UUID})
+	}
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	revokedIds := make([]uuid.UUID, 0)
+	if err := executor.SelectContext(ctx, &revokedIds, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return revokedIds, nil
+}
+
+// IsSessionActive reports false for revoked and for unknown sessions. Only the inactive state is cached,
+// it is final, while a cached active state could outlive a revocation committed meanwhile.
+func (r *SessionsRepository) IsSessionActive(ctx context.Context, sessionId uuid.UUID) (bool, error) {
+	if cached, err := r.cache.Get(ctx, sessionStateCacheKey(sessionId)); err == nil && cached == sessionInactiveCacheValue {
+		return false, nil
+	}
+
+	query := r.qBuilder.
+		Select("COUNT(*)").
+		From(sessionsTable).
+		Where(squirrel.Eq{sessionsIdCol: sessionId}).
+		Where(squirrel.Eq{sessionsRevokedAtCol: nil})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return false, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, toSql, args...); err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	if count == 0 {
+		// the db answer is already known, a cache failure only costs another db lookup next time
+		_ = r.cache.Set(ctx, sessionStateCacheKey(sessionId), sessionInactiveCacheValue)
+	}
+
+	return count > 0, nil
+}
+
+// Touch updates last_seen_at of the session, it is written at most once per sessionTouchInterval.
+func (r *SessionsRepository) Touch(ctx context.Context, sessionId uuid.UUID) error {
+	seenKey := sessionSeenCacheKey(sessionId)
+
+	if seen, err := r.cache.Exists(ctx, seenKey); err == nil && seen {
+		return nil
+	}
+
+	query := r.qBuilder.
+		Update(sessionsTable).
+		Where(squirrel.Eq{sessionsIdCol: sessionId}).
+		Set(sessionsLastSeenAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	_ = r.cache.SetWithTTL(ctx, seenKey, sessionSeenCacheValue, sessionTouchInterval)
+
+	return nil
+}
+
+func sessionStateCacheKey(sessionId uuid.UUID) string {
+	return fmt.Sprintf("%s%s", SessionStateCachePref, sessionId.String())
+}
+
+func sessionSeenCacheKey(sessionId uuid.UUID) string {
+	return fmt.Sprintf("%s%s", SessionSeenCachePref, sessionId.String())
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+const (
+	revokedTokensTable         = "revoked_tokens"
+	userTokensRevocationsTable = "user_tokens_revocations"
+)
+
+const (
+	RevokedTokensCachePref = "revokedJti-"
+	RevokedUsersCachePref  = "revokedUser-"
+)
+
+const (
+	revokedTokensJtiCol       = "jti"
+	revokedTokensUserIdCol    = "user_id"
+	revokedTokensExpiresAtCol = "expires_at"
+
+	userRevocationsUserIdCol        = "user_id"
+	userRevocationsRevokedBeforeCol = "revoked_before"
+)
+
+const tokenRevokedCacheValue = "1"
+
+// TokenRevocationsRepository keeps revocations in postgres and caches the revocations found in redis,
+// so CheckAuth of a revoked token usually does not touch the db. Misses are not cached, a cached miss could
+// hide a revocation committed meanwhile. Postgres is the source of truth and is used whenever the cache
+// has no entry or is unavailable.
+type TokenRevocationsRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+	cache    cache.CacheStorage
+}
+
+func NewTokenRevocationsRepository(db database.DBWrapper, cacheStorage cache.CacheStorage) *TokenRevocationsRepository {
+	return &TokenRevocationsRepository{
+		db:       db,
+		cache:    cacheStorage,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *TokenRevocationsRepository) RevokeToken(ctx context.Context, info transfer.RevokeTokenInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(revokedTokensTable).
+		SetMap(map[string]interface{}{
+			revokedTokensJtiCol:       info.Jti,
+			revokedTokensUserIdCol:    info.UserId,
+			revokedTokensExpiresAtCol: info.ExpiresAt,
+		}).
+		Suffix("ON CONFLICT (\"jti\") DO NOTHING")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *TokenRevocationsRepository) RevokeUserTokens(ctx context.Context, info transfer.RevokeUserTokensInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(userTokensRevocationsTable).
+		SetMap(map[string]interface{}{
+			userRevocationsUserIdCol:        info.UserId,
+			userRevocationsRevokedBeforeCol: info.RevokedBefore,
+		}).
+		Suffix("ON CONFLICT (\"user_id\") DO UPDATE SET \"revoked_before\" = EXCLUDED.\"revoked_before\"")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+// SetRevokedTokenToCache caches the revocation, it is called once the revocation is committed.
+func (r *TokenRevocationsRepository) SetRevokedTokenToCache(ctx context.Context, jti uuid.UUID) error {
+	if err := r.cache.Set(ctx, revokedTokenCacheKey(jti), tokenRevokedCacheValue); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to write to cache", err))
+	}
+
+	return nil
+}
+
+// SetRevokedUserTokensToCache caches the revocation, it is called once the revocation is committed.
+func (r *TokenRevocationsRepository) SetRevokedUserTokensToCache(ctx context.Context, info transfer.RevokeUserTokensInfo) error {
+	if err := r.cache.Set(ctx, revokedUserCacheKey(info.UserId), strconv.FormatInt(info.RevokedBefore.Unix(), 10)); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to write to cache", err))
+	}
+
+	return nil
+}
+
+func (r *TokenRevocationsRepository) IsTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, error) {
+	if cached, err := r.cache.Get(ctx, revokedTokenCacheKey(jti)); err == nil {
+		return cached == tokenRevokedCacheValue, nil
+	}
+
+	query := r.qBuilder.
+		Select("COUNT(*)").
+		From(revokedTokensTable).
+		Where(squirrel.Eq{revokedTokensJtiCol: jti})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return false, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, toSql, args...); err != nil {
+		return false, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	if count > 0 {
+		// the db answer is already known, a cache failure only costs another db lookup next time
+		_, _ = r.cache.SetNX(ctx, revokedTokenCacheKey(jti), tokenRevokedCacheValue)
+	}
+
+	return count > 0, nil
+}
+
+// UserTokensRevokedBefore returns the moment before which every token of the user is revoked,
+// or zero time if the user tokens were never revoked at once.
+func (r *TokenRevocationsRepository) UserTokensRevokedBefore(ctx context.Context, userId uuid.UUID) (time.Time, error) {
+	if cached, err := r.cache.Get(ctx, revokedUserCacheKey(userId)); err == nil {
+		return parseRevokedBefore(cached), nil
+	}
+
+	query := r.qBuilder.
+		Select(userRevocationsRevokedBeforeCol).
+		From(userTokensRevocationsTable).
+		Where(squirrel.Eq{userRevocationsUserIdCol: userId})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return time.Time{}, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var revokedBefore time.Time
+	if err := r.db.GetContext(ctx, &revokedBefore, toSql, args...); err != nil {
+		execErr := reputils.ReturnExecuteSqlError(ctx, err)
+		if !errors.Is(execErr, ctxerrors.ErrNotFound) {
+			return time.Time{}, execErr
+		}
+	}
+
+	if !revokedBefore.IsZero() {
+		// SetNX keeps the value of a newer revocation cached after this read
+		_, _ = r.cache.SetNX(ctx, revokedUserCacheKey(userId), strconv.FormatInt(revokedBefore.Unix(), 10))
+	}
+
+	return revokedBefore, nil
+}
+
+func parseRevokedBefore(cached string) time.Time {
+	unix, err := strconv.ParseInt(cached, 10, 64)
+	if err != nil || unix == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(unix, 0)
+}
+
+func revokedTokenCacheKey(jti uuid.UUID) string {
+	return fmt.Sprintf("%s%s", RevokedTokensCachePref, jti.String())
+}
+
+func revokedUserCacheKey(userId uuid.UUID) string {
+	return fmt.Sprintf("%s%s", RevokedUsersCachePref, userId.String())
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	usersTotpTable         = "users_totp"
+	totpRecoveryCodesTable = "totp_recovery_codes"
+)
+
+const (
+	totpAllCol          = "*"
+	totpUserIdCol       = "user_id"
+	totpSecretCol       = "secret"
+	totpConfirmedAtCol  = "confirmed_at"
+	totpLastUsedStepCol = "last_used_step"
+
+	recoveryCodesIdCol       = "id"
+	recoveryCodesUserIdCol   = "user_id"
+	recoveryCodesCodeHashCol = "code_hash"
+	recoveryCodesUsedAtCol   = "used_at"
+)
+
+// TotpRepository keeps the encrypted totp secrets and hashed recovery codes of the users.
+type TotpRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewTotpRepository(db database.DBWrapper) *TotpRepository {
+	return &TotpRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *TotpRepository) Totp(ctx context.Context, userId uuid.UUID, tx database.Transaction) (*models.UserTotp, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(totpAllCol).
+		From(usersTotpTable).
+		Where(squirrel.Eq{totpUserIdCol: userId})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var totp models.UserTotp
+	if err := executor.GetContext(ctx, &totp, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &totp, nil
+}
+
+// SaveSecret stores a new not confirmed secret. A confirmed secret is never replaced,
+// in that case 0 is returned.
+func (r *TotpRepository) SaveSecret(ctx context.Context, info transfer.SaveTotpSecretInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(usersTotpTable).
+		SetMap(map[string]interface{}{
+			totpUserIdCol: info.UserId,
+			totpSecretCol: info.EncryptedSecret,
+		}).
+		Suffix("ON CONFLICT (\"user_id\") DO UPDATE SET \"secret\" = EXCLUDED.\"secret\", \"last_used_step\" = 0, \"created_date\" = CURRENT_TIMESTAMP " +
+			"WHERE users_totp.\"confirmed_at\" IS NULL")
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	saved, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return saved, nil
+}
+
+func (r *TotpRepository) Confirm(ctx context.Context, userId uuid.UUID, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(usersTotpTable).
+		Where(squirrel.Eq{totpUserIdCol: userId}).
+		Set(totpConfirmedAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+// UseStep remembers the step of the accepted code and returns 0 if the step (or a later one) was already used,
+// so every code is accepted only once.
+func (r *TotpRepository) UseStep(ctx context.Context, info transfer.UseTotpStepInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(usersTotpTable).
+		Where(squirrel.Eq{totpUserIdCol: info.UserId}).
+		Where(squirrel.Lt{totpLastUsedStepCol: info.Step}).
+		Set(totpLastUsedStepCol, info.Step)
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	used, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return used, nil
+}
+
+// Delete removes the secret together with the recovery codes.
+func (r *TotpRepository) Delete(ctx context.Context, userId uuid.UUID, tx database.Transaction) error {
+	if err := r.deleteRecoveryCodes(ctx, userId, tx); err != nil {
+		return err
+	}
+
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Delete(usersTotpTable).
+		Where(squirrel.Eq{totpUserIdCol: userId})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *TotpRepository) ReplaceRecoveryCodes(ctx context.Context, info transfer.ReplaceRecoveryCodesInfo, tx database.Transaction) error {
+	if err := r.deleteRecoveryCodes(ctx, info.UserId, tx); err != nil {
+		return err
+	}
+
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(totpRecoveryCodesTable).
+		Columns(recoveryCodesIdCol, recoveryCodesUserIdCol, recoveryCodesCodeHashCol)
+	for _, codeHash := range info.CodeHashes {
+		query = query.Values(uuid.New(), info.UserId, codeHash)
+	}
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks the code as used and returns 0 if there is no such unused code.
+func (r *TotpRepository) UseRecoveryCode(ctx context.Context, info transfer.UseRecoveryCodeInfo, tx database.Transaction) (int64, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(totpRecoveryCodesTable).
+		Where(squirrel.Eq{
+			recoveryCodesUserIdCol:   info.UserId,
+			recoveryCodesCodeHashCol: info.CodeHash,
+			recoveryCodesUsedAtCol:   nil,
+		}).
+		Set(recoveryCodesUsedAtCol, time.Now())
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return 0, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, toSql, args...)
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	used, err := res.RowsAffected()
+	if err != nil {
+		return 0, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return used, nil
+}
+
+func (r *TotpRepository) deleteRecoveryCodes(ctx context.Context, userId uuid.UUID, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Delete(totpRecoveryCodesTable).
+		Where(squirrel.Eq{recoveryCodesUserIdCol: userId})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
 	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
 	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"strings"
 	"time"
 
 	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
@@ -22,17 +23,29 @@ const (
 )
 
 const (
-	UsersCachePref = "userId-"
+	UsersCachePref     = "userId-"
+	UsernamesCachePref = "username-"
 )
 
 const (
 	usersIdCol          = "id"
 	userEmailCol        = "email"
+	usersUsernameCol    = "username"
 	usersPassHashCol    = "pass_hash"
+	usersTokenVerCol    = "token_version"
+	usersEmailVerAtCol  = "email_verified_at"
+	usersVersionCol     = "version"
 	usersAvatarCol      = "avatar"
 	usersAvatarMiniCol  = "avatar_min"
 	usersFNameCol       = "fname"
 	usersLNameCol       = "lname"
+	usersBioCol         = "bio"
+	usersLinksCol       = "links"
+	usersLocationCol    = "location"
+	usersBirthdayCol    = "birthday"
+	usersBirthdayVisCol = "birthday_visibility"
+	usersPronounsCol    = "pronouns"
+	usersCoverCol       = "cover"
 	usersCreatedDateCol = "created_date"
 	usersUpdatedDateCol = "updated_date"
 )
@@ -105,6 +118,46 @@ func (r *UserRepository) User(ctx context.Context, condition transfer.GetUserInf
 	return &user, nil
 }
 
+// UserByUsername finds the user by the current username, usernames are compared case-insensitively.
+func (r *UserRepository) UserByUsername(ctx context.Context, username string, tx database.Transaction) (*models.User, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	sql, args, err := r.qBuilder.Select("*").
+		From(usersTable).
+		Where(squirrel.Expr(fmt.Sprintf("LOWER(%s) = LOWER(?)", usersUsernameCol), username)).
+		ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var user models.User
+	if err := executor.GetContext(ctx, &user, sql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &user, nil
+}
+
+// ExistingUsernames returns the lower cased usernames of the list that are taken by users.
+func (r *UserRepository) ExistingUsernames(ctx context.Context, usernames []string, tx database.Transaction) ([]string, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	sql, args, err := r.qBuilder.Select(fmt.Sprintf("LOWER(%s)", usersUsernameCol)).
+		From(usersTable).
+		Where(squirrel.Eq{fmt.Sprintf("LOWER(%s)", usersUsernameCol): lowerAll(usernames)}).
+		ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	existing := make([]string, 0)
+	if err := executor.SelectContext(ctx, &existing, sql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return existing, nil
+}
+
 func (r *UserRepository) Count(ctx context.Context, condition transfer.GetUsersCountInfo, tx database.Transaction) (int64, error) {
 	executor := reputils.GetExecutor(r.db, tx)
 
@@ -158,26 +211,129 @@ func (r *UserRepository) Users(ctx context.Context, info *transfer.GetUsersInfo,
 	return users, nil
 }
 
+// Update bumps the user version. With the ExpectedVersion a changed or missing user fails with ErrAborted.
 func (r *UserRepository) Update(ctx context.Context, updateData transfer.UpdateUserInfo, tx database.Transaction) error {
 	executor := reputils.GetExecutor(r.db, tx)
 
 	updateData.UpdateInfo["updated_date"] = time.Now()
+	updateData.UpdateInfo[usersVersionCol] = squirrel.Expr(fmt.Sprintf("%s + 1", usersVersionCol))
 
 	query := r.qBuilder.
 		Update(usersTable).
 		Where(squirrel.Eq{usersIdCol: updateData.Id}).
 		SetMap(updateData.UpdateInfo)
+	if updateData.ExpectedVersion != 0 {
+		query = query.Where(squirrel.Eq{usersVersionCol: updateData.ExpectedVersion})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	if updateData.ExpectedVersion == 0 {
+		return nil
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+	if updated == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("user version is not the expected one", ctxerrors.ErrAborted))
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, info transfer.UpdatePasswordInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(usersTable).
+		Where(squirrel.Eq{usersIdCol: info.Id}).
+		SetMap(map[string]interface{}{
+			usersPassHashCol:    info.PassHash,
+			usersTokenVerCol:    squirrel.Expr(fmt.Sprintf("%s + 1", usersTokenVerCol)),
+			usersUpdatedDateCol: time.Now(),
+		})
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return reputils.ReturnGenerateSqlError(ctx, err)
 	}
 
-	_, err = executor.ExecContext(ctx, sql, args...)
+	res, err := executor.ExecContext(ctx, sql, args...)
 	if err != nil {
 		return reputils.ReturnExecuteSqlError(ctx, err)
 	}
 
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+	if updated == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("user not found", ctxerrors.ErrNotFound))
+	}
+
+	return nil
+}
+
+// ReplacePasswordHash stores another hash of the same password, unlike UpdatePassword it keeps the issued tokens valid.
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, info transfer.UpdatePasswordInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(usersTable).
+		Where(squirrel.Eq{usersIdCol: info.Id}).
+		Set(usersPassHashCol, info.PassHash)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, sql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) BumpTokenVersion(ctx context.Context, userId uuid.UUID, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Update(usersTable).
+		Where(squirrel.Eq{usersIdCol: userId}).
+		SetMap(map[string]interface{}{
+			usersTokenVerCol:    squirrel.Expr(fmt.Sprintf("%s + 1", usersTokenVerCol)),
+			usersUpdatedDateCol: time.Now(),
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	res, err := executor.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+	if updated == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("user not found", ctxerrors.ErrNotFound))
+	}
+
 	return nil
 }
 
@@ -205,10 +361,21 @@ func (r *UserRepository) RollBackUser(ctx context.Context, user models.User) err
 			usersIdCol:          user.Id,
 			userEmailCol:        user.Email,
 			usersPassHashCol:    user.PassHash,
+			usersTokenVerCol:    user.TokenVersion,
+			usersEmailVerAtCol:  user.EmailVerifiedAt,
 			usersAvatarCol:      user.Avatar,
 			usersAvatarMiniCol:  user.AvatarMin,
 			usersFNameCol:       user.FName,
 			usersLNameCol:       user.LName,
+			usersUsernameCol:    user.Username,
+			usersBioCol:         user.Bio,
+			usersLinksCol:       user.Links,
+			usersLocationCol:    user.Location,
+			usersBirthdayCol:    user.Birthday,
+			usersBirthdayVisCol: user.BirthdayVisibility,
+			usersPronounsCol:    user.Pronouns,
+			usersCoverCol:       user.Cover,
+			usersVersionCol:     user.Version,
 			usersCreatedDateCol: user.CreatedDate,
 			usersUpdatedDateCol: time.Now(),
 		}).
@@ -256,6 +423,79 @@ func (r *UserRepository) SetToCache(ctx context.Context, user *models.User) erro
 	return nil
 }
 
+// TryGetManyFromCache reads the users with one multi-get, the users missing in the cache are left out.
+func (r *UserRepository) TryGetManyFromCache(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("%s%s", UsersCachePref, id.String()))
+	}
+
+	data, err := r.cache.MGet(ctx, keys...)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to read from cache", err))
+	}
+
+	users := make(map[uuid.UUID]*models.User, len(data))
+	for _, userJson := range data {
+		var user models.User
+		if err := json.Unmarshal([]byte(userJson), &user); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to unmarshal data", err))
+		}
+		users[user.Id] = &user
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) SetManyToCache(ctx context.Context, users []*models.User) error {
+	values := make(map[string]interface{}, len(users))
+	for _, user := range users {
+		userJson, err := json.Marshal(user)
+		if err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to marshal data", err))
+		}
+		values[fmt.Sprintf("%s%s", UsersCachePref, user.Id.String())] = string(userJson)
+	}
+
+	if err := r.cache.SetMany(ctx, values); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to write to cache", err))
+	}
+
+	return nil
+}
+
+// TryGetIdByUsernameFromCache returns the id of the user the username belonged to when it was cached,
+// the caller checks it against the cached user.
+func (r *UserRepository) TryGetIdByUsernameFromCache(ctx context.Context, username string) (uuid.UUID, error) {
+	data, err := r.cache.Get(ctx, usernameCacheKey(username))
+	if err != nil {
+		return uuid.Nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to read from cache", err))
+	}
+
+	id, err := uuid.Parse(data)
+	if err != nil {
+		return uuid.Nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to parse cached id", err))
+	}
+
+	return id, nil
+}
+
+func (r *UserRepository) SetUsernameToCache(ctx context.Context, username string, id uuid.UUID) error {
+	if err := r.cache.Set(ctx, usernameCacheKey(username), id.String()); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to write to cache", err))
+	}
+
+	return nil
+}
+
+func (r *UserRepository) DeleteUsernameFromCache(ctx context.Context, username string) error {
+	if err := r.cache.Delete(ctx, usernameCacheKey(username)); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("failed to delete from cache", err))
+	}
+
+	return nil
+}
+
 func (r *UserRepository) DeleteFromCache(ctx context.Context, id uuid.UUID) error {
 	err := r.cache.Delete(ctx, fmt.Sprintf("%s%s", UsersCachePref, id.String()))
 	if err != nil {
@@ -264,3 +504,16 @@ func (r *UserRepository) DeleteFromCache(ctx context.Context, id uuid.UUID) erro
 
 	return nil
 }
+
+func usernameCacheKey(username string) string {
+	return fmt.Sprintf("%s%s", UsernamesCachePref, strings.ToLower(username))
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		lowered = append(lowered, strings.ToLower(value))
+	}
+
+	return lowered
+}
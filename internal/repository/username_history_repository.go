@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	reputils "github.com/KBcHMFollower/blog_user_service/internal/repository/lib"
+	"github.com/Masterminds/squirrel"
+	"time"
+)
+
+const (
+	usernameHistoryTable = "username_history"
+)
+
+const (
+	usernameHistoryAllCol       = "*"
+	usernameHistoryUsernameCol  = "username"
+	usernameHistoryUserIdCol    = "user_id"
+	usernameHistoryExpiresAtCol = "expires_at"
+)
+
+type UsernameHistoryRepository struct {
+	db       database.DBWrapper
+	qBuilder squirrel.StatementBuilderType
+}
+
+func NewUsernameHistoryRepository(db database.DBWrapper) *UsernameHistoryRepository {
+	return &UsernameHistoryRepository{
+		db:       db,
+		qBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *UsernameHistoryRepository) Create(ctx context.Context, info transfer.CreateUsernameHistoryInfo, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Insert(usernameHistoryTable).
+		SetMap(map[string]interface{}{
+			usernameHistoryUsernameCol:  info.Username,
+			usernameHistoryUserIdCol:    info.UserId,
+			usernameHistoryExpiresAtCol: info.ExpiresAt,
+		})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
+
+// Entry returns the history entry of the username, expired entries are returned too.
+func (r *UsernameHistoryRepository) Entry(ctx context.Context, username string, tx database.Transaction) (*models.UsernameHistoryEntry, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(usernameHistoryAllCol).
+		From(usernameHistoryTable).
+		Where(squirrel.Expr(fmt.Sprintf("LOWER(%s) = LOWER(?)", usernameHistoryUsernameCol), username))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	var entry models.UsernameHistoryEntry
+	if err := executor.GetContext(ctx, &entry, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return &entry, nil
+}
+
+// HeldUsernames returns the lower cased usernames of the list that still redirect to their former owners.
+func (r *UsernameHistoryRepository) HeldUsernames(ctx context.Context, usernames []string, tx database.Transaction) ([]string, error) {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Select(fmt.Sprintf("LOWER(%s)", usernameHistoryUsernameCol)).
+		From(usernameHistoryTable).
+		Where(squirrel.Eq{fmt.Sprintf("LOWER(%s)", usernameHistoryUsernameCol): lowerAll(usernames)}).
+		Where(squirrel.Gt{usernameHistoryExpiresAtCol: time.Now()})
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return nil, reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	held := make([]string, 0)
+	if err := executor.SelectContext(ctx, &held, toSql, args...); err != nil {
+		return nil, reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return held, nil
+}
+
+func (r *UsernameHistoryRepository) Delete(ctx context.Context, username string, tx database.Transaction) error {
+	executor := reputils.GetExecutor(r.db, tx)
+
+	query := r.qBuilder.
+		Delete(usernameHistoryTable).
+		Where(squirrel.Expr(fmt.Sprintf("LOWER(%s) = LOWER(?)", usernameHistoryUsernameCol), username))
+
+	toSql, args, err := query.ToSql()
+	if err != nil {
+		return reputils.ReturnGenerateSqlError(ctx, err)
+	}
+
+	if _, err := executor.ExecContext(ctx, toSql, args...); err != nil {
+		return reputils.ReturnExecuteSqlError(ctx, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+)
+
+const (
+	serviceAccountLogKey = "service-account"
+	apiKeyIdLogKey       = "api-key-id"
+)
+
+type apiKeysStore interface {
+	dep.ApiKeyCreator
+	dep.ApiKeysGetter
+	dep.ApiKeyRevoker
+	dep.ApiKeyToucher
+}
+
+type ApiKeyService struct {
+	apiKeysRep apiKeysStore
+	log        logger.Logger
+}
+
+func NewApiKeyService(
+	apiKeysRep apiKeysStore,
+	log logger.Logger,
+) *ApiKeyService {
+	return &ApiKeyService{
+		apiKeysRep: apiKeysRep,
+		log:        log,
+	}
+}
+
+func (aks *ApiKeyService) CreateApiKey(ctx context.Context, createInfo *transfer.CreateApiKeyInfo) (*transfer.CreateApiKeyResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, serviceAccountLogKey, createInfo.ServiceAccount)
+
+	aks.log.InfoContext(ctx, "trying to create api key")
+
+	prefix, secret, err := tokenshelper.NewApiKey()
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate api key", err))
+	}
+
+	scopes := createInfo.Scopes
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+
+	apiKey, err := aks.apiKeysRep.Create(ctx, repositoriestransfer.CreateApiKeyInfo{
+		ServiceAccount: createInfo.ServiceAccount,
+		Prefix:         prefix,
+		SecretHash:     tokenshelper.HashOpaqueToken(secret),
+		Scopes:         scopes,
+		CreatedBy:      createInfo.CreatedBy,
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create api key in db", err))
+	}
+
+	aks.log.InfoContext(ctx, "api key created successfully", apiKeyIdLogKey, apiKey.Id)
+
+	return &transfer.CreateApiKeyResult{
+		Id:     apiKey.Id,
+		Prefix: prefix,
+		Key:    tokenshelper.FormatApiKey(prefix, secret),
+	}, nil
+}
+
+func (aks *ApiKeyService) ListApiKeys(ctx context.Context, listInfo *transfer.ListApiKeysInfo) (*transfer.ListApiKeysResult, error) {
+	aks.log.InfoContext(ctx, "trying to list api keys")
+
+	condition := make(map[repositoriestransfer.ApiKeyFieldTarget]any)
+	if listInfo.ServiceAccount != "" {
+		condition[repositoriestransfer.ApiKeyServiceAccountCondition] = listInfo.ServiceAccount
+	}
+
+	apiKeys, err := aks.apiKeysRep.ApiKeys(ctx, repositoriestransfer.GetApiKeysInfo{
+		Condition: condition,
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get api keys from db", err))
+	}
+
+	return transfer.GetListApiKeysResultFromModels(apiKeys), nil
+}
+
+func (aks *ApiKeyService) RevokeApiKey(ctx context.Context, revokeInfo *transfer.RevokeApiKeyInfo) error {
+	ctx = logger.UpdateLoggerCtx(ctx, apiKeyIdLogKey, revokeInfo.Id)
+
+	aks.log.InfoContext(ctx, "trying to revoke api key")
+
+	revoked, err := aks.apiKeysRep.Revoke(ctx, repositoriestransfer.RevokeApiKeysInfo{
+		Condition: map[repositoriestransfer.ApiKeyFieldTarget]any{
+			repositoriestransfer.ApiKeyIdCondition: revokeInfo.Id,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke api key in db", err))
+	}
+	if revoked == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("active api key not found", ctxerrors.ErrNotFound))
+	}
+
+	aks.log.InfoContext(ctx, "api key revoked successfully")
+
+	return nil
+}
+
+// AuthenticateApiKey is used by the auth interceptor, unknown, revoked and malformed keys are ErrUnauthorized.
+func (aks *ApiKeyService) AuthenticateApiKey(ctx context.Context, key string) (*transfer.ApiKeyResult, error) {
+	prefix, secret, ok := tokenshelper.ParseApiKey(key)
+	if !ok {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("malformed api key", ctxerrors.ErrUnauthorized))
+	}
+
+	apiKeys, err := aks.apiKeysRep.ApiKeys(ctx, repositoriestransfer.GetApiKeysInfo{
+		Condition: map[repositoriestransfer.ApiKeyFieldTarget]any{
+			repositoriestransfer.ApiKeyPrefixCondition: prefix,
+		},
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get api key from db", err))
+	}
+	if len(apiKeys) == 0 {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("api key not found", ctxerrors.ErrUnauthorized))
+	}
+
+	apiKey := apiKeys[0]
+	if subtle.ConstantTimeCompare(apiKey.SecretHash, tokenshelper.HashOpaqueToken(secret)) != 1 {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("api key secret mismatch", ctxerrors.ErrUnauthorized))
+	}
+
+	if err := aks.apiKeysRep.Touch(ctx, apiKey.Id); err != nil {
+		aks.log.WarnContext(ctxerrors.ErrorCtx(ctx, err), "can`t update api key last usage", logger.ErrKey, err.Error())
+	}
+
+	return transfer.GetApiKeyResultFromModel(apiKey), nil
+}
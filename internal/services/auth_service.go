@@ -3,37 +3,161 @@ package services
 import (
 	"context"
 	"errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient/messages"
+	oidcclient "github.com/KBcHMFollower/blog_user_service/internal/clients/oidc"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
 	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
 	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
 	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
 	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
 	"github.com/KBcHMFollower/blog_user_service/internal/logger"
 	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
 	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 	"time"
 )
 
+// dummyPassword is hashed once on start, Login verifies its hash for unknown emails,
+// so the response time doesn't tell whether the email is registered.
+const dummyPassword = "dummy-password-for-unknown-emails"
+
+// RegisterOptions with Silent set make Register answer the same way for new and registered emails,
+// see config.Register.
+type RegisterOptions struct {
+	Silent bool
+}
+
+// MagicLinkOptions configure the passwordless login, with SignUp the links are sent to not registered emails too
+// and create the account on redemption.
+type MagicLinkOptions struct {
+	TokenTTL time.Duration
+	LinkBase string
+	SignUp   bool
+}
+
+// ImpersonationOptions limit the lifetime of impersonation tokens, they are not reissued by CheckAuth.
+type ImpersonationOptions struct {
+	TokenTTL time.Duration
+}
+
 type authSvcUserStore interface {
 	dep.UserCreator
 	dep.UserGetter
+	dep.UserUpdater
+	dep.UserDeleter
+	dep.UserPasswordRehasher
+	dep.UserTokenVersionBumper
+}
+
+type authSvcRefreshTokensStore interface {
+	dep.RefreshTokenCreator
+	dep.RefreshTokenGetter
+	dep.RefreshTokenRevoker
+}
+
+type authSvcIdentitiesStore interface {
+	dep.ExternalIdentityCreator
+	dep.ExternalIdentityGetter
+}
+
+type authSvcRevocationsStore interface {
+	dep.TokenRevoker
+	dep.TokenRevocationChecker
 }
 
 type AuthService struct {
-	userRep     authSvcUserStore
-	log         logger.Logger
-	tokenTtl    time.Duration
-	tokenSecret string
-	txCreator   dep.TransactionCreator
+	userRep         authSvcUserStore
+	refreshRep      authSvcRefreshTokensStore
+	sessionsRep     sessionsStore
+	rolesRep        dep.UserRolesGetter
+	revocationsRep  authSvcRevocationsStore
+	tokensRep       oneTimeTokensStore
+	eventsRep       dep.EventCreator
+	totpRep         totpStore
+	attemptsRep     loginAttemptsStore
+	identitiesRep   authSvcIdentitiesStore
+	auditRep        dep.AuditEntryCreator
+	log             logger.Logger
+	hasher          passwordshelper.Hasher
+	policy          passwordshelper.Policy
+	verifier        dep.ExternalTokenVerifier
+	refreshTokenTtl time.Duration
+	jwtOpts         tokenshelper.JwtOptions
+	reissueWindow   time.Duration
+	verifyOpts      EmailVerificationOptions
+	totpOpts        TotpOptions
+	lockoutOpts     LockoutOptions
+	registerOpts    RegisterOptions
+	magicLinkOpts   MagicLinkOptions
+	impersonateOpts ImpersonationOptions
+	dummyHash       []byte
+	txCreator       dep.TransactionCreator
 }
 
-func NewAuthService(userRep authSvcUserStore, log logger.Logger, tokenTtl time.Duration, tokenSecret string, txCreator dep.TransactionCreator) *AuthService {
+func NewAuthService(
+	userRep authSvcUserStore,
+	refreshRep authSvcRefreshTokensStore,
+	sessionsRep sessionsStore,
+	rolesRep dep.UserRolesGetter,
+	revocationsRep authSvcRevocationsStore,
+	tokensRep oneTimeTokensStore,
+	eventsRep dep.EventCreator,
+	totpRep totpStore,
+	attemptsRep loginAttemptsStore,
+	identitiesRep authSvcIdentitiesStore,
+	auditRep dep.AuditEntryCreator,
+	log logger.Logger,
+	hasher passwordshelper.Hasher,
+	policy passwordshelper.Policy,
+	verifier dep.ExternalTokenVerifier,
+	jwtOpts tokenshelper.JwtOptions,
+	reissueWindow time.Duration,
+	refreshTokenTtl time.Duration,
+	verifyOpts EmailVerificationOptions,
+	totpOpts TotpOptions,
+	lockoutOpts LockoutOptions,
+	registerOpts RegisterOptions,
+	magicLinkOpts MagicLinkOptions,
+	impersonateOpts ImpersonationOptions,
+	txCreator dep.TransactionCreator,
+) *AuthService {
+	if totpOpts.Now == nil {
+		totpOpts.Now = time.Now
+	}
+
+	// a failed hashing leaves the hash empty, unknown emails are still rejected, only faster
+	dummyHash, _ := hasher.Hash(dummyPassword)
+
 	return &AuthService{
-		userRep:     userRep,
-		log:         log,
-		tokenTtl:    tokenTtl,
-		tokenSecret: tokenSecret,
-		txCreator:   txCreator,
+		userRep:         userRep,
+		refreshRep:      refreshRep,
+		sessionsRep:     sessionsRep,
+		rolesRep:        rolesRep,
+		revocationsRep:  revocationsRep,
+		tokensRep:       tokensRep,
+		eventsRep:       eventsRep,
+		totpRep:         totpRep,
+		attemptsRep:     attemptsRep,
+		identitiesRep:   identitiesRep,
+		auditRep:        auditRep,
+		log:             log,
+		hasher:          hasher,
+		policy:          policy,
+		verifier:        verifier,
+		refreshTokenTtl: refreshTokenTtl,
+		jwtOpts:         jwtOpts,
+		reissueWindow:   reissueWindow,
+		verifyOpts:      verifyOpts,
+		totpOpts:        totpOpts,
+		lockoutOpts:     lockoutOpts,
+		registerOpts:    registerOpts,
+		magicLinkOpts:   magicLinkOpts,
+		impersonateOpts: impersonateOpts,
+		dummyHash:       dummyHash,
+		txCreator:       txCreator,
 	}
 }
 
@@ -41,21 +165,43 @@ func (as *AuthService) Register(ctx context.Context, req *transfer.RegisterInfo)
 	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, req.Email)
 	as.log.InfoContext(ctx, "trying to register user")
 
+	if err := validateNewPassword(ctx, as.policy, req.Password, &models.User{
+		Email: req.Email,
+		FName: req.FName,
+		LName: req.LName,
+	}); err != nil {
+		return nil, err
+	}
+
 	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
 	if err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
 	}
 	defer func() {
-		resErr = servicesutils.HandleErrInTransaction(err, tx)
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
 	}()
 
-	hashPass, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashPass, err := as.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate hashPass", err))
 	}
 
 	as.log.DebugContext(ctx, "hash pass is generated successfully")
 
+	if as.registerOpts.Silent {
+		registered, err := as.notifyRegisteredEmail(ctx, req.Email, tx)
+		if err != nil {
+			return nil, err
+		}
+		if registered {
+			if err := tx.Commit(); err != nil {
+				return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+			}
+
+			return &transfer.TokenResult{}, nil
+		}
+	}
+
 	userId, err := as.userRep.Create(ctx, &repositoriestransfer.CreateUserInfo{
 		Email:    req.Email,
 		FName:    req.FName,
@@ -69,13 +215,25 @@ func (as *AuthService) Register(ctx context.Context, req *transfer.RegisterInfo)
 	ctx = logger.UpdateLoggerCtx(ctx, createdUserIdLogKey, userId)
 	as.log.DebugContext(ctx, "user created in db successfully")
 
-	token, err := tokenshelper.CreateNewJwt(userId, req.Email, as.tokenTtl, as.tokenSecret)
-	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create new jwt", err))
+	// in the silent mode the user logs in after the registration, like the owner of a registered email would
+	tokens := &transfer.TokenResult{}
+	if !as.registerOpts.Silent {
+		tokens, err = as.createSession(ctx, tokenshelper.NewTokenInfo{
+			UserId: userId,
+			Email:  req.Email,
+		}, req.Client, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		as.log.DebugContext(ctx, "session created successfully")
 	}
 
-	ctx = logger.UpdateLoggerCtx(ctx, accessTokenLogKey, token)
-	as.log.DebugContext(ctx, "token created successfully")
+	if err := sendVerificationEmail(ctx, as.tokensRep, as.eventsRep, as.verifyOpts, userId, req.Email, tx); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t send verification email", err))
+	}
+
+	as.log.DebugContext(ctx, "verification email sent successfully")
 
 	if err := tx.Commit(); err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
@@ -83,16 +241,19 @@ func (as *AuthService) Register(ctx context.Context, req *transfer.RegisterInfo)
 
 	as.log.InfoContext(ctx, "user registered successfully")
 
-	return &transfer.TokenResult{
-		AccessToken: token,
-	}, nil
+	return tokens, nil
 }
 
-func (as *AuthService) Login(ctx context.Context, loginInfo *transfer.LoginInfo) (*transfer.TokenResult, error) {
+func (as *AuthService) Login(ctx context.Context, loginInfo *transfer.LoginInfo) (resToken *transfer.TokenResult, resErr error) {
 	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, loginInfo.Email)
 
 	as.log.InfoContext(ctx, "user try to login")
 
+	attemptsKeys := loginAttemptsKeys(as.lockoutOpts, loginInfo.Email, loginInfo.Client.IpAddress)
+	if err := checkLoginLocks(ctx, as.attemptsRep, attemptsKeys); err != nil {
+		return nil, err
+	}
+
 	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
 		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
 			repositoriestransfer.UserEmailCondition: loginInfo.Email,
@@ -100,7 +261,10 @@ func (as *AuthService) Login(ctx context.Context, loginInfo *transfer.LoginInfo)
 	}, nil)
 	if err != nil {
 		if errors.Is(err, ctxerrors.ErrNotFound) {
-			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("email not found", ctxerrors.ErrBadRequest))
+			as.log.DebugContext(ctx, "email not found")
+
+			_, _ = as.hasher.Verify(as.dummyHash, loginInfo.Password)
+			return nil, as.failLogin(ctx, attemptsKeys, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("invalid credentials", ctxerrors.ErrBadRequest)))
 		}
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
 	}
@@ -108,47 +272,1159 @@ func (as *AuthService) Login(ctx context.Context, loginInfo *transfer.LoginInfo)
 	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, user.Id)
 	as.log.DebugContext(ctx, "email is exists")
 
-	err = bcrypt.CompareHashAndPassword(user.PassHash, []byte(loginInfo.Password))
-	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("passwords not eq", ctxerrors.ErrBadRequest))
+	if err := checkPassword(ctx, as.hasher, user.PassHash, loginInfo.Password); err != nil {
+		if errors.Is(err, ctxerrors.ErrBadRequest) {
+			as.log.DebugContext(ctx, "password is wrong")
+
+			return nil, as.failLogin(ctx, attemptsKeys, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("invalid credentials", ctxerrors.ErrBadRequest)))
+		}
+		return nil, err
 	}
 
 	as.log.DebugContext(ctx, "password is correct")
 
-	token, err := tokenshelper.CreateNewJwt(user.Id, user.Email, as.tokenTtl, as.tokenSecret)
+	if as.verifyOpts.Required && !user.IsEmailVerified() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("email is not verified", ctxerrors.ErrUnauthorized))
+	}
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
 	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate jwt", err))
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	if err := as.rehashPassword(ctx, user, loginInfo.Password, tx); err != nil {
+		return nil, err
+	}
+
+	tokens, err := as.startSession(ctx, user, loginInfo.Client, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if tokens.MfaRequired {
+		return tokens, nil
+	}
+
+	// the counter is kept until the tokens are issued, wrong second factor codes are counted in it too.
+	// Only the email counter is reset, an ip may keep guessing passwords of other accounts
+	if err := as.attemptsRep.Reset(ctx, emailAttemptsKey(loginInfo.Email)); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t reset login failures", err))
 	}
 
-	as.log.DebugContext(ctx, "token created successfully", "as-token", token)
 	as.log.InfoContext(ctx, "user logged in successfully")
 
-	return &transfer.TokenResult{
-		AccessToken: token,
-	}, nil
+	return tokens, nil
+}
+
+// RequestMagicLink sends a login link to the email. Not registered emails get the link only when
+// the sign up by link is enabled, the caller is not told whether the email is registered.
+func (as *AuthService) RequestMagicLink(ctx context.Context, requestInfo *transfer.RequestMagicLinkInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, requestInfo.Email)
+
+	as.log.InfoContext(ctx, "trying to request magic link")
+
+	var userId uuid.UUID
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: requestInfo.Email,
+		},
+	}, nil)
+	switch {
+	case err == nil:
+		userId = user.Id
+		ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, userId)
+	case errors.Is(err, ctxerrors.ErrNotFound) && as.magicLinkOpts.SignUp:
+		as.log.DebugContext(ctx, "magic link signs up a new user")
+	case errors.Is(err, ctxerrors.ErrNotFound):
+		as.log.InfoContext(ctx, "magic link is requested for unknown email")
+		return nil
+	default:
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	rawToken, expiresAt, err := issueOneTimeToken(ctx, as.tokensRep, repositoriestransfer.CreateOneTimeTokenInfo{
+		UserId:  userId,
+		Purpose: repositoriestransfer.MagicLinkPurpose,
+		Email:   requestInfo.Email,
+	}, as.magicLinkOpts.TokenTTL, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t issue magic link token", err))
+	}
+
+	eventId := uuid.New()
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
+
+	if err := createOutboxEvent(ctx, as.eventsRep, eventId, amqpclient.MagicLinkRequestedEventKey, messages.MagicLinkRequestedMessage{
+		EventId:   eventId,
+		UserId:    userId,
+		Email:     requestInfo.Email,
+		Link:      as.magicLinkOpts.LinkBase + rawToken,
+		SignUp:    userId == uuid.Nil,
+		ExpiresAt: expiresAt,
+	}, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	as.log.InfoContext(ctx, "magic link requested successfully")
+
+	return nil
 }
 
-func (as *AuthService) CheckAuth(ctx context.Context, authInfo *transfer.CheckAuthInfo) (*transfer.TokenResult, error) {
-	parsedToken, err := tokenshelper.Parse(authInfo.AccessToken, as.tokenSecret)
+// RedeemMagicLink spends the link token and logs the user in like Login does. The link proves the email,
+// so it is marked as verified, and the account is created for the sign up links.
+func (as *AuthService) RedeemMagicLink(ctx context.Context, redeemInfo *transfer.RedeemMagicLinkInfo) (resToken *transfer.TokenResult, resErr error) {
+	as.log.InfoContext(ctx, "trying to redeem magic link")
 
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
 	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t parse token", ctxerrors.ErrUnauthorized))
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	token, err := spendOneTimeToken(ctx, as.tokensRep, redeemInfo.Token, repositoriestransfer.MagicLinkPurpose, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t spend magic link token", err))
 	}
-	if !parsedToken.Valid {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token is invalid", ctxerrors.ErrUnauthorized))
+	if !token.Email.Valid {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("magic link token has no email", ctxerrors.ErrBadRequest))
 	}
 
-	tokenClaims, err := tokenshelper.GetClaimsValues(parsedToken)
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, token.Email.String)
+
+	user, err := as.magicLinkUser(ctx, token, tx)
 	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t  parse jwt claims", ctxerrors.ErrUnauthorized))
+		return nil, err
 	}
 
-	newToken, err := tokenshelper.CreateNewJwt(tokenClaims.Id, tokenClaims.Email, as.tokenTtl, as.tokenSecret)
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, user.Id)
+
+	verified, err := as.markEmailVerified(ctx, user, tx)
 	if err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create jwt", err))
+		return nil, err
 	}
 
-	return &transfer.TokenResult{
-		AccessToken: newToken,
-	}, nil
+	tokens, err := as.startSession(ctx, user, redeemInfo.Client, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if verified {
+		if err := as.userRep.DeleteFromCache(ctx, user.Id); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+		}
+	}
+
+	if !tokens.MfaRequired {
+		as.log.InfoContext(ctx, "user logged in by magic link successfully")
+	}
+
+	return tokens, nil
+}
+
+// magicLinkUser returns the user the token is issued for, sign up tokens have no user and create the account.
+func (as *AuthService) magicLinkUser(ctx context.Context, token *models.OneTimeToken, tx database.Transaction) (*models.User, error) {
+	condition := map[repositoriestransfer.UserFieldTarget]interface{}{
+		repositoriestransfer.UserIdCondition: token.UserId,
+	}
+	if token.UserId == uuid.Nil {
+		// the email may be registered after the link was sent
+		condition = map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: token.Email.String,
+		}
+	}
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: condition,
+	}, tx)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, ctxerrors.ErrNotFound) || token.UserId != uuid.Nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if !as.magicLinkOpts.SignUp {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("sign up by magic link is disabled", ctxerrors.ErrBadRequest))
+	}
+
+	user, err = as.createPasswordlessUser(ctx, repositoriestransfer.CreateUserInfo{
+		Email: token.Email.String,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	as.log.InfoContext(ctx, "user signed up by magic link", createdUserIdLogKey, user.Id)
+
+	return user, nil
+}
+
+// createPasswordlessUser creates the account of a user signing up without a password. It gets a random one,
+// the user may set a password with the password reset.
+func (as *AuthService) createPasswordlessUser(ctx context.Context, createInfo repositoriestransfer.CreateUserInfo, tx database.Transaction) (*models.User, error) {
+	randomPassword, err := tokenshelper.NewOpaqueToken()
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate password", err))
+	}
+
+	createInfo.HashPass, err = as.hasher.Hash(randomPassword)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate hashPass", err))
+	}
+
+	userId, err := as.userRep.Create(ctx, &createInfo, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create user in db", err))
+	}
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: userId,
+		},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	return user, nil
+}
+
+// markEmailVerified reports whether the user is changed, the cached user has to be dropped
+// once the transaction is committed then.
+func (as *AuthService) markEmailVerified(ctx context.Context, user *models.User, tx database.Transaction) (bool, error) {
+	if user.IsEmailVerified() {
+		return false, nil
+	}
+
+	if err := as.userRep.Update(ctx, repositoriestransfer.UpdateUserInfo{
+		Id: user.Id,
+		UpdateInfo: map[string]interface{}{
+			"email_verified_at": time.Now(),
+		},
+	}, tx); err != nil {
+		return false, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update user in db", err))
+	}
+
+	return true, nil
+}
+
+// ExchangeExternalToken logs in with an id token of a configured OIDC provider. An unknown identity is linked
+// to the account with its email, or a new account is created when the email is not registered.
+func (as *AuthService) ExchangeExternalToken(ctx context.Context, exchangeInfo *transfer.ExchangeExternalTokenInfo) (resToken *transfer.TokenResult, resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, identityProviderLogKey, exchangeInfo.Provider)
+
+	as.log.InfoContext(ctx, "trying to exchange external token")
+
+	identity, err := verifyExternalToken(ctx, as.verifier, exchangeInfo.Provider, exchangeInfo.IdToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	user, err := as.externalIdentityUser(ctx, identity, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, user.Id)
+
+	tokens, err := as.startSession(ctx, user, exchangeInfo.Client, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if !tokens.MfaRequired {
+		as.log.InfoContext(ctx, "user logged in by external token successfully")
+	}
+
+	return tokens, nil
+}
+
+// externalIdentityUser returns the user the identity is linked to. An identity is linked to a registered email
+// only if both the provider and this service have verified it, otherwise anyone registering the email first
+// would get the account of its owner.
+func (as *AuthService) externalIdentityUser(ctx context.Context, identity *oidcclient.Identity, tx database.Transaction) (*models.User, error) {
+	linked, err := as.identitiesRep.Identity(ctx, repositoriestransfer.GetExternalIdentityInfo{
+		Condition: map[repositoriestransfer.ExternalIdentityFieldTarget]any{
+			repositoriestransfer.ExternalIdentityProviderCondition: identity.Provider,
+			repositoriestransfer.ExternalIdentitySubjectCondition:  identity.Subject,
+		},
+	}, tx)
+	if err == nil {
+		user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+			Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+				repositoriestransfer.UserIdCondition: linked.UserId,
+			},
+		}, tx)
+		if err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+		}
+
+		return user, nil
+	}
+	if !errors.Is(err, ctxerrors.ErrNotFound) {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get external identity from db", err))
+	}
+
+	if identity.Email == "" {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("external token has no email", ctxerrors.ErrBadRequest))
+	}
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: identity.Email,
+		},
+	}, tx)
+	switch {
+	case err == nil:
+		if !identity.EmailVerified || !user.IsEmailVerified() {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("email is registered, log in to link the identity", ctxerrors.ErrConflict))
+		}
+	case errors.Is(err, ctxerrors.ErrNotFound):
+		user, err = as.createPasswordlessUser(ctx, repositoriestransfer.CreateUserInfo{
+			Email: identity.Email,
+			FName: identity.FName,
+			LName: identity.LName,
+		}, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		as.log.InfoContext(ctx, "user signed up by external token", createdUserIdLogKey, user.Id)
+
+		// the user is created in this transaction, so it can`t be cached yet
+		if identity.EmailVerified {
+			if _, err := as.markEmailVerified(ctx, user, tx); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if _, err := as.identitiesRep.Create(ctx, repositoriestransfer.CreateExternalIdentityInfo{
+		UserId:   user.Id,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}, tx); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create external identity in db", err))
+	}
+
+	return user, nil
+}
+
+// startSession completes a login: users with totp enabled get an mfa challenge, the others a new session.
+func (as *AuthService) startSession(
+	ctx context.Context,
+	user *models.User,
+	client transfer.ClientInfo,
+	tx database.Transaction,
+) (*transfer.TokenResult, error) {
+	totpEnabled, err := isTotpEnabled(ctx, as.totpRep, user.Id, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check totp", err))
+	}
+	if totpEnabled {
+		mfaToken, _, err := issueOneTimeToken(ctx, as.tokensRep, repositoriestransfer.CreateOneTimeTokenInfo{
+			UserId:  user.Id,
+			Purpose: repositoriestransfer.MfaChallengePurpose,
+		}, as.totpOpts.ChallengeTTL, tx)
+		if err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t issue mfa challenge", err))
+		}
+
+		as.log.InfoContext(ctx, "mfa challenge issued")
+
+		return &transfer.TokenResult{
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+
+	tokenInfo, err := as.userTokenInfo(ctx, user, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.createSession(ctx, tokenInfo, client, tx)
+}
+
+// notifyRegisteredEmail sends the owner of a registered email a notice about the registration attempt
+// and reports whether the email is registered.
+func (as *AuthService) notifyRegisteredEmail(ctx context.Context, email string, tx database.Transaction) (bool, error) {
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: email,
+		},
+	}, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return false, nil
+		}
+		return false, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	eventId := uuid.New()
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
+
+	if err := createOutboxEvent(ctx, as.eventsRep, eventId, amqpclient.RegistrationAttemptNoticeEventKey, messages.RegistrationAttemptNoticeMessage{
+		EventId:     eventId,
+		UserId:      user.Id,
+		Email:       user.Email,
+		AttemptedAt: time.Now(),
+	}, tx); err != nil {
+		return false, err
+	}
+
+	as.log.InfoContext(ctx, "registration attempt with a registered email, owner is notified")
+
+	return true, nil
+}
+
+// rehashPassword replaces a verified password hash produced by another algorithm or with outdated parameters.
+func (as *AuthService) rehashPassword(ctx context.Context, user *models.User, password string, tx database.Transaction) error {
+	if !as.hasher.NeedsRehash(user.PassHash) {
+		return nil
+	}
+
+	hashPass, err := as.hasher.Hash(password)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate hashPass", err))
+	}
+
+	if err := as.userRep.ReplacePasswordHash(ctx, repositoriestransfer.UpdatePasswordInfo{
+		Id:       user.Id,
+		PassHash: hashPass,
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t replace password hash in db", err))
+	}
+
+	as.log.InfoContext(ctx, "password hash upgraded")
+
+	return nil
+}
+
+// UnlockAccount removes the lockout and the failed login counter of the user email.
+func (as *AuthService) UnlockAccount(ctx context.Context, unlockInfo *transfer.UnlockAccountInfo) error {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, unlockInfo.UserId)
+
+	as.log.InfoContext(ctx, "trying to unlock account")
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: unlockInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if err := as.attemptsRep.Reset(ctx, emailAttemptsKey(user.Email)); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t reset login failures", err))
+	}
+
+	as.log.InfoContext(ctx, "account unlocked successfully")
+
+	return nil
+}
+
+// VerifyMfa completes the login started by Login with a totp or a recovery code.
+func (as *AuthService) VerifyMfa(ctx context.Context, verifyInfo *transfer.VerifyMfaInfo) (resToken *transfer.TokenResult, resErr error) {
+	as.log.InfoContext(ctx, "trying to verify mfa")
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	challenge, err := spendOneTimeToken(ctx, as.tokensRep, verifyInfo.MfaToken, repositoriestransfer.MfaChallengePurpose, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t spend mfa challenge", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, challenge.UserId)
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: challenge.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	attemptsKeys := loginAttemptsKeys(as.lockoutOpts, user.Email, verifyInfo.Client.IpAddress)
+	if err := checkLoginLocks(ctx, as.attemptsRep, attemptsKeys); err != nil {
+		return nil, err
+	}
+
+	// the challenge is spent only together with an accepted code, the transaction is rolled back otherwise
+	// until the challenge runs out of attempts
+	if err := verifySecondFactor(ctx, as.totpRep, as.totpOpts, challenge.UserId, verifyInfo.Code, verifyInfo.RecoveryCode, tx); err != nil {
+		if errors.Is(err, ctxerrors.ErrUnauthorized) {
+			return nil, as.failMfa(ctx, attemptsKeys, mfaChallengeAttemptsKey(challenge.Id), err, tx)
+		}
+		return nil, err
+	}
+
+	tokenInfo, err := as.userTokenInfo(ctx, user, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := as.createSession(ctx, tokenInfo, verifyInfo.Client, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := as.attemptsRep.Reset(ctx, emailAttemptsKey(user.Email)); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t reset login failures", err))
+	}
+
+	as.log.InfoContext(ctx, "user logged in with mfa successfully")
+
+	return tokens, nil
+}
+
+// CheckAuth validates the token and returns its claims. A new access token is issued only
+// when the checked one expires within the reissue window, its claims are built from the db.
+func (as *AuthService) CheckAuth(ctx context.Context, authInfo *transfer.CheckAuthInfo) (*transfer.CheckAuthResult, error) {
+	tokenClaims, err := tokenshelper.Parse(authInfo.AccessToken, as.jwtOpts)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t parse token", ctxerrors.ErrUnauthorized))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, tokenClaims.Id)
+
+	if err := as.CheckClaims(ctx, tokenClaims); err != nil {
+		return nil, err
+	}
+
+	result := &transfer.CheckAuthResult{
+		Claims: transfer.GetClaimsResultFromToken(tokenClaims),
+	}
+
+	// impersonation tokens end at their expiration
+	if time.Until(tokenClaims.ExpiresAt) > as.reissueWindow || tokenClaims.IsImpersonation() {
+		return result, nil
+	}
+
+	// the claims are read again, the checked token may carry outdated roles
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: tokenClaims.Id,
+		},
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	tokenInfo, err := as.userTokenInfo(ctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokenInfo.SessionId = tokenClaims.SessionId
+
+	newToken, err := tokenshelper.CreateNewJwt(tokenInfo, as.jwtOpts)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create jwt", err))
+	}
+
+	as.log.DebugContext(ctx, "token reissued")
+
+	result.AccessToken = newToken
+
+	return result, nil
+}
+
+// CheckClaims rejects parsed tokens that are revoked, outdated or belong to a revoked session.
+func (as *AuthService) CheckClaims(ctx context.Context, tokenClaims tokenshelper.TokenClaims) error {
+	if err := as.checkRevocation(ctx, tokenClaims); err != nil {
+		return err
+	}
+	if err := as.checkTokenVersion(ctx, tokenClaims); err != nil {
+		return err
+	}
+
+	return as.checkSession(ctx, tokenClaims)
+}
+
+func (as *AuthService) RefreshToken(ctx context.Context, refreshInfo *transfer.RefreshTokenInfo) (resToken *transfer.TokenResult, resErr error) {
+	as.log.InfoContext(ctx, "trying to refresh token")
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	oldToken, err := as.refreshRep.Token(ctx, repositoriestransfer.GetRefreshTokenInfo{
+		Condition: map[repositoriestransfer.RefreshTokenFieldTarget]any{
+			repositoriestransfer.RefreshTokenHashCondition: tokenshelper.HashOpaqueToken(refreshInfo.RefreshToken),
+		},
+	}, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("refresh token not found", ctxerrors.ErrUnauthorized))
+		}
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get refresh token from db", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, oldToken.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, tokenFamilyIdLogKey, oldToken.FamilyId)
+
+	if oldToken.IsRevoked() {
+		as.log.WarnContext(ctx, "revoked refresh token is reused, revoking token family")
+
+		// revoke outside the transaction: it is rolled back because of the returned error
+		if _, err := revokeSessions(ctx, as.sessionsRep, as.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+			Condition: map[repositoriestransfer.SessionFieldTarget]any{
+				repositoriestransfer.SessionIdCondition: oldToken.FamilyId,
+			},
+		}, nil); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke refresh token family", err))
+		}
+
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("refresh token is reused", ctxerrors.ErrUnauthorized))
+	}
+	if oldToken.IsExpired() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("refresh token is expired", ctxerrors.ErrUnauthorized))
+	}
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: oldToken.UserId,
+		},
+	}, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token owner not found", ctxerrors.ErrUnauthorized))
+		}
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	rawToken, err := tokenshelper.NewOpaqueToken()
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate refresh token", err))
+	}
+
+	newTokenId, err := as.refreshRep.Create(ctx, repositoriestransfer.CreateRefreshTokenInfo{
+		UserId:    oldToken.UserId,
+		FamilyId:  oldToken.FamilyId,
+		TokenHash: tokenshelper.HashOpaqueToken(rawToken),
+		ExpiresAt: time.Now().Add(as.refreshTokenTtl),
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create refresh token in db", err))
+	}
+
+	revoked, err := as.refreshRep.Revoke(ctx, repositoriestransfer.RevokeRefreshTokensInfo{
+		Condition: map[repositoriestransfer.RefreshTokenFieldTarget]any{
+			repositoriestransfer.RefreshTokenIdCondition: oldToken.Id,
+		},
+		ReplacedBy: uuid.NullUUID{UUID: newTokenId, Valid: true},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke refresh token", err))
+	}
+	if revoked == 0 {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("refresh token is already rotated", ctxerrors.ErrUnauthorized))
+	}
+
+	as.log.DebugContext(ctx, "refresh token rotated successfully")
+
+	tokenInfo, err := as.userTokenInfo(ctx, user, tx)
+	if err != nil {
+		return nil, err
+	}
+	tokenInfo.SessionId = oldToken.FamilyId
+
+	token, err := tokenshelper.CreateNewJwt(tokenInfo, as.jwtOpts)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create jwt", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	as.log.InfoContext(ctx, "token refreshed successfully")
+
+	return &transfer.TokenResult{
+		AccessToken:  token,
+		RefreshToken: rawToken,
+	}, nil
+}
+
+func (as *AuthService) Logout(ctx context.Context, logoutInfo *transfer.LogoutInfo) error {
+	as.log.InfoContext(ctx, "user try to logout")
+
+	token, err := as.refreshRep.Token(ctx, repositoriestransfer.GetRefreshTokenInfo{
+		Condition: map[repositoriestransfer.RefreshTokenFieldTarget]any{
+			repositoriestransfer.RefreshTokenHashCondition: tokenshelper.HashOpaqueToken(logoutInfo.RefreshToken),
+		},
+	}, nil)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("refresh token not found", ctxerrors.ErrUnauthorized))
+		}
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get refresh token from db", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, token.UserId)
+
+	revokeCondition := map[repositoriestransfer.SessionFieldTarget]any{
+		repositoriestransfer.SessionIdCondition: token.FamilyId,
+	}
+	if logoutInfo.AllSessions {
+		revokeCondition = map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionUserIdCondition: token.UserId,
+		}
+	}
+
+	revoked, err := revokeSessions(ctx, as.sessionsRep, as.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+		Condition: revokeCondition,
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke sessions", err))
+	}
+
+	as.log.InfoContext(ctx, "user logged out successfully", "revoked-sessions", revoked)
+
+	return nil
+}
+
+func (as *AuthService) RevokeToken(ctx context.Context, revokeInfo *transfer.RevokeTokenInfo) error {
+	tokenClaims, err := tokenshelper.Parse(revokeInfo.AccessToken, as.jwtOpts)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t parse token", ctxerrors.ErrUnauthorized))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, tokenClaims.Id)
+	ctx = logger.UpdateLoggerCtx(ctx, tokenJtiLogKey, tokenClaims.Jti)
+
+	as.log.InfoContext(ctx, "trying to revoke token")
+
+	if err := as.revocationsRep.RevokeToken(ctx, repositoriestransfer.RevokeTokenInfo{
+		Jti:       tokenClaims.Jti,
+		UserId:    tokenClaims.Id,
+		ExpiresAt: tokenClaims.ExpiresAt,
+	}, nil); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke token", err))
+	}
+
+	if err := as.revocationsRep.SetRevokedTokenToCache(ctx, tokenClaims.Jti); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t cache token revocation", err))
+	}
+
+	as.log.InfoContext(ctx, "token revoked successfully")
+
+	return nil
+}
+
+func (as *AuthService) RevokeUserTokens(ctx context.Context, revokeInfo *transfer.RevokeUserTokensInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, revokeInfo.UserId)
+
+	as.log.InfoContext(ctx, "trying to revoke all user tokens")
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	revocation := repositoriestransfer.RevokeUserTokensInfo{
+		UserId:        revokeInfo.UserId,
+		RevokedBefore: time.Now(),
+	}
+	if err := as.revocationsRep.RevokeUserTokens(ctx, revocation, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke user access tokens", err))
+	}
+
+	// revoked_before has second precision, the tokens issued in the second of the revocation
+	// are rejected by their token version
+	if err := as.userRep.BumpTokenVersion(ctx, revokeInfo.UserId, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t bump token version", err))
+	}
+
+	if _, err := revokeSessions(ctx, as.sessionsRep, as.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+		Condition: map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionUserIdCondition: revokeInfo.UserId,
+		},
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke user sessions", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := as.revocationsRep.SetRevokedUserTokensToCache(ctx, revocation); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t cache user tokens revocation", err))
+	}
+
+	if err := as.userRep.DeleteFromCache(ctx, revokeInfo.UserId); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	as.log.InfoContext(ctx, "user tokens revoked successfully")
+
+	return nil
+}
+
+// Impersonate issues a short-lived token of the user for the actor, it has no refresh token and carries
+// the actor in the act claim. The start is written into the audit log before the token is returned.
+func (as *AuthService) Impersonate(ctx context.Context, impersonateInfo *transfer.ImpersonateInfo) (*transfer.ImpersonationResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, impersonateInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, actorIdLogKey, impersonateInfo.ActorId)
+
+	as.log.InfoContext(ctx, "trying to impersonate user")
+
+	if impersonateInfo.ActorId == impersonateInfo.UserId {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t impersonate yourself", ctxerrors.ErrBadRequest))
+	}
+
+	user, err := as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: impersonateInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	tokenInfo, err := as.userTokenInfo(ctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the actor acts as the user, not as the user's roles
+	tokenInfo.Roles = nil
+	tokenInfo.SessionId = impersonateInfo.ActorSessionId
+	tokenInfo.ActorId = impersonateInfo.ActorId
+	tokenInfo.Jti = uuid.New()
+
+	jwtOpts := as.jwtOpts
+	jwtOpts.TTL = as.impersonateOpts.TokenTTL
+
+	token, err := tokenshelper.CreateNewJwt(tokenInfo, jwtOpts)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate jwt", err))
+	}
+
+	if _, err := as.auditRep.Create(ctx, repositoriestransfer.CreateAuditEntryInfo{
+		Action:       models.AuditImpersonationStarted,
+		ActorId:      uuid.NullUUID{UUID: impersonateInfo.ActorId, Valid: true},
+		TargetUserId: uuid.NullUUID{UUID: user.Id, Valid: true},
+		TokenId:      uuid.NullUUID{UUID: tokenInfo.Jti, Valid: true},
+		Reason:       impersonateInfo.Reason,
+		IpAddress:    impersonateInfo.Client.IpAddress,
+	}, nil); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t write audit entry", err))
+	}
+
+	as.log.WarnContext(ctx, "impersonation started", tokenJtiLogKey, tokenInfo.Jti)
+
+	return &transfer.ImpersonationResult{
+		AccessToken: token,
+		ExpiresAt:   time.Now().Add(jwtOpts.TTL),
+	}, nil
+}
+
+// StopImpersonation revokes the impersonation token, like RevokeToken the token itself authorizes the call.
+func (as *AuthService) StopImpersonation(ctx context.Context, stopInfo *transfer.StopImpersonationInfo) (resErr error) {
+	tokenClaims, err := tokenshelper.Parse(stopInfo.AccessToken, as.jwtOpts)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t parse token", ctxerrors.ErrUnauthorized))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, tokenClaims.Id)
+	ctx = logger.UpdateLoggerCtx(ctx, actorIdLogKey, tokenClaims.ActorId)
+	ctx = logger.UpdateLoggerCtx(ctx, tokenJtiLogKey, tokenClaims.Jti)
+
+	as.log.InfoContext(ctx, "trying to stop impersonation")
+
+	if !tokenClaims.IsImpersonation() {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token is not an impersonation token", ctxerrors.ErrBadRequest))
+	}
+
+	tx, err := as.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	if err := as.revocationsRep.RevokeToken(ctx, repositoriestransfer.RevokeTokenInfo{
+		Jti:       tokenClaims.Jti,
+		UserId:    tokenClaims.Id,
+		ExpiresAt: tokenClaims.ExpiresAt,
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke token", err))
+	}
+
+	if _, err := as.auditRep.Create(ctx, repositoriestransfer.CreateAuditEntryInfo{
+		Action:       models.AuditImpersonationStopped,
+		ActorId:      uuid.NullUUID{UUID: tokenClaims.ActorId, Valid: true},
+		TargetUserId: uuid.NullUUID{UUID: tokenClaims.Id, Valid: true},
+		TokenId:      uuid.NullUUID{UUID: tokenClaims.Jti, Valid: true},
+		IpAddress:    stopInfo.Client.IpAddress,
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t write audit entry", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := as.revocationsRep.SetRevokedTokenToCache(ctx, tokenClaims.Jti); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t cache token revocation", err))
+	}
+
+	as.log.WarnContext(ctx, "impersonation stopped")
+
+	return nil
+}
+
+func (as *AuthService) GetJWKS(ctx context.Context) (*transfer.JWKSResult, error) {
+	as.log.DebugContext(ctx, "trying to get jwks")
+
+	return transfer.GetJWKSResultFromKeys(as.jwtOpts.Keys.PublicJWKs()), nil
+}
+
+func (as *AuthService) checkRevocation(ctx context.Context, tokenClaims tokenshelper.TokenClaims) error {
+	revoked, err := as.revocationsRep.IsTokenRevoked(ctx, tokenClaims.Jti)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check token revocation", err))
+	}
+	if revoked {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token is revoked", ctxerrors.ErrUnauthorized))
+	}
+
+	revokedBefore, err := as.revocationsRep.UserTokensRevokedBefore(ctx, tokenClaims.Id)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check user tokens revocation", err))
+	}
+	// iat has second precision, the tokens issued in the second of the revocation are left to checkTokenVersion,
+	// the token issued right after the revocation must stay valid
+	if tokenClaims.IssuedAt.Unix() < revokedBefore.Unix() {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("user tokens are revoked", ctxerrors.ErrUnauthorized))
+	}
+
+	return nil
+}
+
+// checkTokenVersion rejects tokens issued before the last password change. The user is read
+// from the cache first, it is dropped from there whenever the version is bumped.
+func (as *AuthService) checkTokenVersion(ctx context.Context, tokenClaims tokenshelper.TokenClaims) error {
+	user, err := as.userRep.TryGetFromCache(ctx, tokenClaims.Id)
+	if err != nil {
+		as.log.DebugContext(ctx, "can`t get user from cache", logger.ErrKey, err.Error())
+
+		user, err = as.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+			Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+				repositoriestransfer.UserIdCondition: tokenClaims.Id,
+			},
+		}, nil)
+		if err != nil {
+			if errors.Is(err, ctxerrors.ErrNotFound) {
+				return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token owner not found", ctxerrors.ErrUnauthorized))
+			}
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+		}
+
+		if err := as.userRep.SetToCache(ctx, user); err != nil {
+			as.log.DebugContext(ctx, "can`t set user to cache", logger.ErrKey, err.Error())
+		}
+	}
+
+	if tokenClaims.Version != user.TokenVersion {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token version is outdated", ctxerrors.ErrUnauthorized))
+	}
+
+	return nil
+}
+
+// checkSession rejects tokens of revoked sessions and marks the session as seen.
+func (as *AuthService) checkSession(ctx context.Context, tokenClaims tokenshelper.TokenClaims) error {
+	active, err := as.sessionsRep.IsSessionActive(ctx, tokenClaims.SessionId)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check session", err))
+	}
+	if !active {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("session is revoked", ctxerrors.ErrUnauthorized))
+	}
+
+	// last_seen_at is informational, a failed update must not fail the check
+	if err := as.sessionsRep.Touch(ctx, tokenClaims.SessionId); err != nil {
+		as.log.DebugContext(ctx, "can`t update session last seen", logger.ErrKey, err.Error())
+	}
+
+	return nil
+}
+
+// failLogin registers the failed attempt and returns loginErr once the progressive delay is over.
+func (as *AuthService) failLogin(ctx context.Context, keys []repositoriestransfer.LoginAttemptsKey, loginErr error) error {
+	return failAttempt(ctx, as.log, as.attemptsRep, as.lockoutOpts, keys, loginErr)
+}
+
+// failMfa counts the wrong code like a failed login. Once the challenge reached MaxMfaAttempts the transaction
+// is committed with the challenge spent, the rejected code has changed nothing else. The transaction is finished
+// before the progressive delay, so a wrong code doesn't hold a connection while waiting.
+func (as *AuthService) failMfa(
+	ctx context.Context,
+	keys []repositoriestransfer.LoginAttemptsKey,
+	challengeKey repositoriestransfer.LoginAttemptsKey,
+	mfaErr error,
+	tx database.Transaction,
+) error {
+	delay, err := countLoginFailure(ctx, as.attemptsRep, as.lockoutOpts, append(keys, challengeKey))
+	if err != nil {
+		as.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t register login failure", logger.ErrKey, err.Error())
+	}
+
+	burned, err := as.attemptsRep.IsLocked(ctx, challengeKey)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check mfa challenge lock", err))
+	}
+
+	if burned {
+		if err := tx.Commit(); err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t burn mfa challenge", err))
+		}
+
+		as.log.InfoContext(ctx, "mfa challenge is burned after too many wrong codes")
+	} else if err := tx.Rollback(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t rollback transaction", err))
+	}
+
+	if err := waitLoginDelay(ctx, delay); err != nil {
+		as.log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t wait login delay", logger.ErrKey, err.Error())
+	}
+
+	return mfaErr
+}
+
+// createSession stores a new session and issues the first token pair of it,
+// the session id is used as the refresh token family id.
+func (as *AuthService) createSession(
+	ctx context.Context,
+	tokenInfo tokenshelper.NewTokenInfo,
+	client transfer.ClientInfo,
+	tx database.Transaction,
+) (*transfer.TokenResult, error) {
+	sessionId := uuid.New()
+
+	if err := as.sessionsRep.Create(ctx, repositoriestransfer.CreateSessionInfo{
+		Id:        sessionId,
+		UserId:    tokenInfo.UserId,
+		UserAgent: client.UserAgent,
+		IpAddress: client.IpAddress,
+	}, tx); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create session in db", err))
+	}
+
+	tokenInfo.SessionId = sessionId
+
+	token, err := tokenshelper.CreateNewJwt(tokenInfo, as.jwtOpts)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate jwt", err))
+	}
+
+	as.log.DebugContext(ctx, "token created successfully")
+
+	refreshToken, err := as.createRefreshToken(ctx, tokenInfo.UserId, sessionId, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create refresh token", err))
+	}
+
+	return &transfer.TokenResult{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// userTokenInfo collects the claims of the user, the session id is set by the caller.
+func (as *AuthService) userTokenInfo(ctx context.Context, user *models.User, tx database.Transaction) (tokenshelper.NewTokenInfo, error) {
+	roles, err := as.rolesRep.UserRoles(ctx, user.Id, tx)
+	if err != nil {
+		return tokenshelper.NewTokenInfo{}, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user roles", err))
+	}
+
+	return tokenshelper.NewTokenInfo{
+		UserId:        user.Id,
+		Email:         user.Email,
+		Roles:         roles,
+		Version:       user.TokenVersion,
+		EmailVerified: user.IsEmailVerified(),
+	}, nil
+}
+
+func (as *AuthService) createRefreshToken(ctx context.Context, userId uuid.UUID, familyId uuid.UUID, tx database.Transaction) (string, error) {
+	rawToken, err := tokenshelper.NewOpaqueToken()
+	if err != nil {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate refresh token", err))
+	}
+
+	if _, err := as.refreshRep.Create(ctx, repositoriestransfer.CreateRefreshTokenInfo{
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: tokenshelper.HashOpaqueToken(rawToken),
+		ExpiresAt: time.Now().Add(as.refreshTokenTtl),
+	}, tx); err != nil {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create refresh token in db", err))
+	}
+
+	return rawToken, nil
 }
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	"github.com/google/uuid"
+)
+
+type refreshTest struct {
+	svc       *AuthService
+	refresh   *fakeRefreshTokens
+	sessions  *fakeSessions
+	txs       *fakeTxCreator
+	user      *models.User
+	sessionId uuid.UUID
+	token     string
+}
+
+// newRefreshTest starts a session of a user, its refresh token is rt.token.
+func newRefreshTest(t *testing.T, refreshTokenTtl time.Duration) *refreshTest {
+	t.Helper()
+
+	user := models.NewUserModel("user@example.com", "First", "Last", nil)
+	rt := &refreshTest{
+		user:     user,
+		refresh:  &fakeRefreshTokens{},
+		sessions: newFakeSessions(),
+		txs:      &fakeTxCreator{},
+	}
+	rt.svc = &AuthService{
+		userRep:     &fakeUsers{users: []*models.User{user}},
+		refreshRep:  rt.refresh,
+		sessionsRep: rt.sessions,
+		rolesRep:    &fakeUserRoles{},
+		log:         testLogger(),
+		jwtOpts: tokenshelper.JwtOptions{
+			Keys:     tokenshelper.NewHmacKeySet("test-secret"),
+			Issuer:   "test",
+			Audience: "test",
+			TTL:      time.Minute,
+		},
+		refreshTokenTtl: refreshTokenTtl,
+		txCreator:       rt.txs,
+	}
+
+	rt.sessionId, rt.token = rt.newSession(t)
+
+	return rt
+}
+
+func (rt *refreshTest) newSession(t *testing.T) (uuid.UUID, string) {
+	t.Helper()
+
+	tokenInfo, err := rt.svc.userTokenInfo(context.Background(), rt.user, nil)
+	if err != nil {
+		t.Fatalf("userTokenInfo: %v", err)
+	}
+	tokens, err := rt.svc.createSession(context.Background(), tokenInfo, transfer.ClientInfo{}, nil)
+	if err != nil {
+		t.Fatalf("createSession: %v", err)
+	}
+
+	claims, err := tokenshelper.Parse(tokens.AccessToken, rt.svc.jwtOpts)
+	if err != nil {
+		t.Fatalf("can`t parse access token: %v", err)
+	}
+
+	return claims.SessionId, tokens.RefreshToken
+}
+
+func (rt *refreshTest) refreshToken(token string) (*transfer.TokenResult, error) {
+	return rt.svc.RefreshToken(context.Background(), &transfer.RefreshTokenInfo{RefreshToken: token})
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	rt := newRefreshTest(t, time.Hour)
+
+	tokens, err := rt.refreshToken(rt.token)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.RefreshToken == rt.token {
+		t.Fatalf("RefreshToken tokens = %+v, want new access and refresh tokens", tokens)
+	}
+	if !rt.txs.last().committed {
+		t.Errorf("RefreshToken didn't commit the transaction")
+	}
+
+	family := rt.refresh.byFamily(rt.sessionId)
+	if len(family) != 2 {
+		t.Fatalf("token family has %d tokens, want 2", len(family))
+	}
+	rotated, issued := family[0], family[1]
+	if !rotated.IsRevoked() || rotated.ReplacedBy.UUID != issued.Id {
+		t.Errorf("rotated token = %+v, want revoked and replaced by %s", rotated, issued.Id)
+	}
+	if issued.IsRevoked() {
+		t.Errorf("issued token is revoked")
+	}
+
+	// the issued token keeps rotating within the session
+	if _, err := rt.refreshToken(tokens.RefreshToken); err != nil {
+		t.Fatalf("RefreshToken with the issued token: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	rt := newRefreshTest(t, time.Hour)
+
+	tokens, err := rt.refreshToken(rt.token)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	if _, err := rt.refreshToken(rt.token); !errors.Is(err, ctxerrors.ErrUnauthorized) {
+		t.Fatalf("RefreshToken with the rotated token err = %v, want ErrUnauthorized", err)
+	}
+	if !rt.txs.last().rolledBack {
+		t.Errorf("RefreshToken of a reused token didn't roll the transaction back")
+	}
+
+	if !rt.sessions.revoked[rt.sessionId] {
+		t.Errorf("session of the reused token is not revoked")
+	}
+	for _, token := range rt.refresh.byFamily(rt.sessionId) {
+		if !token.IsRevoked() {
+			t.Errorf("token %s of the family is not revoked", token.Id)
+		}
+	}
+
+	// the token issued by the rotation belongs to the family, the attacker and the owner both lose it
+	if _, err := rt.refreshToken(tokens.RefreshToken); !errors.Is(err, ctxerrors.ErrUnauthorized) {
+		t.Fatalf("RefreshToken with the issued token err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRefreshTokenReuseKeepsOtherSessions(t *testing.T) {
+	rt := newRefreshTest(t, time.Hour)
+	otherSessionId, otherToken := rt.newSession(t)
+
+	if _, err := rt.refreshToken(rt.token); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if _, err := rt.refreshToken(rt.token); !errors.Is(err, ctxerrors.ErrUnauthorized) {
+		t.Fatalf("RefreshToken with the rotated token err = %v, want ErrUnauthorized", err)
+	}
+
+	if rt.sessions.revoked[otherSessionId] {
+		t.Errorf("other session of the user is revoked")
+	}
+	if _, err := rt.refreshToken(otherToken); err != nil {
+		t.Fatalf("RefreshToken of the other session: %v", err)
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		token func(rt *refreshTest) string
+	}{
+		{name: "unknown token", ttl: time.Hour, token: func(*refreshTest) string { return "unknown" }},
+		{name: "expired token", ttl: -time.Minute, token: func(rt *refreshTest) string { return rt.token }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newRefreshTest(t, tt.ttl)
+
+			if _, err := rt.refreshToken(tt.token(rt)); !errors.Is(err, ctxerrors.ErrUnauthorized) {
+				t.Fatalf("RefreshToken err = %v, want ErrUnauthorized", err)
+			}
+			if rt.sessions.revoked[rt.sessionId] {
+				t.Errorf("session is revoked by a rejected token")
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient/messages"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"github.com/google/uuid"
+	"time"
+)
+
+// EmailVerificationOptions configure the verification emails. When Required is set
+// users with not verified email can't log in.
+type EmailVerificationOptions struct {
+	TokenTTL time.Duration
+	LinkBase string
+	Required bool
+}
+
+type EmailChangeOptions struct {
+	TokenTTL time.Duration
+	LinkBase string
+}
+
+type emailSvcUserStore interface {
+	dep.UserGetter
+	dep.UserUpdater
+	dep.UserDeleter
+	dep.UserTokenVersionBumper
+}
+
+type EmailService struct {
+	userRep    emailSvcUserStore
+	tokensRep  oneTimeTokensStore
+	eventsRep  dep.EventCreator
+	log        logger.Logger
+	hasher     passwordshelper.Hasher
+	verifyOpts EmailVerificationOptions
+	changeOpts EmailChangeOptions
+	txCreator  dep.TransactionCreator
+}
+
+func NewEmailService(
+	userRep emailSvcUserStore,
+	tokensRep oneTimeTokensStore,
+	eventsRep dep.EventCreator,
+	log logger.Logger,
+	hasher passwordshelper.Hasher,
+	verifyOpts EmailVerificationOptions,
+	changeOpts EmailChangeOptions,
+	txCreator dep.TransactionCreator,
+) *EmailService {
+	return &EmailService{
+		userRep:    userRep,
+		tokensRep:  tokensRep,
+		eventsRep:  eventsRep,
+		log:        log,
+		hasher:     hasher,
+		verifyOpts: verifyOpts,
+		changeOpts: changeOpts,
+		txCreator:  txCreator,
+	}
+}
+
+// SendVerificationEmail sends a new verification link. Unknown and already verified emails
+// are silently skipped, so the method can't be used to find registered users.
+func (es *EmailService) SendVerificationEmail(ctx context.Context, sendInfo *transfer.SendVerificationEmailInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, sendInfo.Email)
+
+	es.log.InfoContext(ctx, "trying to send verification email")
+
+	user, err := es.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: sendInfo.Email,
+		},
+	}, nil)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			es.log.InfoContext(ctx, "verification email is requested for unknown email")
+			return nil
+		}
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, user.Id)
+
+	if user.IsEmailVerified() {
+		es.log.InfoContext(ctx, "email is already verified")
+		return nil
+	}
+
+	tx, err := es.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	if err := sendVerificationEmail(ctx, es.tokensRep, es.eventsRep, es.verifyOpts, user.Id, user.Email, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	es.log.InfoContext(ctx, "verification email sent successfully")
+
+	return nil
+}
+
+func (es *EmailService) VerifyEmail(ctx context.Context, verifyInfo *transfer.VerifyEmailInfo) (resErr error) {
+	es.log.InfoContext(ctx, "trying to verify email")
+
+	tx, err := es.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	token, err := spendOneTimeToken(ctx, es.tokensRep, verifyInfo.Token, repositoriestransfer.EmailVerificationPurpose, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t spend verification token", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, token.UserId)
+
+	if err := es.userRep.Update(ctx, repositoriestransfer.UpdateUserInfo{
+		Id: token.UserId,
+		UpdateInfo: map[string]interface{}{
+			"email_verified_at": time.Now(),
+		},
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update user in db", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := es.userRep.DeleteFromCache(ctx, token.UserId); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	es.log.InfoContext(ctx, "email verified successfully")
+
+	return nil
+}
+
+// RequestEmailChange stores the new email as pending: it is confirmed by the link sent to the new address,
+// the current address only gets a notice about the request.
+func (es *EmailService) RequestEmailChange(ctx context.Context, changeInfo *transfer.RequestEmailChangeInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, changeInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, newEmailLogKey, changeInfo.NewEmail)
+
+	es.log.InfoContext(ctx, "trying to request email change")
+
+	user, err := es.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: changeInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if err := checkPassword(ctx, es.hasher, user.PassHash, changeInfo.Password); err != nil {
+		return err
+	}
+	if user.Email == changeInfo.NewEmail {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("new email is equal to the current one", ctxerrors.ErrBadRequest))
+	}
+
+	usersCount, err := es.userRep.Count(ctx, repositoriestransfer.GetUsersCountInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: changeInfo.NewEmail,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t count users in db", err))
+	}
+	if usersCount > 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("email is already taken", ctxerrors.ErrConflict))
+	}
+
+	tx, err := es.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	rawToken, expiresAt, err := issueOneTimeToken(ctx, es.tokensRep, repositoriestransfer.CreateOneTimeTokenInfo{
+		UserId:  user.Id,
+		Purpose: repositoriestransfer.EmailChangePurpose,
+		Email:   changeInfo.NewEmail,
+	}, es.changeOpts.TokenTTL, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t issue email change token", err))
+	}
+
+	confirmEventId := uuid.New()
+	if err := createOutboxEvent(ctx, es.eventsRep, confirmEventId, amqpclient.EmailChangeRequestedEventKey, messages.EmailChangeRequestedMessage{
+		EventId:   confirmEventId,
+		UserId:    user.Id,
+		Email:     changeInfo.NewEmail,
+		Link:      es.changeOpts.LinkBase + rawToken,
+		ExpiresAt: expiresAt,
+	}, tx); err != nil {
+		return err
+	}
+
+	noticeEventId := uuid.New()
+	if err := createOutboxEvent(ctx, es.eventsRep, noticeEventId, amqpclient.EmailChangeNoticeEventKey, messages.EmailChangeNoticeMessage{
+		EventId:  noticeEventId,
+		UserId:   user.Id,
+		Email:    user.Email,
+		NewEmail: changeInfo.NewEmail,
+	}, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	es.log.InfoContext(ctx, "email change requested successfully")
+
+	return nil
+}
+
+// ConfirmEmailChange swaps the email to the pending one. The new address is verified by the confirmation itself,
+// the access tokens issued for the old email stop being accepted.
+func (es *EmailService) ConfirmEmailChange(ctx context.Context, confirmInfo *transfer.ConfirmEmailChangeInfo) (resErr error) {
+	es.log.InfoContext(ctx, "trying to confirm email change")
+
+	tx, err := es.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	token, err := spendOneTimeToken(ctx, es.tokensRep, confirmInfo.Token, repositoriestransfer.EmailChangePurpose, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t spend email change token", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, token.UserId)
+
+	if !token.Email.Valid {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("email change token has no pending email", ctxerrors.ErrInternalServerError))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, newEmailLogKey, token.Email.String)
+
+	if err := es.userRep.Update(ctx, repositoriestransfer.UpdateUserInfo{
+		Id: token.UserId,
+		UpdateInfo: map[string]interface{}{
+			"email":             token.Email.String,
+			"email_verified_at": time.Now(),
+		},
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update user in db", err))
+	}
+
+	// the issued access tokens carry the old email
+	if err := es.userRep.BumpTokenVersion(ctx, token.UserId, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t bump token version", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := es.userRep.DeleteFromCache(ctx, token.UserId); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	es.log.InfoContext(ctx, "email changed successfully")
+
+	return nil
+}
+
+// sendVerificationEmail issues a verification token and puts the link with it to the outbox.
+func sendVerificationEmail(
+	ctx context.Context,
+	tokensRep oneTimeTokensStore,
+	eventsRep dep.EventCreator,
+	opts EmailVerificationOptions,
+	userId uuid.UUID,
+	email string,
+	tx database.Transaction,
+) error {
+	rawToken, expiresAt, err := issueOneTimeToken(ctx, tokensRep, repositoriestransfer.CreateOneTimeTokenInfo{
+		UserId:  userId,
+		Purpose: repositoriestransfer.EmailVerificationPurpose,
+	}, opts.TokenTTL, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t issue verification token", err))
+	}
+
+	eventId := uuid.New()
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
+
+	return createOutboxEvent(ctx, eventsRep, eventId, amqpclient.EmailVerificationRequestedEventKey, messages.EmailVerificationRequestedMessage{
+		EventId:   eventId,
+		UserId:    userId,
+		Email:     email,
+		Link:      opts.LinkBase + rawToken,
+		ExpiresAt: expiresAt,
+	}, tx)
+}
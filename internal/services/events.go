@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	"github.com/google/uuid"
+)
+
+// createOutboxEvent stores the message in the outbox, EventChecker publishes it after the transaction is committed.
+func createOutboxEvent(
+	ctx context.Context,
+	eventsRep dep.EventCreator,
+	eventId uuid.UUID,
+	eventType string,
+	message any,
+	tx database.Transaction,
+) error {
+	messageJson, err := json.Marshal(message)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t marshal message", err))
+	}
+
+	if err := eventsRep.Create(ctx, repositoriestransfer.CreateEventInfo{
+		EventId:   eventId,
+		EventType: eventType,
+		Payload:   messageJson,
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create event", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// The fakes of this file apply the writes at once, a transaction only records how it was finished.
+
+type fakeTx struct {
+	database.Executor
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit() error {
+	if tx.committed || tx.rolledBack {
+		return sql.ErrTxDone
+	}
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	if tx.committed || tx.rolledBack {
+		return sql.ErrTxDone
+	}
+	tx.rolledBack = true
+	return nil
+}
+
+type fakeTxCreator struct {
+	txs []*fakeTx
+}
+
+func (c *fakeTxCreator) BeginTxCtx(context.Context, *sql.TxOptions) (database.Transaction, error) {
+	tx := &fakeTx{}
+	c.txs = append(c.txs, tx)
+	return tx, nil
+}
+
+func (c *fakeTxCreator) last() *fakeTx {
+	if len(c.txs) == 0 {
+		return nil
+	}
+	return c.txs[len(c.txs)-1]
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// matchesId reports whether a condition value, a single id or a list of them, contains the id.
+func matchesId(value any, id uuid.UUID) bool {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v == id
+	case []uuid.UUID:
+		for _, candidate := range v {
+			if candidate == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type fakeUsers struct {
+	authSvcUserStore
+	mu    sync.Mutex
+	users []*models.User
+	// txs is checked by DeleteFromCache, a user dropped from the cache before the commit may be cached again stale
+	txs          *fakeTxCreator
+	cacheDeletes []uuid.UUID
+}
+
+func (f *fakeUsers) User(_ context.Context, info repositoriestransfer.GetUserInfo, _ database.Transaction) (*models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, user := range f.users {
+		if id, ok := info.Condition[repositoriestransfer.UserIdCondition]; ok && !matchesId(id, user.Id) {
+			continue
+		}
+		if email, ok := info.Condition[repositoriestransfer.UserEmailCondition]; ok && !strings.EqualFold(email.(string), user.Email) {
+			continue
+		}
+		copied := *user
+		return &copied, nil
+	}
+
+	return nil, ctxerrors.ErrNotFound
+}
+
+func (f *fakeUsers) BumpTokenVersion(_ context.Context, userId uuid.UUID, _ database.Transaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, user := range f.users {
+		if user.Id == userId {
+			user.TokenVersion++
+			return nil
+		}
+	}
+
+	return ctxerrors.ErrNotFound
+}
+
+func (f *fakeUsers) DeleteFromCache(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.txs != nil {
+		if tx := f.txs.last(); tx != nil && !tx.committed {
+			return errors.New("user is deleted from cache before the commit")
+		}
+	}
+
+	f.cacheDeletes = append(f.cacheDeletes, id)
+	return nil
+}
+
+type fakeUserRoles struct {
+	roles map[uuid.UUID][]string
+}
+
+func (f *fakeUserRoles) UserRoles(_ context.Context, userId uuid.UUID, _ database.Transaction) ([]string, error) {
+	return f.roles[userId], nil
+}
+
+type fakeSessions struct {
+	sessionsStore
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*repositoriestransfer.CreateSessionInfo
+	revoked  map[uuid.UUID]bool
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{
+		sessions: map[uuid.UUID]*repositoriestransfer.CreateSessionInfo{},
+		revoked:  map[uuid.UUID]bool{},
+	}
+}
+
+func (f *fakeSessions) Create(_ context.Context, info repositoriestransfer.CreateSessionInfo, _ database.Transaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sessions[info.Id] = &info
+	return nil
+}
+
+func (f *fakeSessions) Revoke(_ context.Context, info repositoriestransfer.RevokeSessionsInfo, _ database.Transaction) ([]uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var revokedIds []uuid.UUID
+	for id, session := range f.sessions {
+		if f.revoked[id] || (info.ExceptId.Valid && info.ExceptId.UUID == id) {
+			continue
+		}
+		if value, ok := info.Condition[repositoriestransfer.SessionIdCondition]; ok && !matchesId(value, id) {
+			continue
+		}
+		if value, ok := info.Condition[repositoriestransfer.SessionUserIdCondition]; ok && !matchesId(value, session.UserId) {
+			continue
+		}
+		f.revoked[id] = true
+		revokedIds = append(revokedIds, id)
+	}
+
+	return revokedIds, nil
+}
+
+type fakeRefreshTokens struct {
+	mu     sync.Mutex
+	tokens []*models.RefreshToken
+}
+
+func (f *fakeRefreshTokens) Create(_ context.Context, info repositoriestransfer.CreateRefreshTokenInfo, _ database.Transaction) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token := models.NewRefreshTokenModel(info.UserId, info.FamilyId, info.TokenHash, info.ExpiresAt)
+	f.tokens = append(f.tokens, token)
+	return token.Id, nil
+}
+
+func (f *fakeRefreshTokens) Token(_ context.Context, info repositoriestransfer.GetRefreshTokenInfo, _ database.Transaction) (*models.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, token := range f.tokens {
+		if f.matches(token, info.Condition) {
+			copied := *token
+			return &copied, nil
+		}
+	}
+
+	return nil, ctxerrors.ErrNotFound
+}
+
+// Revoke revokes the not revoked tokens only, like the repository does.
+func (f *fakeRefreshTokens) Revoke(_ context.Context, info repositoriestransfer.RevokeRefreshTokensInfo, _ database.Transaction) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var revoked int64
+	for _, token := range f.tokens {
+		if token.IsRevoked() || !f.matches(token, info.Condition) {
+			continue
+		}
+		token.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		token.ReplacedBy = info.ReplacedBy
+		revoked++
+	}
+
+	return revoked, nil
+}
+
+func (f *fakeRefreshTokens) matches(token *models.RefreshToken, condition map[repositoriestransfer.RefreshTokenFieldTarget]any) bool {
+	for target, value := range condition {
+		switch target {
+		case repositoriestransfer.RefreshTokenIdCondition:
+			if !matchesId(value, token.Id) {
+				return false
+			}
+		case repositoriestransfer.RefreshTokenFamilyCondition:
+			if !matchesId(value, token.FamilyId) {
+				return false
+			}
+		case repositoriestransfer.RefreshTokenUserCondition:
+			if !matchesId(value, token.UserId) {
+				return false
+			}
+		case repositoriestransfer.RefreshTokenHashCondition:
+			if string(value.([]byte)) != string(token.TokenHash) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *fakeRefreshTokens) byFamily(familyId uuid.UUID) []*models.RefreshToken {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var tokens []*models.RefreshToken
+	for _, token := range f.tokens {
+		if token.FamilyId == familyId {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
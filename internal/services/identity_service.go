@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	oidcclient "github.com/KBcHMFollower/blog_user_service/internal/clients/oidc"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+)
+
+const (
+	identityProviderLogKey = "identity-provider"
+)
+
+type identitiesStore interface {
+	dep.ExternalIdentityCreator
+	dep.ExternalIdentityGetter
+	dep.ExternalIdentityDeleter
+}
+
+type IdentityService struct {
+	userRep       dep.UserGetter
+	identitiesRep identitiesStore
+	verifier      dep.ExternalTokenVerifier
+	log           logger.Logger
+}
+
+func NewIdentityService(
+	userRep dep.UserGetter,
+	identitiesRep identitiesStore,
+	verifier dep.ExternalTokenVerifier,
+	log logger.Logger,
+) *IdentityService {
+	return &IdentityService{
+		userRep:       userRep,
+		identitiesRep: identitiesRep,
+		verifier:      verifier,
+		log:           log,
+	}
+}
+
+// LinkIdentity links the account of the id token to the user, linking it again is a no-op.
+func (is *IdentityService) LinkIdentity(ctx context.Context, linkInfo *transfer.LinkIdentityInfo) error {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, linkInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, identityProviderLogKey, linkInfo.Provider)
+
+	is.log.InfoContext(ctx, "trying to link identity")
+
+	identity, err := verifyExternalToken(ctx, is.verifier, linkInfo.Provider, linkInfo.IdToken)
+	if err != nil {
+		return err
+	}
+
+	if _, err := is.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: linkInfo.UserId,
+		},
+	}, nil); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	linked, err := is.identitiesRep.Identity(ctx, repositoriestransfer.GetExternalIdentityInfo{
+		Condition: map[repositoriestransfer.ExternalIdentityFieldTarget]any{
+			repositoriestransfer.ExternalIdentityProviderCondition: identity.Provider,
+			repositoriestransfer.ExternalIdentitySubjectCondition:  identity.Subject,
+		},
+	}, nil)
+	switch {
+	case err == nil && linked.UserId == linkInfo.UserId:
+		is.log.InfoContext(ctx, "identity is already linked")
+		return nil
+	case err == nil:
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("identity is linked to another user", ctxerrors.ErrConflict))
+	case !errors.Is(err, ctxerrors.ErrNotFound):
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get external identity from db", err))
+	}
+
+	// the user may have another identity of the provider, it is a conflict too
+	if _, err := is.identitiesRep.Create(ctx, repositoriestransfer.CreateExternalIdentityInfo{
+		UserId:   linkInfo.UserId,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}, nil); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create external identity in db", err))
+	}
+
+	is.log.InfoContext(ctx, "identity linked successfully")
+
+	return nil
+}
+
+func (is *IdentityService) UnlinkIdentity(ctx context.Context, unlinkInfo *transfer.UnlinkIdentityInfo) error {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, unlinkInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, identityProviderLogKey, unlinkInfo.Provider)
+
+	is.log.InfoContext(ctx, "trying to unlink identity")
+
+	deleted, err := is.identitiesRep.Delete(ctx, repositoriestransfer.DeleteExternalIdentitiesInfo{
+		Condition: map[repositoriestransfer.ExternalIdentityFieldTarget]any{
+			repositoriestransfer.ExternalIdentityUserIdCondition:   unlinkInfo.UserId,
+			repositoriestransfer.ExternalIdentityProviderCondition: unlinkInfo.Provider,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete external identity from db", err))
+	}
+	if deleted == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("identity is not linked", ctxerrors.ErrNotFound))
+	}
+
+	is.log.InfoContext(ctx, "identity unlinked successfully")
+
+	return nil
+}
+
+// verifyExternalToken reports unknown providers and invalid tokens as client errors,
+// an unavailable provider stays an internal error.
+func verifyExternalToken(ctx context.Context, verifier dep.ExternalTokenVerifier, provider string, rawToken string) (*oidcclient.Identity, error) {
+	identity, err := verifier.Verify(ctx, provider, rawToken)
+	switch {
+	case err == nil:
+		return identity, nil
+	case errors.Is(err, oidcclient.ErrUnknownProvider):
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(err.Error(), ctxerrors.ErrBadRequest))
+	case errors.Is(err, oidcclient.ErrInvalidToken):
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(err.Error(), ctxerrors.ErrUnauthorized))
+	default:
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t verify external token", err))
+	}
+}
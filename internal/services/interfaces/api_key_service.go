@@ -0,0 +1,12 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type ApiKeyService interface {
+	CreateApiKey(ctx context.Context, createInfo *transfer.CreateApiKeyInfo) (*transfer.CreateApiKeyResult, error)
+	ListApiKeys(ctx context.Context, listInfo *transfer.ListApiKeysInfo) (*transfer.ListApiKeysResult, error)
+	RevokeApiKey(ctx context.Context, revokeInfo *transfer.RevokeApiKeyInfo) error
+}
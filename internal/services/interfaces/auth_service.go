@@ -8,5 +8,17 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, req *transfer.RegisterInfo) (resToken *transfer.TokenResult, resErr error)
 	Login(ctx context.Context, loginInfo *transfer.LoginInfo) (*transfer.TokenResult, error)
-	CheckAuth(ctx context.Context, authInfo *transfer.CheckAuthInfo) (*transfer.TokenResult, error)
+	CheckAuth(ctx context.Context, authInfo *transfer.CheckAuthInfo) (*transfer.CheckAuthResult, error)
+	RefreshToken(ctx context.Context, refreshInfo *transfer.RefreshTokenInfo) (*transfer.TokenResult, error)
+	Logout(ctx context.Context, logoutInfo *transfer.LogoutInfo) error
+	RevokeToken(ctx context.Context, revokeInfo *transfer.RevokeTokenInfo) error
+	RevokeUserTokens(ctx context.Context, revokeInfo *transfer.RevokeUserTokensInfo) error
+	GetJWKS(ctx context.Context) (*transfer.JWKSResult, error)
+	UnlockAccount(ctx context.Context, unlockInfo *transfer.UnlockAccountInfo) error
+	VerifyMfa(ctx context.Context, verifyInfo *transfer.VerifyMfaInfo) (*transfer.TokenResult, error)
+	RequestMagicLink(ctx context.Context, requestInfo *transfer.RequestMagicLinkInfo) error
+	RedeemMagicLink(ctx context.Context, redeemInfo *transfer.RedeemMagicLinkInfo) (*transfer.TokenResult, error)
+	ExchangeExternalToken(ctx context.Context, exchangeInfo *transfer.ExchangeExternalTokenInfo) (*transfer.TokenResult, error)
+	Impersonate(ctx context.Context, impersonateInfo *transfer.ImpersonateInfo) (*transfer.ImpersonationResult, error)
+	StopImpersonation(ctx context.Context, stopInfo *transfer.StopImpersonationInfo) error
 }
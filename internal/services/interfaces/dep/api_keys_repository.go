@@ -0,0 +1,25 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type ApiKeyCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateApiKeyInfo, tx database.Transaction) (*models.ApiKey, error)
+}
+
+type ApiKeysGetter interface {
+	ApiKeys(ctx context.Context, info repositoriestransfer.GetApiKeysInfo, tx database.Transaction) ([]*models.ApiKey, error)
+}
+
+type ApiKeyRevoker interface {
+	Revoke(ctx context.Context, info repositoriestransfer.RevokeApiKeysInfo, tx database.Transaction) (int64, error)
+}
+
+type ApiKeyToucher interface {
+	Touch(ctx context.Context, keyId uuid.UUID) error
+}
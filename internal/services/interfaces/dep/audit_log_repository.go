@@ -0,0 +1,12 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+)
+
+type AuditEntryCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateAuditEntryInfo, tx database.Transaction) (*models.AuditEntry, error)
+}
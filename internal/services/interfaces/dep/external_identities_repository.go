@@ -0,0 +1,25 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	oidcclient "github.com/KBcHMFollower/blog_user_service/internal/clients/oidc"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+)
+
+type ExternalIdentityCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateExternalIdentityInfo, tx database.Transaction) (*models.ExternalIdentity, error)
+}
+
+type ExternalIdentityGetter interface {
+	Identity(ctx context.Context, info repositoriestransfer.GetExternalIdentityInfo, tx database.Transaction) (*models.ExternalIdentity, error)
+}
+
+type ExternalIdentityDeleter interface {
+	Delete(ctx context.Context, info repositoriestransfer.DeleteExternalIdentitiesInfo, tx database.Transaction) (int64, error)
+}
+
+type ExternalTokenVerifier interface {
+	Verify(ctx context.Context, provider string, rawToken string) (*oidcclient.Identity, error)
+}
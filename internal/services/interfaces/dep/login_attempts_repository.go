@@ -0,0 +1,16 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+)
+
+type LoginFailuresCounter interface {
+	RegisterFailure(ctx context.Context, info repositoriestransfer.RegisterLoginFailureInfo) (int64, error)
+	Reset(ctx context.Context, key repositoriestransfer.LoginAttemptsKey) error
+}
+
+type LoginLocker interface {
+	Lock(ctx context.Context, info repositoriestransfer.LockLoginInfo) error
+	IsLocked(ctx context.Context, key repositoriestransfer.LoginAttemptsKey) (bool, error)
+}
@@ -0,0 +1,21 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type OneTimeTokenCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateOneTimeTokenInfo, tx database.Transaction) (uuid.UUID, error)
+}
+
+type OneTimeTokenGetter interface {
+	Token(ctx context.Context, info repositoriestransfer.GetOneTimeTokenInfo, tx database.Transaction) (*models.OneTimeToken, error)
+}
+
+type OneTimeTokenConsumer interface {
+	Use(ctx context.Context, info repositoriestransfer.UseOneTimeTokensInfo, tx database.Transaction) (int64, error)
+}
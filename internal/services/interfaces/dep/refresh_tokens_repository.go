@@ -0,0 +1,21 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateRefreshTokenInfo, tx database.Transaction) (uuid.UUID, error)
+}
+
+type RefreshTokenGetter interface {
+	Token(ctx context.Context, info repositoriestransfer.GetRefreshTokenInfo, tx database.Transaction) (*models.RefreshToken, error)
+}
+
+type RefreshTokenRevoker interface {
+	Revoke(ctx context.Context, info repositoriestransfer.RevokeRefreshTokensInfo, tx database.Transaction) (int64, error)
+}
@@ -0,0 +1,25 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/google/uuid"
+)
+
+type RoleExistenceChecker interface {
+	RoleExists(ctx context.Context, role string, tx database.Transaction) (bool, error)
+}
+
+type UserRolesGetter interface {
+	UserRoles(ctx context.Context, userId uuid.UUID, tx database.Transaction) ([]string, error)
+}
+
+type UserRolesManager interface {
+	Grant(ctx context.Context, info repositoriestransfer.UserRoleInfo, tx database.Transaction) (bool, error)
+	Revoke(ctx context.Context, info repositoriestransfer.UserRoleInfo, tx database.Transaction) (bool, error)
+}
+
+type RolePermissionsGetter interface {
+	RolesPermissions(ctx context.Context, roles []string) ([]string, error)
+}
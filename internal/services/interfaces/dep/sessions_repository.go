@@ -0,0 +1,26 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type SessionCreator interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateSessionInfo, tx database.Transaction) error
+}
+
+type SessionsGetter interface {
+	Sessions(ctx context.Context, info repositoriestransfer.GetSessionsInfo, tx database.Transaction) ([]*models.Session, error)
+}
+
+type SessionRevoker interface {
+	Revoke(ctx context.Context, info repositoriestransfer.RevokeSessionsInfo, tx database.Transaction) ([]uuid.UUID, error)
+}
+
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionId uuid.UUID) (bool, error)
+	Touch(ctx context.Context, sessionId uuid.UUID) error
+}
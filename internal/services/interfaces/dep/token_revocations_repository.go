@@ -0,0 +1,21 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, info repositoriestransfer.RevokeTokenInfo, tx database.Transaction) error
+	RevokeUserTokens(ctx context.Context, info repositoriestransfer.RevokeUserTokensInfo, tx database.Transaction) error
+	SetRevokedTokenToCache(ctx context.Context, jti uuid.UUID) error
+	SetRevokedUserTokensToCache(ctx context.Context, info repositoriestransfer.RevokeUserTokensInfo) error
+}
+
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, error)
+	UserTokensRevokedBefore(ctx context.Context, userId uuid.UUID) (time.Time, error)
+}
@@ -0,0 +1,30 @@
+package services_dep_interfaces
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+type TotpGetter interface {
+	Totp(ctx context.Context, userId uuid.UUID, tx database.Transaction) (*models.UserTotp, error)
+}
+
+type TotpManager interface {
+	SaveSecret(ctx context.Context, info repositoriestransfer.SaveTotpSecretInfo, tx database.Transaction) (int64, error)
+	Confirm(ctx context.Context, userId uuid.UUID, tx database.Transaction) error
+	UseStep(ctx context.Context, info repositoriestransfer.UseTotpStepInfo, tx database.Transaction) (int64, error)
+	Delete(ctx context.Context, userId uuid.UUID, tx database.Transaction) error
+}
+
+type RecoveryCodesManager interface {
+	ReplaceRecoveryCodes(ctx context.Context, info repositoriestransfer.ReplaceRecoveryCodesInfo, tx database.Transaction) error
+	UseRecoveryCode(ctx context.Context, info repositoriestransfer.UseRecoveryCodeInfo, tx database.Transaction) (int64, error)
+}
+
+type SecretCipher interface {
+	Encrypt(plain []byte) ([]byte, error)
+	Decrypt(encrypted []byte) ([]byte, error)
+}
@@ -19,6 +19,22 @@ type UserUpdater interface {
 	Update(ctx context.Context, updateData repositoriestransfer.UpdateUserInfo, tx database.Transaction) error
 }
 
+// UserPasswordUpdater sets a new password hash and bumps the user token version,
+// so every token issued before the change stops being accepted.
+type UserPasswordUpdater interface {
+	UpdatePassword(ctx context.Context, info repositoriestransfer.UpdatePasswordInfo, tx database.Transaction) error
+}
+
+// UserPasswordRehasher replaces the hash of an unchanged password, e.g. to upgrade the hashing algorithm.
+type UserPasswordRehasher interface {
+	ReplacePasswordHash(ctx context.Context, info repositoriestransfer.UpdatePasswordInfo, tx database.Transaction) error
+}
+
+// UserTokenVersionBumper invalidates the issued access tokens of the user, e.g. when the claims are outdated.
+type UserTokenVersionBumper interface {
+	BumpTokenVersion(ctx context.Context, userId uuid.UUID, tx database.Transaction) error
+}
+
 type UserCreator interface {
 	Create(ctx context.Context, createDto *repositoriestransfer.CreateUserInfo, tx database.Transaction) (uuid.UUID, error)
 	SetToCache(ctx context.Context, user *models.User) error
@@ -29,3 +45,28 @@ type UserDeleter interface {
 	Delete(ctx context.Context, delInfo repositoriestransfer.DeleteUserInfo, tx database.Transaction) error
 	DeleteFromCache(ctx context.Context, id uuid.UUID) error
 }
+
+// UsersBatchCache reads and writes the cached users in one round trip.
+type UsersBatchCache interface {
+	TryGetManyFromCache(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error)
+	SetManyToCache(ctx context.Context, users []*models.User) error
+}
+
+// UsernameGetter compares usernames case-insensitively.
+type UsernameGetter interface {
+	UserByUsername(ctx context.Context, username string, tx database.Transaction) (*models.User, error)
+	ExistingUsernames(ctx context.Context, usernames []string, tx database.Transaction) ([]string, error)
+}
+
+type UsernameCache interface {
+	TryGetIdByUsernameFromCache(ctx context.Context, username string) (uuid.UUID, error)
+	SetUsernameToCache(ctx context.Context, username string, id uuid.UUID) error
+	DeleteUsernameFromCache(ctx context.Context, username string) error
+}
+
+type UsernameHistoryManager interface {
+	Create(ctx context.Context, info repositoriestransfer.CreateUsernameHistoryInfo, tx database.Transaction) error
+	Entry(ctx context.Context, username string, tx database.Transaction) (*models.UsernameHistoryEntry, error)
+	HeldUsernames(ctx context.Context, usernames []string, tx database.Transaction) ([]string, error)
+	Delete(ctx context.Context, username string, tx database.Transaction) error
+}
@@ -0,0 +1,13 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type EmailService interface {
+	SendVerificationEmail(ctx context.Context, sendInfo *transfer.SendVerificationEmailInfo) error
+	VerifyEmail(ctx context.Context, verifyInfo *transfer.VerifyEmailInfo) error
+	RequestEmailChange(ctx context.Context, changeInfo *transfer.RequestEmailChangeInfo) error
+	ConfirmEmailChange(ctx context.Context, confirmInfo *transfer.ConfirmEmailChangeInfo) error
+}
@@ -0,0 +1,11 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type IdentityService interface {
+	LinkIdentity(ctx context.Context, linkInfo *transfer.LinkIdentityInfo) error
+	UnlinkIdentity(ctx context.Context, unlinkInfo *transfer.UnlinkIdentityInfo) error
+}
@@ -0,0 +1,12 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type PasswordService interface {
+	RequestPasswordReset(ctx context.Context, requestInfo *transfer.RequestPasswordResetInfo) error
+	ConfirmPasswordReset(ctx context.Context, confirmInfo *transfer.ConfirmPasswordResetInfo) error
+	ChangePassword(ctx context.Context, changeInfo *transfer.ChangePasswordInfo) error
+}
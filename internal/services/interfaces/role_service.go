@@ -0,0 +1,11 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type RoleService interface {
+	GrantRole(ctx context.Context, grantInfo *transfer.GrantRoleInfo) error
+	RevokeRole(ctx context.Context, revokeInfo *transfer.RevokeRoleInfo) error
+}
@@ -0,0 +1,12 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type SessionService interface {
+	ListSessions(ctx context.Context, listInfo *transfer.ListSessionsInfo) (*transfer.ListSessionsResult, error)
+	RevokeSession(ctx context.Context, revokeInfo *transfer.RevokeSessionInfo) error
+	RevokeAllOtherSessions(ctx context.Context, revokeInfo *transfer.RevokeOtherSessionsInfo) (int64, error)
+}
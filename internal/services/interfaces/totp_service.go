@@ -0,0 +1,12 @@
+package services_interfaces
+
+import (
+	"context"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+)
+
+type TotpService interface {
+	EnrollTotp(ctx context.Context, enrollInfo *transfer.EnrollTotpInfo) (*transfer.EnrollTotpResult, error)
+	ConfirmTotp(ctx context.Context, confirmInfo *transfer.ConfirmTotpInfo) (*transfer.ConfirmTotpResult, error)
+	DisableTotp(ctx context.Context, disableInfo *transfer.DisableTotpInfo) error
+}
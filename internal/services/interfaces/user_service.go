@@ -8,8 +8,11 @@ import (
 
 type UserService interface {
 	GetUserById(ctx context.Context, userId uuid.UUID) (*transfer.GetUserResult, error)
+	GetUsers(ctx context.Context, getInfo *transfer.GetUsersInfo) (*transfer.GetUsersResult, error)
 	UpdateUser(ctx context.Context, updateInfo *transfer.UpdateUserInfo) (*transfer.UpdateUserResult, error)
 	DeleteUser(ctx context.Context, deleteInfo *transfer.DeleteUserInfo) error
 	UploadAvatar(ctx context.Context, uploadInfo *transfer.UploadAvatarInfo) (*transfer.AvatarResult, error)
 	CompensateDeletedUser(ctx context.Context, eventId uuid.UUID) error
+	GetUserByUsername(ctx context.Context, info *transfer.GetUserByUsernameInfo) (*transfer.GetUserByUsernameResult, error)
+	CheckUsernameAvailable(ctx context.Context, info *transfer.CheckUsernameInfo) (*transfer.UsernameAvailabilityResult, error)
 }
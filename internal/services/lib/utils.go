@@ -1,6 +1,7 @@
 package services_utils
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/KBcHMFollower/blog_user_service/internal/database"
@@ -11,7 +12,8 @@ func HandleErrInTransaction(err error, tx database.Transaction) error {
 		return nil
 	}
 
-	if txErr := tx.Rollback(); txErr != nil {
+	// a transaction committed on purpose before returning the error is not rolled back
+	if txErr := tx.Rollback(); txErr != nil && !errors.Is(txErr, sql.ErrTxDone) {
 		return errors.Join(err, fmt.Errorf("error rolling back transaction: %v", txErr))
 	}
 
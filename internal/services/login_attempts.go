@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// LockoutOptions configure the login brute-force protection. Failures are counted within Window,
+// reaching the limit locks the email (or the client ip) for Duration. Every failure is answered
+// with a delay growing from BaseDelay twice per failure up to MaxDelay. Wrong second factor codes
+// count as failed logins too, an mfa challenge is burned after MaxMfaAttempts of them. Zero
+// MaxIpAttempts turns the client ip limit off.
+type LockoutOptions struct {
+	MaxEmailAttempts int64
+	MaxIpAttempts    int64
+	MaxMfaAttempts   int64
+	Window           time.Duration
+	Duration         time.Duration
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+}
+
+type loginAttemptsStore interface {
+	dep.LoginFailuresCounter
+	dep.LoginLocker
+}
+
+func emailAttemptsKey(email string) repositoriestransfer.LoginAttemptsKey {
+	return repositoriestransfer.LoginAttemptsKey{
+		Target: repositoriestransfer.EmailAttemptsTarget,
+		Value:  strings.ToLower(email),
+	}
+}
+
+// loginAttemptsKeys returns the keys of the login, the ip is skipped when it is unknown or its limit is off.
+func loginAttemptsKeys(opts LockoutOptions, email string, clientIp string) []repositoriestransfer.LoginAttemptsKey {
+	keys := []repositoriestransfer.LoginAttemptsKey{emailAttemptsKey(email)}
+	if clientIp != "" && opts.MaxIpAttempts > 0 {
+		keys = append(keys, repositoriestransfer.LoginAttemptsKey{
+			Target: repositoriestransfer.IpAttemptsTarget,
+			Value:  clientIp,
+		})
+	}
+
+	return keys
+}
+
+func mfaChallengeAttemptsKey(challengeId uuid.UUID) repositoriestransfer.LoginAttemptsKey {
+	return repositoriestransfer.LoginAttemptsKey{
+		Target: repositoriestransfer.MfaChallengeAttemptsTarget,
+		Value:  challengeId.String(),
+	}
+}
+
+func checkLoginLocks(ctx context.Context, attemptsRep loginAttemptsStore, keys []repositoriestransfer.LoginAttemptsKey) error {
+	for _, key := range keys {
+		locked, err := attemptsRep.IsLocked(ctx, key)
+		if err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check login lock", err))
+		}
+		if locked {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("login is locked for "+string(key.Target), ctxerrors.ErrTooManyRequests))
+		}
+	}
+
+	return nil
+}
+
+// registerLoginFailure counts the failure for every key, locks the keys that reached their limit
+// and waits for the progressive delay of the key with the most failures.
+func registerLoginFailure(
+	ctx context.Context,
+	attemptsRep loginAttemptsStore,
+	opts LockoutOptions,
+	keys []repositoriestransfer.LoginAttemptsKey,
+) error {
+	delay, err := countLoginFailure(ctx, attemptsRep, opts, keys)
+	if err != nil {
+		return err
+	}
+
+	return waitLoginDelay(ctx, delay)
+}
+
+// countLoginFailure is registerLoginFailure without the wait, it returns the delay the failure
+// has to be answered with.
+func countLoginFailure(
+	ctx context.Context,
+	attemptsRep loginAttemptsStore,
+	opts LockoutOptions,
+	keys []repositoriestransfer.LoginAttemptsKey,
+) (time.Duration, error) {
+	var maxFailures int64
+
+	for _, key := range keys {
+		failures, err := attemptsRep.RegisterFailure(ctx, repositoriestransfer.RegisterLoginFailureInfo{
+			Key:    key,
+			Window: opts.Window,
+		})
+		if err != nil {
+			return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t register login failure", err))
+		}
+
+		if failures >= attemptsLimit(opts, key.Target) {
+			if err := attemptsRep.Lock(ctx, repositoriestransfer.LockLoginInfo{
+				Key:      key,
+				Duration: opts.Duration,
+			}); err != nil {
+				return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t lock login", err))
+			}
+		}
+
+		maxFailures = max(maxFailures, failures)
+	}
+
+	return loginDelay(opts, maxFailures), nil
+}
+
+// failAttempt registers the failure of the keys and returns the error of the attempt,
+// a failure that can't be registered is logged only.
+func failAttempt(
+	ctx context.Context,
+	log logger.Logger,
+	attemptsRep loginAttemptsStore,
+	opts LockoutOptions,
+	keys []repositoriestransfer.LoginAttemptsKey,
+	attemptErr error,
+) error {
+	if err := registerLoginFailure(ctx, attemptsRep, opts, keys); err != nil {
+		log.ErrorContext(ctxerrors.ErrorCtx(ctx, err), "can`t register login failure", logger.ErrKey, err.Error())
+	}
+
+	return attemptErr
+}
+
+func attemptsLimit(opts LockoutOptions, target repositoriestransfer.LoginAttemptsTarget) int64 {
+	switch target {
+	case repositoriestransfer.IpAttemptsTarget:
+		return opts.MaxIpAttempts
+	case repositoriestransfer.MfaChallengeAttemptsTarget:
+		return opts.MaxMfaAttempts
+	default:
+		return opts.MaxEmailAttempts
+	}
+}
+
+func loginDelay(opts LockoutOptions, failures int64) time.Duration {
+	if failures <= 0 || opts.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := opts.BaseDelay
+	for i := int64(1); i < failures && delay < opts.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, opts.MaxDelay)
+}
+
+func waitLoginDelay(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("login delay interrupted", ctx.Err()))
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/cache/memory"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	"github.com/KBcHMFollower/blog_user_service/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct-password"
+
+func TestLoginDelay(t *testing.T) {
+	opts := LockoutOptions{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name     string
+		opts     LockoutOptions
+		failures int64
+		delay    time.Duration
+	}{
+		{name: "no failures", opts: opts, failures: 0, delay: 0},
+		{name: "negative failures", opts: opts, failures: -1, delay: 0},
+		{name: "first failure", opts: opts, failures: 1, delay: 100 * time.Millisecond},
+		{name: "second failure", opts: opts, failures: 2, delay: 200 * time.Millisecond},
+		{name: "fourth failure", opts: opts, failures: 4, delay: 800 * time.Millisecond},
+		{name: "capped", opts: opts, failures: 5, delay: time.Second},
+		{name: "capped far above", opts: opts, failures: 1000, delay: time.Second},
+		{name: "delay off", opts: LockoutOptions{MaxDelay: time.Second}, failures: 3, delay: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if delay := loginDelay(tt.opts, tt.failures); delay != tt.delay {
+				t.Errorf("loginDelay(%d) = %v, want %v", tt.failures, delay, tt.delay)
+			}
+		})
+	}
+}
+
+func TestAttemptsLimit(t *testing.T) {
+	opts := LockoutOptions{MaxEmailAttempts: 5, MaxIpAttempts: 20, MaxMfaAttempts: 3}
+
+	tests := []struct {
+		target repositoriestransfer.LoginAttemptsTarget
+		limit  int64
+	}{
+		{target: repositoriestransfer.EmailAttemptsTarget, limit: 5},
+		{target: repositoriestransfer.IpAttemptsTarget, limit: 20},
+		{target: repositoriestransfer.MfaChallengeAttemptsTarget, limit: 3},
+	}
+
+	for _, tt := range tests {
+		if limit := attemptsLimit(opts, tt.target); limit != tt.limit {
+			t.Errorf("attemptsLimit(%s) = %d, want %d", tt.target, limit, tt.limit)
+		}
+	}
+}
+
+func TestLoginAttemptsKeys(t *testing.T) {
+	emailKey := repositoriestransfer.LoginAttemptsKey{Target: repositoriestransfer.EmailAttemptsTarget, Value: "user@example.com"}
+	ipKey := repositoriestransfer.LoginAttemptsKey{Target: repositoriestransfer.IpAttemptsTarget, Value: "10.0.0.1"}
+
+	tests := []struct {
+		name     string
+		maxIp    int64
+		email    string
+		clientIp string
+		keys     []repositoriestransfer.LoginAttemptsKey
+	}{
+		{name: "email and ip", maxIp: 10, email: "user@example.com", clientIp: "10.0.0.1", keys: []repositoriestransfer.LoginAttemptsKey{emailKey, ipKey}},
+		{name: "email is lowercased", maxIp: 10, email: "User@Example.com", clientIp: "10.0.0.1", keys: []repositoriestransfer.LoginAttemptsKey{emailKey, ipKey}},
+		{name: "ip limit off", maxIp: 0, email: "user@example.com", clientIp: "10.0.0.1", keys: []repositoriestransfer.LoginAttemptsKey{emailKey}},
+		{name: "unknown ip", maxIp: 10, email: "user@example.com", clientIp: "", keys: []repositoriestransfer.LoginAttemptsKey{emailKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := loginAttemptsKeys(LockoutOptions{MaxIpAttempts: tt.maxIp}, tt.email, tt.clientIp)
+			if len(keys) != len(tt.keys) {
+				t.Fatalf("loginAttemptsKeys = %v, want %v", keys, tt.keys)
+			}
+			for i := range keys {
+				if keys[i] != tt.keys[i] {
+					t.Errorf("loginAttemptsKeys[%d] = %v, want %v", i, keys[i], tt.keys[i])
+				}
+			}
+		})
+	}
+}
+
+type fakeTotp struct {
+	totpStore
+	confirmed map[uuid.UUID]bool
+}
+
+func (f *fakeTotp) Totp(_ context.Context, userId uuid.UUID, _ database.Transaction) (*models.UserTotp, error) {
+	if !f.confirmed[userId] {
+		return nil, ctxerrors.ErrNotFound
+	}
+	return &models.UserTotp{UserId: userId, ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil
+}
+
+type fakeOneTimeTokens struct {
+	oneTimeTokensStore
+}
+
+func (f *fakeOneTimeTokens) Use(context.Context, repositoriestransfer.UseOneTimeTokensInfo, database.Transaction) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOneTimeTokens) Create(context.Context, repositoriestransfer.CreateOneTimeTokenInfo, database.Transaction) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+type lockoutTest struct {
+	svc      *AuthService
+	user     *models.User
+	mfaUser  *models.User
+	attempts loginAttemptsStore
+}
+
+// newLockoutTest returns an AuthService with the login attempts kept in memory, its delay is off.
+func newLockoutTest(t *testing.T, opts LockoutOptions) *lockoutTest {
+	t.Helper()
+
+	hasher := passwordshelper.NewBcryptHasher(bcrypt.MinCost)
+	passHash, err := hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatalf("can`t hash password: %v", err)
+	}
+
+	user := models.NewUserModel("user@example.com", "First", "Last", passHash)
+	mfaUser := models.NewUserModel("mfa@example.com", "First", "Last", passHash)
+
+	attemptsCache := memory.NewMemoryCache(0)
+	attemptsFallback := memory.NewMemoryCache(0)
+	t.Cleanup(func() {
+		_ = attemptsCache.Stop()
+		_ = attemptsFallback.Stop()
+	})
+	attempts := repository.NewLoginAttemptsRepository(attemptsCache, attemptsFallback)
+
+	return &lockoutTest{
+		svc: &AuthService{
+			userRep:     &fakeUsers{users: []*models.User{user, mfaUser}},
+			refreshRep:  &fakeRefreshTokens{},
+			sessionsRep: newFakeSessions(),
+			rolesRep:    &fakeUserRoles{},
+			tokensRep:   &fakeOneTimeTokens{},
+			totpRep:     &fakeTotp{confirmed: map[uuid.UUID]bool{mfaUser.Id: true}},
+			attemptsRep: attempts,
+			log:         testLogger(),
+			hasher:      hasher,
+			jwtOpts: tokenshelper.JwtOptions{
+				Keys:     tokenshelper.NewHmacKeySet("test-secret"),
+				Issuer:   "test",
+				Audience: "test",
+				TTL:      time.Minute,
+			},
+			refreshTokenTtl: time.Hour,
+			totpOpts:        TotpOptions{ChallengeTTL: time.Minute},
+			lockoutOpts:     opts,
+			txCreator:       &fakeTxCreator{},
+		},
+		user:     user,
+		mfaUser:  mfaUser,
+		attempts: attempts,
+	}
+}
+
+func (lt *lockoutTest) login(email string, password string, ip string) (*transfer.TokenResult, error) {
+	return lt.svc.Login(context.Background(), &transfer.LoginInfo{
+		Email:    email,
+		Password: password,
+		Client:   transfer.ClientInfo{IpAddress: ip},
+	})
+}
+
+// failLogins sends n logins with a wrong password, each of them must be rejected as invalid.
+func (lt *lockoutTest) failLogins(t *testing.T, email string, ip string, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		if _, err := lt.login(email, "wrong-password", ip); !errors.Is(err, ctxerrors.ErrBadRequest) {
+			t.Fatalf("Login with a wrong password err = %v, want ErrBadRequest", err)
+		}
+	}
+}
+
+func TestLoginLocksEmailAtThreshold(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 3, Window: time.Minute, Duration: time.Minute})
+
+	lt.failLogins(t, lt.user.Email, "", 2)
+	if _, err := lt.login(lt.user.Email, testPassword, ""); err != nil {
+		t.Fatalf("Login below the threshold: %v", err)
+	}
+
+	lt.failLogins(t, lt.user.Email, "", 3)
+	if _, err := lt.login(lt.user.Email, testPassword, ""); !errors.Is(err, ctxerrors.ErrTooManyRequests) {
+		t.Fatalf("Login of a locked email err = %v, want ErrTooManyRequests", err)
+	}
+	if _, err := lt.login("USER@example.com", testPassword, ""); !errors.Is(err, ctxerrors.ErrTooManyRequests) {
+		t.Fatalf("Login of a locked email in another case err = %v, want ErrTooManyRequests", err)
+	}
+	if _, err := lt.login(lt.mfaUser.Email, testPassword, ""); err != nil {
+		t.Fatalf("Login of another email: %v", err)
+	}
+}
+
+func TestLoginLocksIp(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 10, MaxIpAttempts: 3, Window: time.Minute, Duration: time.Minute})
+
+	lt.failLogins(t, "unknown-1@example.com", "10.0.0.1", 2)
+	lt.failLogins(t, "unknown-2@example.com", "10.0.0.1", 1)
+
+	if _, err := lt.login(lt.user.Email, testPassword, "10.0.0.1"); !errors.Is(err, ctxerrors.ErrTooManyRequests) {
+		t.Fatalf("Login from a locked ip err = %v, want ErrTooManyRequests", err)
+	}
+	if _, err := lt.login(lt.user.Email, testPassword, "10.0.0.2"); err != nil {
+		t.Fatalf("Login from another ip: %v", err)
+	}
+}
+
+func TestLoginIpLimitOff(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 3, Window: time.Minute, Duration: time.Minute})
+
+	for i := 0; i < 5; i++ {
+		lt.failLogins(t, uuid.NewString()+"@example.com", "10.0.0.1", 2)
+	}
+
+	if _, err := lt.login(lt.user.Email, testPassword, "10.0.0.1"); err != nil {
+		t.Fatalf("Login with the ip limit off: %v", err)
+	}
+}
+
+func TestLoginFailuresWindowExpires(t *testing.T) {
+	window := 50 * time.Millisecond
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 3, Window: window, Duration: time.Minute})
+
+	lt.failLogins(t, lt.user.Email, "", 2)
+	time.Sleep(2 * window)
+	lt.failLogins(t, lt.user.Email, "", 2)
+
+	if _, err := lt.login(lt.user.Email, testPassword, ""); err != nil {
+		t.Fatalf("Login after the window expired: %v", err)
+	}
+}
+
+func TestLoginResetsFailuresOnSuccess(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 3, Window: time.Minute, Duration: time.Minute})
+
+	lt.failLogins(t, lt.user.Email, "", 2)
+	tokens, err := lt.login(lt.user.Email, testPassword, "")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("Login tokens = %+v, want access and refresh tokens", tokens)
+	}
+
+	lt.failLogins(t, lt.user.Email, "", 2)
+	if _, err := lt.login(lt.user.Email, testPassword, ""); err != nil {
+		t.Fatalf("Login after the reset: %v", err)
+	}
+}
+
+// The password of an mfa user is not a successful login yet, the failures are kept until the code is verified.
+func TestLoginKeepsFailuresUntilMfa(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 3, Window: time.Minute, Duration: time.Minute})
+
+	lt.failLogins(t, lt.mfaUser.Email, "", 2)
+	tokens, err := lt.login(lt.mfaUser.Email, testPassword, "")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !tokens.MfaRequired || tokens.AccessToken != "" {
+		t.Fatalf("Login tokens = %+v, want an mfa challenge only", tokens)
+	}
+
+	lt.failLogins(t, lt.mfaUser.Email, "", 1)
+	if _, err := lt.login(lt.mfaUser.Email, testPassword, ""); !errors.Is(err, ctxerrors.ErrTooManyRequests) {
+		t.Fatalf("Login err = %v, want ErrTooManyRequests", err)
+	}
+}
+
+func TestCountLoginFailureDelay(t *testing.T) {
+	lt := newLockoutTest(t, LockoutOptions{MaxEmailAttempts: 10, Window: time.Minute, BaseDelay: time.Second, MaxDelay: 4 * time.Second})
+	keys := loginAttemptsKeys(lt.svc.lockoutOpts, lt.user.Email, "")
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+	for i, wantDelay := range want {
+		delay, err := countLoginFailure(context.Background(), lt.attempts, lt.svc.lockoutOpts, keys)
+		if err != nil {
+			t.Fatalf("countLoginFailure: %v", err)
+		}
+		if delay != wantDelay {
+			t.Errorf("countLoginFailure delay of failure %d = %v, want %v", i+1, delay, wantDelay)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	tokenshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/tokens"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	"github.com/google/uuid"
+	"time"
+)
+
+type oneTimeTokensStore interface {
+	dep.OneTimeTokenCreator
+	dep.OneTimeTokenGetter
+	dep.OneTimeTokenConsumer
+}
+
+// issueOneTimeToken creates a new token for the user (or the email when the user is empty) and purpose of the info
+// and invalidates the ones issued before, so only the latest requested token stays valid. Only the hash of the token is stored.
+func issueOneTimeToken(
+	ctx context.Context,
+	tokensRep oneTimeTokensStore,
+	info repositoriestransfer.CreateOneTimeTokenInfo,
+	ttl time.Duration,
+	tx database.Transaction,
+) (string, time.Time, error) {
+	previousCondition := map[repositoriestransfer.OneTimeTokenFieldTarget]any{
+		repositoriestransfer.OneTimeTokenPurposeCondition: info.Purpose,
+	}
+	if info.UserId != uuid.Nil {
+		previousCondition[repositoriestransfer.OneTimeTokenUserIdCondition] = info.UserId
+	} else {
+		previousCondition[repositoriestransfer.OneTimeTokenEmailCondition] = info.Email
+	}
+
+	if _, err := tokensRep.Use(ctx, repositoriestransfer.UseOneTimeTokensInfo{
+		Condition: previousCondition,
+	}, tx); err != nil {
+		return "", time.Time{}, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t invalidate previous tokens", err))
+	}
+
+	rawToken, err := tokenshelper.NewOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate token", err))
+	}
+
+	info.TokenHash = tokenshelper.HashOpaqueToken(rawToken)
+	info.ExpiresAt = time.Now().Add(ttl)
+
+	if _, err := tokensRep.Create(ctx, info, tx); err != nil {
+		return "", time.Time{}, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create token in db", err))
+	}
+
+	return rawToken, info.ExpiresAt, nil
+}
+
+// spendOneTimeToken marks the token as used. Unknown, used and expired tokens are a bad request.
+func spendOneTimeToken(
+	ctx context.Context,
+	tokensRep oneTimeTokensStore,
+	rawToken string,
+	purpose repositoriestransfer.OneTimeTokenPurpose,
+	tx database.Transaction,
+) (*models.OneTimeToken, error) {
+	token, err := tokensRep.Token(ctx, repositoriestransfer.GetOneTimeTokenInfo{
+		Condition: map[repositoriestransfer.OneTimeTokenFieldTarget]any{
+			repositoriestransfer.OneTimeTokenHashCondition:    tokenshelper.HashOpaqueToken(rawToken),
+			repositoriestransfer.OneTimeTokenPurposeCondition: purpose,
+		},
+	}, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token not found", ctxerrors.ErrBadRequest))
+		}
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get token from db", err))
+	}
+
+	if token.IsUsed() || token.IsExpired() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token is used or expired", ctxerrors.ErrBadRequest))
+	}
+
+	used, err := tokensRep.Use(ctx, repositoriestransfer.UseOneTimeTokensInfo{
+		Condition: map[repositoriestransfer.OneTimeTokenFieldTarget]any{
+			repositoriestransfer.OneTimeTokenIdCondition: token.Id,
+		},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t use token", err))
+	}
+	if used == 0 {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("token is already used", ctxerrors.ErrBadRequest))
+	}
+
+	return token, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient"
+	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient/messages"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	passwordshelper "github.com/KBcHMFollower/blog_user_service/internal/lib/passwords"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"github.com/google/uuid"
+	"time"
+)
+
+type passSvcUserStore interface {
+	dep.UserGetter
+	dep.UserPasswordUpdater
+	dep.UserDeleter
+}
+
+type PasswordService struct {
+	userRep       passSvcUserStore
+	tokensRep     oneTimeTokensStore
+	refreshRep    dep.RefreshTokenRevoker
+	sessionsRep   dep.SessionRevoker
+	eventsRep     dep.EventCreator
+	log           logger.Logger
+	policy        passwordshelper.Policy
+	hasher        passwordshelper.Hasher
+	resetTokenTtl time.Duration
+	txCreator     dep.TransactionCreator
+}
+
+func NewPasswordService(
+	userRep passSvcUserStore,
+	tokensRep oneTimeTokensStore,
+	refreshRep dep.RefreshTokenRevoker,
+	sessionsRep dep.SessionRevoker,
+	eventsRep dep.EventCreator,
+	log logger.Logger,
+	policy passwordshelper.Policy,
+	hasher passwordshelper.Hasher,
+	resetTokenTtl time.Duration,
+	txCreator dep.TransactionCreator,
+) *PasswordService {
+	return &PasswordService{
+		userRep:       userRep,
+		tokensRep:     tokensRep,
+		refreshRep:    refreshRep,
+		sessionsRep:   sessionsRep,
+		eventsRep:     eventsRep,
+		log:           log,
+		policy:        policy,
+		hasher:        hasher,
+		resetTokenTtl: resetTokenTtl,
+		txCreator:     txCreator,
+	}
+}
+
+// RequestPasswordReset issues a new reset token and hands it to the mailer through the outbox.
+// Unknown emails are not reported to the caller, so the method can't be used to find registered users.
+func (ps *PasswordService) RequestPasswordReset(ctx context.Context, requestInfo *transfer.RequestPasswordResetInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionEmailKey, requestInfo.Email)
+
+	ps.log.InfoContext(ctx, "trying to request password reset")
+
+	user, err := ps.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserEmailCondition: requestInfo.Email,
+		},
+	}, nil)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			ps.log.InfoContext(ctx, "password reset is requested for unknown email")
+			return nil
+		}
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, user.Id)
+
+	tx, err := ps.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	rawToken, expiresAt, err := issueOneTimeToken(ctx, ps.tokensRep, repositoriestransfer.CreateOneTimeTokenInfo{
+		UserId:  user.Id,
+		Purpose: repositoriestransfer.PasswordResetPurpose,
+	}, ps.resetTokenTtl, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t issue reset token", err))
+	}
+
+	eventId := uuid.New()
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
+
+	if err := createOutboxEvent(ctx, ps.eventsRep, eventId, amqpclient.PasswordResetRequestedEventKey, messages.PasswordResetRequestedMessage{
+		EventId:   eventId,
+		UserId:    user.Id,
+		Email:     user.Email,
+		Token:     rawToken,
+		ExpiresAt: expiresAt,
+	}, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	ps.log.InfoContext(ctx, "password reset requested successfully")
+
+	return nil
+}
+
+// ConfirmPasswordReset spends the reset token, sets the new password and ends every session of the user.
+func (ps *PasswordService) ConfirmPasswordReset(ctx context.Context, confirmInfo *transfer.ConfirmPasswordResetInfo) (resErr error) {
+	ps.log.InfoContext(ctx, "trying to confirm password reset")
+
+	tx, err := ps.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	token, err := spendOneTimeToken(ctx, ps.tokensRep, confirmInfo.Token, repositoriestransfer.PasswordResetPurpose, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t spend reset token", err))
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, token.UserId)
+
+	user, err := ps.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: token.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if err := validateNewPassword(ctx, ps.policy, confirmInfo.NewPassword, user); err != nil {
+		return err
+	}
+
+	if err := ps.setPassword(ctx, user.Id, confirmInfo.NewPassword, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := ps.userRep.DeleteFromCache(ctx, user.Id); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	ps.log.InfoContext(ctx, "password reset successfully")
+
+	return nil
+}
+
+// ChangePassword replaces the password of a user who knows the current one. Every issued
+// token stops being accepted, so the user has to log in again on every device.
+func (ps *PasswordService) ChangePassword(ctx context.Context, changeInfo *transfer.ChangePasswordInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, changeInfo.UserId)
+
+	ps.log.InfoContext(ctx, "trying to change password")
+
+	if changeInfo.NewPassword == changeInfo.OldPassword {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("new password is equal to the old one", ctxerrors.ErrBadRequest))
+	}
+
+	tx, err := ps.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	user, err := ps.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: changeInfo.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	if err := checkPassword(ctx, ps.hasher, user.PassHash, changeInfo.OldPassword); err != nil {
+		return err
+	}
+	if err := validateNewPassword(ctx, ps.policy, changeInfo.NewPassword, user); err != nil {
+		return err
+	}
+
+	if err := ps.setPassword(ctx, user.Id, changeInfo.NewPassword, tx); err != nil {
+		return err
+	}
+
+	eventId := uuid.New()
+
+	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
+
+	if err := createOutboxEvent(ctx, ps.eventsRep, eventId, amqpclient.PasswordChangedEventKey, messages.PasswordChangedMessage{
+		EventId:   eventId,
+		UserId:    user.Id,
+		Email:     user.Email,
+		ChangedAt: time.Now(),
+	}, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := ps.userRep.DeleteFromCache(ctx, user.Id); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	ps.log.InfoContext(ctx, "password changed successfully")
+
+	return nil
+}
+
+// setPassword stores the new hash, bumps the token version (so issued access tokens are rejected)
+// and revokes every refresh token of the user. The cached user is dropped by the caller once the
+// transaction is committed.
+func (ps *PasswordService) setPassword(ctx context.Context, userId uuid.UUID, password string, tx database.Transaction) error {
+	hashPass, err := ps.hasher.Hash(password)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate hashPass", err))
+	}
+
+	if err := ps.userRep.UpdatePassword(ctx, repositoriestransfer.UpdatePasswordInfo{
+		Id:       userId,
+		PassHash: hashPass,
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update password in db", err))
+	}
+
+	ps.log.DebugContext(ctx, "password updated in db")
+
+	if _, err := revokeSessions(ctx, ps.sessionsRep, ps.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+		Condition: map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionUserIdCondition: userId,
+		},
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke user sessions", err))
+	}
+
+	ps.log.DebugContext(ctx, "user sessions revoked")
+
+	return nil
+}
+
+// checkPassword returns ErrBadRequest when the password doesn't match the stored hash.
+func checkPassword(ctx context.Context, hasher passwordshelper.Hasher, hash []byte, password string) error {
+	matches, err := hasher.Verify(hash, password)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t verify password", err))
+	}
+	if !matches {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("passwords not eq", ctxerrors.ErrBadRequest))
+	}
+
+	return nil
+}
+
+// validateNewPassword checks the policy, the password must not contain the email or the names of the user.
+func validateNewPassword(ctx context.Context, policy passwordshelper.Policy, password string, user *models.User) error {
+	if err := policy.Validate(password, user.Email, user.FName, user.LName); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(err.Error(), ctxerrors.ErrBadRequest))
+	}
+
+	return nil
+}
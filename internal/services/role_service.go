@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"github.com/google/uuid"
+)
+
+const (
+	roleLogKey = "role"
+)
+
+type roleSvcUserStore interface {
+	dep.UserGetter
+	dep.UserTokenVersionBumper
+	dep.UserDeleter
+}
+
+type rolesStore interface {
+	dep.RoleExistenceChecker
+	dep.UserRolesGetter
+	dep.UserRolesManager
+	dep.RolePermissionsGetter
+}
+
+type RoleService struct {
+	userRep   roleSvcUserStore
+	rolesRep  rolesStore
+	log       logger.Logger
+	txCreator dep.TransactionCreator
+}
+
+func NewRoleService(
+	userRep roleSvcUserStore,
+	rolesRep rolesStore,
+	log logger.Logger,
+	txCreator dep.TransactionCreator,
+) *RoleService {
+	return &RoleService{
+		userRep:   userRep,
+		rolesRep:  rolesRep,
+		log:       log,
+		txCreator: txCreator,
+	}
+}
+
+func (rs *RoleService) GrantRole(ctx context.Context, grantInfo *transfer.GrantRoleInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, grantInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, roleLogKey, grantInfo.Role)
+
+	rs.log.InfoContext(ctx, "trying to grant role")
+
+	tx, err := rs.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	if err := rs.checkRoleExists(ctx, grantInfo.Role, tx); err != nil {
+		return err
+	}
+
+	if _, err := rs.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: grantInfo.UserId,
+		},
+	}, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	granted, err := rs.rolesRep.Grant(ctx, repositoriestransfer.UserRoleInfo{
+		UserId: grantInfo.UserId,
+		Role:   grantInfo.Role,
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t grant role in db", err))
+	}
+	if granted {
+		if err := rs.invalidateClaims(ctx, grantInfo.UserId, tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if granted {
+		if err := rs.userRep.DeleteFromCache(ctx, grantInfo.UserId); err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+		}
+	}
+
+	rs.log.InfoContext(ctx, "role granted successfully", "already-granted", !granted)
+
+	return nil
+}
+
+func (rs *RoleService) RevokeRole(ctx context.Context, revokeInfo *transfer.RevokeRoleInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, revokeInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, roleLogKey, revokeInfo.Role)
+
+	rs.log.InfoContext(ctx, "trying to revoke role")
+
+	tx, err := rs.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	revoked, err := rs.rolesRep.Revoke(ctx, repositoriestransfer.UserRoleInfo{
+		UserId: revokeInfo.UserId,
+		Role:   revokeInfo.Role,
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke role in db", err))
+	}
+	if !revoked {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("user has no such role", ctxerrors.ErrNotFound))
+	}
+
+	if err := rs.invalidateClaims(ctx, revokeInfo.UserId, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	if err := rs.userRep.DeleteFromCache(ctx, revokeInfo.UserId); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
+	}
+
+	rs.log.InfoContext(ctx, "role revoked successfully")
+
+	return nil
+}
+
+// RolesPermissions is used by the auth interceptor to check the permissions of the caller roles.
+func (rs *RoleService) RolesPermissions(ctx context.Context, roles []string) ([]string, error) {
+	permissions, err := rs.rolesRep.RolesPermissions(ctx, roles)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get roles permissions", err))
+	}
+
+	return permissions, nil
+}
+
+func (rs *RoleService) checkRoleExists(ctx context.Context, role string, tx database.Transaction) error {
+	exists, err := rs.rolesRep.RoleExists(ctx, role, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t check role", err))
+	}
+	if !exists {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("unknown role", ctxerrors.ErrBadRequest))
+	}
+
+	return nil
+}
+
+// invalidateClaims makes the user access tokens outdated, the refreshed ones carry the new roles.
+// The cached user is dropped by the caller once the transaction is committed.
+func (rs *RoleService) invalidateClaims(ctx context.Context, userId uuid.UUID, tx database.Transaction) error {
+	if err := rs.userRep.BumpTokenVersion(ctx, userId, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t bump token version", err))
+	}
+
+	return nil
+}
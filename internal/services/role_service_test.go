@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+type fakeRoles struct {
+	known map[string]bool
+	fakeUserRoles
+}
+
+func (f *fakeRoles) RoleExists(_ context.Context, role string, _ database.Transaction) (bool, error) {
+	return f.known[role], nil
+}
+
+func (f *fakeRoles) Grant(_ context.Context, info repositoriestransfer.UserRoleInfo, _ database.Transaction) (bool, error) {
+	if slices.Contains(f.roles[info.UserId], info.Role) {
+		return false, nil
+	}
+	f.roles[info.UserId] = append(f.roles[info.UserId], info.Role)
+	return true, nil
+}
+
+func (f *fakeRoles) Revoke(_ context.Context, info repositoriestransfer.UserRoleInfo, _ database.Transaction) (bool, error) {
+	roles := f.roles[info.UserId]
+	i := slices.Index(roles, info.Role)
+	if i < 0 {
+		return false, nil
+	}
+	f.roles[info.UserId] = slices.Delete(roles, i, i+1)
+	return true, nil
+}
+
+func (f *fakeRoles) RolesPermissions(context.Context, []string) ([]string, error) {
+	return nil, nil
+}
+
+type roleTest struct {
+	svc   *RoleService
+	users *fakeUsers
+	roles *fakeRoles
+	txs   *fakeTxCreator
+	user  *models.User
+}
+
+func newRoleTest() *roleTest {
+	user := models.NewUserModel("user@example.com", "First", "Last", nil)
+	txs := &fakeTxCreator{}
+	users := &fakeUsers{users: []*models.User{user}, txs: txs}
+	roles := &fakeRoles{
+		known:         map[string]bool{rbac.AdminRole: true, rbac.ModeratorRole: true},
+		fakeUserRoles: fakeUserRoles{roles: map[uuid.UUID][]string{user.Id: {rbac.ModeratorRole}}},
+	}
+
+	return &roleTest{
+		svc:   NewRoleService(users, roles, testLogger(), txs),
+		users: users,
+		roles: roles,
+		txs:   txs,
+		user:  user,
+	}
+}
+
+func TestGrantRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  func(rt *roleTest) uuid.UUID
+		role    string
+		wantErr error
+		changed bool
+	}{
+		{name: "new role", role: rbac.AdminRole, changed: true},
+		{name: "already granted", role: rbac.ModeratorRole},
+		{name: "unknown role", role: "superuser", wantErr: ctxerrors.ErrBadRequest},
+		{name: "unknown user", userId: func(*roleTest) uuid.UUID { return uuid.New() }, role: rbac.AdminRole, wantErr: ctxerrors.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newRoleTest()
+			userId := rt.user.Id
+			if tt.userId != nil {
+				userId = tt.userId(rt)
+			}
+
+			err := rt.svc.GrantRole(context.Background(), &transfer.GrantRoleInfo{UserId: userId, Role: tt.role})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GrantRole err = %v, want %v", err, tt.wantErr)
+				}
+				if !rt.txs.last().rolledBack {
+					t.Errorf("GrantRole didn't roll the transaction back")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GrantRole: %v", err)
+			}
+
+			assertClaimsInvalidated(t, rt, tt.changed)
+			if !slices.Contains(rt.roles.roles[rt.user.Id], tt.role) {
+				t.Errorf("user roles = %v, want %s among them", rt.roles.roles[rt.user.Id], tt.role)
+			}
+		})
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		wantErr error
+	}{
+		{name: "granted role", role: rbac.ModeratorRole},
+		{name: "not granted role", role: rbac.AdminRole, wantErr: ctxerrors.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newRoleTest()
+
+			err := rt.svc.RevokeRole(context.Background(), &transfer.RevokeRoleInfo{UserId: rt.user.Id, Role: tt.role})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RevokeRole err = %v, want %v", err, tt.wantErr)
+				}
+				assertClaimsInvalidated(t, rt, false)
+				return
+			}
+			if err != nil {
+				t.Fatalf("RevokeRole: %v", err)
+			}
+
+			assertClaimsInvalidated(t, rt, true)
+			if slices.Contains(rt.roles.roles[rt.user.Id], tt.role) {
+				t.Errorf("user roles = %v, want %s revoked", rt.roles.roles[rt.user.Id], tt.role)
+			}
+		})
+	}
+}
+
+// assertClaimsInvalidated checks the token version and the cached user were dropped only when the roles changed,
+// the fake users store fails the cache deletion made before the commit.
+func assertClaimsInvalidated(t *testing.T, rt *roleTest, changed bool) {
+	t.Helper()
+
+	wantVersion, wantDeletes := 0, 0
+	if changed {
+		wantVersion, wantDeletes = 1, 1
+	}
+
+	if rt.user.TokenVersion != wantVersion {
+		t.Errorf("token version = %d, want %d", rt.user.TokenVersion, wantVersion)
+	}
+	if len(rt.users.cacheDeletes) != wantDeletes {
+		t.Errorf("user deleted from cache %d times, want %d", len(rt.users.cacheDeletes), wantDeletes)
+	}
+}
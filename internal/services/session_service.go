@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"github.com/google/uuid"
+)
+
+const (
+	sessionIdLogKey = "session-id"
+)
+
+type sessionsStore interface {
+	dep.SessionCreator
+	dep.SessionsGetter
+	dep.SessionRevoker
+	dep.SessionChecker
+}
+
+type SessionService struct {
+	sessionsRep sessionsStore
+	refreshRep  dep.RefreshTokenRevoker
+	log         logger.Logger
+	txCreator   dep.TransactionCreator
+}
+
+func NewSessionService(
+	sessionsRep sessionsStore,
+	refreshRep dep.RefreshTokenRevoker,
+	log logger.Logger,
+	txCreator dep.TransactionCreator,
+) *SessionService {
+	return &SessionService{
+		sessionsRep: sessionsRep,
+		refreshRep:  refreshRep,
+		log:         log,
+		txCreator:   txCreator,
+	}
+}
+
+func (ss *SessionService) ListSessions(ctx context.Context, listInfo *transfer.ListSessionsInfo) (*transfer.ListSessionsResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, listInfo.UserId)
+
+	ss.log.InfoContext(ctx, "trying to list sessions")
+
+	sessions, err := ss.sessionsRep.Sessions(ctx, repositoriestransfer.GetSessionsInfo{
+		Condition: map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionUserIdCondition: listInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get sessions from db", err))
+	}
+
+	return transfer.GetListSessionsResultFromModels(sessions, listInfo.CurrentSessionId), nil
+}
+
+func (ss *SessionService) RevokeSession(ctx context.Context, revokeInfo *transfer.RevokeSessionInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, revokeInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, sessionIdLogKey, revokeInfo.SessionId)
+
+	ss.log.InfoContext(ctx, "trying to revoke session")
+
+	tx, err := ss.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	revoked, err := revokeSessions(ctx, ss.sessionsRep, ss.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+		Condition: map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionIdCondition:     revokeInfo.SessionId,
+			repositoriestransfer.SessionUserIdCondition: revokeInfo.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke session", err))
+	}
+	if revoked == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("active session not found", ctxerrors.ErrNotFound))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	ss.log.InfoContext(ctx, "session revoked successfully")
+
+	return nil
+}
+
+// RevokeAllOtherSessions revokes every session of the user except the current one and returns their number.
+func (ss *SessionService) RevokeAllOtherSessions(ctx context.Context, revokeInfo *transfer.RevokeOtherSessionsInfo) (resCount int64, resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, revokeInfo.UserId)
+	ctx = logger.UpdateLoggerCtx(ctx, sessionIdLogKey, revokeInfo.CurrentSessionId)
+
+	ss.log.InfoContext(ctx, "trying to revoke other sessions")
+
+	tx, err := ss.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	revoked, err := revokeSessions(ctx, ss.sessionsRep, ss.refreshRep, repositoriestransfer.RevokeSessionsInfo{
+		Condition: map[repositoriestransfer.SessionFieldTarget]any{
+			repositoriestransfer.SessionUserIdCondition: revokeInfo.UserId,
+		},
+		ExceptId: uuid.NullUUID{UUID: revokeInfo.CurrentSessionId, Valid: true},
+	}, tx)
+	if err != nil {
+		return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke sessions", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	ss.log.InfoContext(ctx, "other sessions revoked successfully", "revoked-sessions", revoked)
+
+	return revoked, nil
+}
+
+// revokeSessions revokes the sessions together with their refresh token families.
+func revokeSessions(
+	ctx context.Context,
+	sessionsRep dep.SessionRevoker,
+	refreshRep dep.RefreshTokenRevoker,
+	info repositoriestransfer.RevokeSessionsInfo,
+	tx database.Transaction,
+) (int64, error) {
+	revokedIds, err := sessionsRep.Revoke(ctx, info, tx)
+	if err != nil {
+		return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke sessions in db", err))
+	}
+	if len(revokedIds) == 0 {
+		return 0, nil
+	}
+
+	if _, err := refreshRep.Revoke(ctx, repositoriestransfer.RevokeRefreshTokensInfo{
+		Condition: map[repositoriestransfer.RefreshTokenFieldTarget]any{
+			repositoriestransfer.RefreshTokenFamilyCondition: revokedIds,
+		},
+	}, tx); err != nil {
+		return 0, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t revoke sessions refresh tokens", err))
+	}
+
+	return int64(len(revokedIds)), nil
+}
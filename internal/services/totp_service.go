@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	totphelper "github.com/KBcHMFollower/blog_user_service/internal/lib/totp"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	dep "github.com/KBcHMFollower/blog_user_service/internal/services/interfaces/dep"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TotpOptions configure the second factor. Now is the clock the codes are checked against.
+type TotpOptions struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+	Cipher       dep.SecretCipher
+	Now          func() time.Time
+}
+
+type totpStore interface {
+	dep.TotpGetter
+	dep.TotpManager
+	dep.RecoveryCodesManager
+}
+
+type TotpService struct {
+	userRep     dep.UserGetter
+	totpRep     totpStore
+	attemptsRep loginAttemptsStore
+	log         logger.Logger
+	totpOpts    TotpOptions
+	lockoutOpts LockoutOptions
+	txCreator   dep.TransactionCreator
+}
+
+func NewTotpService(
+	userRep dep.UserGetter,
+	totpRep totpStore,
+	attemptsRep loginAttemptsStore,
+	log logger.Logger,
+	totpOpts TotpOptions,
+	lockoutOpts LockoutOptions,
+	txCreator dep.TransactionCreator,
+) *TotpService {
+	if totpOpts.Now == nil {
+		totpOpts.Now = time.Now
+	}
+
+	return &TotpService{
+		userRep:     userRep,
+		totpRep:     totpRep,
+		attemptsRep: attemptsRep,
+		log:         log,
+		totpOpts:    totpOpts,
+		lockoutOpts: lockoutOpts,
+		txCreator:   txCreator,
+	}
+}
+
+// EnrollTotp generates a new secret. It is not used for login until it is confirmed with a code.
+func (ts *TotpService) EnrollTotp(ctx context.Context, enrollInfo *transfer.EnrollTotpInfo) (*transfer.EnrollTotpResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, enrollInfo.UserId)
+
+	ts.log.InfoContext(ctx, "trying to enroll totp")
+
+	user, err := ts.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: enrollInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	secret, err := totphelper.GenerateSecret()
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate totp secret", err))
+	}
+
+	encryptedSecret, err := ts.totpOpts.Cipher.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t encrypt totp secret", err))
+	}
+
+	saved, err := ts.totpRep.SaveSecret(ctx, repositoriestransfer.SaveTotpSecretInfo{
+		UserId:          user.Id,
+		EncryptedSecret: encryptedSecret,
+	}, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t save totp secret in db", err))
+	}
+	if saved == 0 {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp is already enabled", ctxerrors.ErrConflict))
+	}
+
+	ts.log.InfoContext(ctx, "totp enrolled successfully")
+
+	return &transfer.EnrollTotpResult{
+		Secret:          secret,
+		ProvisioningUri: totphelper.ProvisioningURI(ts.totpOpts.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTotp enables the enrolled secret and returns recovery codes, they are never shown again.
+func (ts *TotpService) ConfirmTotp(ctx context.Context, confirmInfo *transfer.ConfirmTotpInfo) (resCodes *transfer.ConfirmTotpResult, resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, confirmInfo.UserId)
+
+	ts.log.InfoContext(ctx, "trying to confirm totp")
+
+	tx, err := ts.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	totp, err := ts.totpRep.Totp(ctx, confirmInfo.UserId, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp is not enrolled", ctxerrors.ErrBadRequest))
+		}
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get totp from db", err))
+	}
+	if totp.IsConfirmed() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp is already enabled", ctxerrors.ErrConflict))
+	}
+
+	if err := checkTotpCode(ctx, ts.totpRep, ts.totpOpts, totp, confirmInfo.Code, tx); err != nil {
+		return nil, err
+	}
+
+	if err := ts.totpRep.Confirm(ctx, confirmInfo.UserId, tx); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t confirm totp in db", err))
+	}
+
+	recoveryCodes, err := totphelper.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t generate recovery codes", err))
+	}
+
+	codeHashes := make([][]byte, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		codeHashes = append(codeHashes, totphelper.HashRecoveryCode(code))
+	}
+
+	if err := ts.totpRep.ReplaceRecoveryCodes(ctx, repositoriestransfer.ReplaceRecoveryCodesInfo{
+		UserId:     confirmInfo.UserId,
+		CodeHashes: codeHashes,
+	}, tx); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t save recovery codes in db", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	ts.log.InfoContext(ctx, "totp confirmed successfully")
+
+	return &transfer.ConfirmTotpResult{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// DisableTotp requires a code or a recovery code, a stolen session alone is not enough to turn 2fa off.
+// Wrong codes are counted like failed logins of the user.
+func (ts *TotpService) DisableTotp(ctx context.Context, disableInfo *transfer.DisableTotpInfo) (resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, disableInfo.UserId)
+
+	ts.log.InfoContext(ctx, "trying to disable totp")
+
+	user, err := ts.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+			repositoriestransfer.UserIdCondition: disableInfo.UserId,
+		},
+	}, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	attemptsKeys := loginAttemptsKeys(ts.lockoutOpts, user.Email, disableInfo.Client.IpAddress)
+	if err := checkLoginLocks(ctx, ts.attemptsRep, attemptsKeys); err != nil {
+		return err
+	}
+
+	tx, err := ts.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t start transaction", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	if err := verifySecondFactor(ctx, ts.totpRep, ts.totpOpts, disableInfo.UserId, disableInfo.Code, disableInfo.RecoveryCode, tx); err != nil {
+		if errors.Is(err, ctxerrors.ErrUnauthorized) {
+			// the connection is not held for the progressive delay
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t rollback transaction", rbErr))
+			}
+			return failAttempt(ctx, ts.log, ts.attemptsRep, ts.lockoutOpts, attemptsKeys, err)
+		}
+		return err
+	}
+
+	if err := ts.totpRep.Delete(ctx, disableInfo.UserId, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete totp from db", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit transaction", err))
+	}
+
+	ts.log.InfoContext(ctx, "totp disabled successfully")
+
+	return nil
+}
+
+// isTotpEnabled reports whether the user has a confirmed totp secret.
+func isTotpEnabled(ctx context.Context, totpRep dep.TotpGetter, userId uuid.UUID, tx database.Transaction) (bool, error) {
+	totp, err := totpRep.Totp(ctx, userId, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return false, nil
+		}
+		return false, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get totp from db", err))
+	}
+
+	return totp.IsConfirmed(), nil
+}
+
+// verifySecondFactor accepts either a code of the confirmed secret or one of the unused recovery codes.
+func verifySecondFactor(
+	ctx context.Context,
+	totpRep totpStore,
+	opts TotpOptions,
+	userId uuid.UUID,
+	code string,
+	recoveryCode string,
+	tx database.Transaction,
+) error {
+	if recoveryCode != "" {
+		used, err := totpRep.UseRecoveryCode(ctx, repositoriestransfer.UseRecoveryCodeInfo{
+			UserId:   userId,
+			CodeHash: totphelper.HashRecoveryCode(recoveryCode),
+		}, tx)
+		if err != nil {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t use recovery code", err))
+		}
+		if used == 0 {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("recovery code is invalid", ctxerrors.ErrUnauthorized))
+		}
+
+		return nil
+	}
+
+	totp, err := totpRep.Totp(ctx, userId, tx)
+	if err != nil {
+		if errors.Is(err, ctxerrors.ErrNotFound) {
+			return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp is not enabled", ctxerrors.ErrBadRequest))
+		}
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get totp from db", err))
+	}
+	if !totp.IsConfirmed() {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp is not enabled", ctxerrors.ErrBadRequest))
+	}
+
+	return checkTotpCode(ctx, totpRep, opts, totp, code, tx)
+}
+
+func checkTotpCode(ctx context.Context, totpRep dep.TotpManager, opts TotpOptions, totp *models.UserTotp, code string, tx database.Transaction) error {
+	secret, err := opts.Cipher.Decrypt(totp.Secret)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t decrypt totp secret", err))
+	}
+
+	step, ok, err := totphelper.Validate(string(secret), code, opts.Now())
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t validate totp code", err))
+	}
+	if !ok {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp code is invalid", ctxerrors.ErrUnauthorized))
+	}
+
+	used, err := totpRep.UseStep(ctx, repositoriestransfer.UseTotpStepInfo{
+		UserId: totp.UserId,
+		Step:   step,
+	}, tx)
+	if err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t use totp step", err))
+	}
+	if used == 0 {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("totp code is already used", ctxerrors.ErrUnauthorized))
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"github.com/KBcHMFollower/blog_user_service/internal/clients/amqpclient"
@@ -26,8 +27,18 @@ const (
 	subscriberIdLogKey  = "subscriber-id"
 	updateInfoLogKey    = "update-info"
 	avatarUruLogKey     = "avatar-uru"
+	tokenFamilyIdLogKey = "token-family-id"
+	tokenJtiLogKey      = "token-jti"
+	newEmailLogKey      = "new-email"
+	actorIdLogKey       = "actor-id"
+	usersCountLogKey    = "users-count"
 )
 
+// UsersBatchOptions limit the number of ids GetUsers takes at once.
+type UsersBatchOptions struct {
+	Limit int
+}
+
 type usrSvcEventStore interface {
 	dep.EventCreator
 	dep.EventGetter
@@ -43,6 +54,9 @@ type usrSvcUsersStore interface {
 	dep.UserGetter
 	dep.UserUpdater
 	dep.UserCreator
+	dep.UsernameGetter
+	dep.UsernameCache
+	dep.UsersBatchCache
 }
 
 type subsSvcSubscribersStore interface {
@@ -51,21 +65,39 @@ type subsSvcSubscribersStore interface {
 }
 
 type UserService struct {
-	log       logger.Logger
-	userRep   usrSvcUsersStore
-	eventsRep usrSvcEventStore
-	subsRep   subsSvcSubscribersStore
-	txCreator dep.TransactionCreator
-	imgStore  usrSvcImageStore
+	log            logger.Logger
+	userRep        usrSvcUsersStore
+	eventsRep      usrSvcEventStore
+	subsRep        subsSvcSubscribersStore
+	revocationsRep dep.TokenRevoker
+	txCreator      dep.TransactionCreator
+	imgStore       usrSvcImageStore
+	usernamesRep   dep.UsernameHistoryManager
+	usernameOpts   UsernameOptions
+	batchOpts      UsersBatchOptions
 }
 
-func NewUserService(log logger.Logger, txCreator dep.TransactionCreator, userRep usrSvcUsersStore, eventsRep usrSvcEventStore, imgStore usrSvcImageStore) *UserService {
+func NewUserService(
+	log logger.Logger,
+	txCreator dep.TransactionCreator,
+	userRep usrSvcUsersStore,
+	eventsRep usrSvcEventStore,
+	imgStore usrSvcImageStore,
+	revocationsRep dep.TokenRevoker,
+	usernamesRep dep.UsernameHistoryManager,
+	usernameOpts UsernameOptions,
+	batchOpts UsersBatchOptions,
+) *UserService {
 	return &UserService{
-		log:       log,
-		userRep:   userRep,
-		imgStore:  imgStore,
-		txCreator: txCreator,
-		eventsRep: eventsRep,
+		log:            log,
+		userRep:        userRep,
+		imgStore:       imgStore,
+		txCreator:      txCreator,
+		eventsRep:      eventsRep,
+		revocationsRep: revocationsRep,
+		usernamesRep:   usernamesRep,
+		usernameOpts:   usernameOpts,
+		batchOpts:      batchOpts,
 	}
 }
 
@@ -122,6 +154,82 @@ func (a *UserService) GetUserById(ctx context.Context, userId uuid.UUID) (ersUse
 	}, nil
 }
 
+// GetUsers reads the cached users with one multi-get and the rest with one query, the loaded users are cached.
+func (a *UserService) GetUsers(ctx context.Context, getInfo *transfer.GetUsersInfo) (*transfer.GetUsersResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, usersCountLogKey, len(getInfo.Ids))
+
+	a.log.DebugContext(ctx, "try to get users by ids")
+
+	if len(getInfo.Ids) > a.batchOpts.Limit {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(fmt.Sprintf("can`t get more than %d users at once", a.batchOpts.Limit), ctxerrors.ErrBadRequest))
+	}
+
+	ids := make([]uuid.UUID, 0, len(getInfo.Ids))
+	seen := make(map[uuid.UUID]struct{}, len(getInfo.Ids))
+	for _, id := range getInfo.Ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	users, err := a.userRep.TryGetManyFromCache(ctx, ids)
+	if err != nil {
+		a.log.DebugContext(ctx, "can`t get users from cache: ", "err", err.Error())
+		users = make(map[uuid.UUID]*models.User, len(ids))
+	}
+
+	misses := make([]uuid.UUID, 0, len(ids)-len(users))
+	for _, id := range ids {
+		if _, ok := users[id]; !ok {
+			misses = append(misses, id)
+		}
+	}
+
+	a.log.DebugContext(ctx, "users found in cache", "hits", len(users), "misses", len(misses))
+
+	if len(misses) > 0 {
+		loaded, err := a.userRep.Users(ctx, &repositoriestransfer.GetUsersInfo{
+			Size: uint64(len(misses)),
+			Condition: map[repositoriestransfer.UserFieldTarget]interface{}{
+				repositoriestransfer.UserIdCondition: misses,
+			},
+		}, nil)
+		if err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get users from db", err))
+		}
+
+		if len(loaded) > 0 {
+			if err := a.userRep.SetManyToCache(ctx, loaded); err != nil {
+				return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t set users to cache", err))
+			}
+			a.log.DebugContext(ctx, "users added to cache")
+		}
+
+		for _, user := range loaded {
+			users[user.Id] = user
+		}
+	}
+
+	res := &transfer.GetUsersResult{
+		Users:       make([]transfer.UserResult, 0, len(ids)),
+		NotFoundIds: make([]uuid.UUID, 0),
+	}
+	for _, id := range ids {
+		user, ok := users[id]
+		if !ok {
+			res.NotFoundIds = append(res.NotFoundIds, id)
+			continue
+		}
+		res.Users = append(res.Users, transfer.GetUserResultFromModel(user))
+	}
+
+	a.log.DebugContext(ctx, "get users by ids successfully")
+
+	return res, nil
+}
+
 func (a *UserService) UpdateUser(ctx context.Context, updateInfo *transfer.UpdateUserInfo) (resUser *transfer.UpdateUserResult, resErr error) {
 	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, updateInfo.Id)
 	ctx = logger.UpdateLoggerCtx(ctx, updateInfoLogKey, updateInfo.UpdateFields)
@@ -136,9 +244,18 @@ func (a *UserService) UpdateUser(ctx context.Context, updateInfo *transfer.Updat
 		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
 	}()
 
+	formerUsername := ""
+	if username, ok := updateInfo.UpdateFields[transfer.UserUsernameUpdateTarget]; ok {
+		formerUsername, err = a.renameUsername(ctx, updateInfo.Id, fmt.Sprint(username), tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := a.userRep.Update(ctx, repositoriestransfer.UpdateUserInfo{
-		Id:         updateInfo.Id,
-		UpdateInfo: servicesutils.ConvertMapKeysToStrings(updateInfo.UpdateFields),
+		Id:              updateInfo.Id,
+		UpdateInfo:      userUpdateColumns(updateInfo.UpdateFields),
+		ExpectedVersion: updateInfo.ExpectedVersion,
 	}, tx); err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update user in db", err))
 	}
@@ -149,15 +266,22 @@ func (a *UserService) UpdateUser(ctx context.Context, updateInfo *transfer.Updat
 		},
 	}, tx)
 	if exErr != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", exErr))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit tx", err))
 	}
 
 	if err := a.userRep.DeleteFromCache(ctx, updateInfo.Id); err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t update user in db", err))
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete user from cache", err))
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit tx", err))
+	// a lookup running meanwhile could cache the former username again before the commit
+	if formerUsername != "" {
+		if err := a.userRep.DeleteUsernameFromCache(ctx, formerUsername); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete username from cache", err))
+		}
 	}
 
 	a.log.InfoContext(ctx, "user updated successfully")
@@ -203,21 +327,51 @@ func (a *UserService) DeleteUser(ctx context.Context, deleteInfo *transfer.Delet
 
 	a.log.InfoContext(ctx, "user deleted from cache")
 
+	revocation := repositoriestransfer.RevokeUserTokensInfo{
+		UserId:        deleteInfo.Id,
+		RevokedBefore: time.Now(),
+	}
+	if err := a.revocationsRep.RevokeUserTokens(ctx, revocation, tx); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `RevokeUserTokens`", err))
+	}
+
+	a.log.InfoContext(ctx, "user tokens revoked")
+
 	eventId := uuid.New()
 
 	ctx = logger.UpdateLoggerCtx(ctx, logger.EventIdKey, eventId)
 
+	var birthday *time.Time
+	if user.Birthday.Valid {
+		birthday = &user.Birthday.Time
+	}
+	var emailVerifiedAt *time.Time
+	if user.EmailVerifiedAt.Valid {
+		emailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
 	messageEntity := messages.UserDeletedMessage{
 		User: messages.UserMessage{
-			FName:       user.FName,
-			LName:       user.LName,
-			Email:       user.Email,
-			PassHash:    user.PassHash,
-			Id:          user.Id,
-			Avatar:      user.Avatar,
-			AvatarMin:   user.AvatarMin,
-			CreatedDate: user.CreatedDate,
-			UpdatedDate: user.UpdatedDate,
+			FName:              user.FName,
+			LName:              user.LName,
+			Email:              user.Email,
+			Username:           user.Username.String,
+			PassHash:           user.PassHash,
+			TokenVersion:       user.TokenVersion,
+			EmailVerifiedAt:    emailVerifiedAt,
+			Id:                 user.Id,
+			Avatar:             user.Avatar,
+			AvatarMin:          user.AvatarMin,
+			Bio:                user.Bio,
+			Links:              user.Links,
+			Location:           user.Location,
+			Birthday:           birthday,
+			Pronouns:           user.Pronouns,
+			Cover:              user.Cover,
+			Version:            user.Version,
+			BirthdayVisibility: string(user.BirthdayVisibility),
+			CreatedDate:        user.CreatedDate,
+			UpdatedDate:        user.UpdatedDate,
 		},
 		EventId: eventId,
 	}
@@ -241,6 +395,10 @@ func (a *UserService) DeleteUser(ctx context.Context, deleteInfo *transfer.Delet
 		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
 	}
 
+	if err := a.revocationsRep.SetRevokedUserTokensToCache(ctx, revocation); err != nil {
+		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `SetRevokedUserTokensToCache`", err))
+	}
+
 	return nil
 }
 
@@ -271,26 +429,37 @@ func (a *UserService) UploadAvatar(ctx context.Context, uploadInfo *transfer.Upl
 			"avatar":     imgUrl,
 			"avatar_min": imgUrl,
 		},
-	}, nil); err != nil {
+		ExpectedVersion: uploadInfo.ExpectedVersion,
+	}, tx); err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
 	}
 
-	if err := a.userRep.DeleteFromCache(ctx, uploadInfo.UserId); err != nil {
-		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
+	user, err := a.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]any{
+			repositoriestransfer.UserIdCondition: uploadInfo.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
 	}
 
-	a.log.DebugContext(ctx, "user deleted from cache")
-
 	if err := tx.Commit(); err != nil {
 		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
 	}
 
+	if err := a.userRep.DeleteFromCache(ctx, uploadInfo.UserId); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
+	}
+
+	a.log.DebugContext(ctx, "user deleted from cache")
+
 	a.log.DebugContext(ctx, "avatar is uploaded successfully")
 
 	return &transfer.AvatarResult{
 		UserId:     uploadInfo.UserId,
 		Avatar:     imgUrl,
 		AvatarMini: imgUrl,
+		Version:    user.Version,
 	}, nil
 }
 
@@ -312,16 +481,41 @@ func (a *UserService) CompensateDeletedUser(ctx context.Context, eventId uuid.UU
 	ctx = logger.UpdateLoggerCtx(ctx, logger.ActionUserIdKey, message.User.Id)
 	a.log.InfoContext(ctx, "event is found")
 
+	birthday := sql.NullTime{}
+	if message.User.Birthday != nil {
+		birthday = sql.NullTime{Time: *message.User.Birthday, Valid: true}
+	}
+	emailVerifiedAt := sql.NullTime{}
+	if message.User.EmailVerifiedAt != nil {
+		emailVerifiedAt = sql.NullTime{Time: *message.User.EmailVerifiedAt, Valid: true}
+	}
+	// messages of the users deleted before the profile fields were added have no visibility
+	birthdayVisibility := models.BirthdayVisibility(message.User.BirthdayVisibility)
+	if birthdayVisibility == "" {
+		birthdayVisibility = models.BirthdayPrivate
+	}
+
 	err = a.userRep.RollBackUser(ctx, models.User{
-		Id:          message.User.Id,
-		Email:       message.User.Email,
-		PassHash:    message.User.PassHash,
-		FName:       message.User.FName,
-		LName:       message.User.LName,
-		CreatedDate: message.User.CreatedDate,
-		UpdatedDate: time.Now(),
-		Avatar:      message.User.Avatar,
-		AvatarMin:   message.User.AvatarMin,
+		Id:                 message.User.Id,
+		Email:              message.User.Email,
+		Username:           sql.NullString{String: message.User.Username, Valid: message.User.Username != ""},
+		PassHash:           message.User.PassHash,
+		TokenVersion:       message.User.TokenVersion,
+		EmailVerifiedAt:    emailVerifiedAt,
+		FName:              message.User.FName,
+		LName:              message.User.LName,
+		CreatedDate:        message.User.CreatedDate,
+		UpdatedDate:        time.Now(),
+		Avatar:             message.User.Avatar,
+		AvatarMin:          message.User.AvatarMin,
+		Bio:                message.User.Bio,
+		Links:              message.User.Links,
+		Location:           message.User.Location,
+		Birthday:           birthday,
+		Pronouns:           message.User.Pronouns,
+		Cover:              message.User.Cover,
+		Version:            max(message.User.Version, 1),
+		BirthdayVisibility: birthdayVisibility,
 	})
 	if err != nil {
 		return ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t` `CreateEvent`", err))
@@ -331,3 +525,19 @@ func (a *UserService) CompensateDeletedUser(ctx context.Context, eventId uuid.UU
 
 	return nil
 }
+
+// userUpdateColumns converts the update values to the column types, links are stored as json
+// and an empty birthday clears it.
+func userUpdateColumns(fields map[transfer.UserFieldTarget]any) map[string]any {
+	columns := servicesutils.ConvertMapKeysToStrings(fields)
+
+	if links, ok := fields[transfer.UserLinksUpdateTarget].([]string); ok {
+		columns[string(transfer.UserLinksUpdateTarget)] = models.ProfileLinks(links)
+	}
+
+	if birthday, ok := fields[transfer.UserBirthdayUpdateTarget]; ok && fmt.Sprint(birthday) == "" {
+		columns[string(transfer.UserBirthdayUpdateTarget)] = nil
+	}
+
+	return columns
+}
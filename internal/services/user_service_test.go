@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	usersv1 "github.com/KBcHMFollower/blog_user_service/api/protos/gen/users"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// fakeUserSvcUsers is the users store of UserService, the users are kept by fakeUsers.
+// The cached users are kept apart, cacheErr makes the cache reads fail.
+type fakeUserSvcUsers struct {
+	usrSvcUsersStore
+	users    *fakeUsers
+	updates  []repositoriestransfer.UpdateUserInfo
+	cached   map[uuid.UUID]*models.User
+	cacheErr error
+	queries  [][]uuid.UUID
+}
+
+func (f *fakeUserSvcUsers) Users(ctx context.Context, info *repositoriestransfer.GetUsersInfo, tx database.Transaction) ([]*models.User, error) {
+	ids := info.Condition[repositoriestransfer.UserIdCondition].([]uuid.UUID)
+	f.queries = append(f.queries, ids)
+
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		user, err := f.users.User(ctx, repositoriestransfer.GetUserInfo{
+			Condition: map[repositoriestransfer.UserFieldTarget]any{repositoriestransfer.UserIdCondition: id},
+		}, tx)
+		if err != nil {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	// the db doesn't keep the order of the ids
+	slices.Reverse(users)
+	return users, nil
+}
+
+func (f *fakeUserSvcUsers) TryGetManyFromCache(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	if f.cacheErr != nil {
+		return nil, f.cacheErr
+	}
+
+	users := make(map[uuid.UUID]*models.User)
+	for _, id := range ids {
+		if user, ok := f.cached[id]; ok {
+			users[id] = user
+		}
+	}
+	return users, nil
+}
+
+func (f *fakeUserSvcUsers) SetManyToCache(_ context.Context, users []*models.User) error {
+	for _, user := range users {
+		f.cached[user.Id] = user
+	}
+	return nil
+}
+
+func (f *fakeUserSvcUsers) User(ctx context.Context, info repositoriestransfer.GetUserInfo, tx database.Transaction) (*models.User, error) {
+	return f.users.User(ctx, info, tx)
+}
+
+func (f *fakeUserSvcUsers) DeleteFromCache(ctx context.Context, id uuid.UUID) error {
+	return f.users.DeleteFromCache(ctx, id)
+}
+
+func (f *fakeUserSvcUsers) Update(_ context.Context, info repositoriestransfer.UpdateUserInfo, _ database.Transaction) error {
+	f.updates = append(f.updates, info)
+	return nil
+}
+
+type userSvcTest struct {
+	svc   *UserService
+	users *fakeUserSvcUsers
+	txs   *fakeTxCreator
+	user  *models.User
+}
+
+func newUserSvcTest() *userSvcTest {
+	user := models.NewUserModel("user@example.com", "First", "Last", nil)
+	txs := &fakeTxCreator{}
+	users := &fakeUserSvcUsers{
+		users:  &fakeUsers{users: []*models.User{user}, txs: txs},
+		cached: map[uuid.UUID]*models.User{},
+	}
+
+	return &userSvcTest{
+		svc:   &UserService{log: testLogger(), userRep: users, txCreator: txs, batchOpts: UsersBatchOptions{Limit: 5}},
+		users: users,
+		txs:   txs,
+		user:  user,
+	}
+}
+
+func TestUpdateUserFromPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   *usersv1.UserPatch
+		paths   []string
+		columns map[string]any
+	}{
+		{
+			name:    "set fields",
+			patch:   &usersv1.UserPatch{Bio: "bio", Links: []string{"https://example.com"}, Birthday: &usersv1.BirthdayPatch{Date: "2000-01-02"}},
+			paths:   []string{"bio", "links", "birthday.date"},
+			columns: map[string]any{"bio": "bio", "links": models.ProfileLinks{"https://example.com"}, "birthday": "2000-01-02"},
+		},
+		{
+			name:    "clear fields",
+			patch:   &usersv1.UserPatch{},
+			paths:   []string{"bio", "links", "birthday.date", "cover"},
+			columns: map[string]any{"bio": "", "links": models.ProfileLinks{}, "birthday": nil, "cover": ""},
+		},
+		{
+			name:    "fields out of the mask are not updated",
+			patch:   &usersv1.UserPatch{Fname: "New", Lname: "Name", Bio: "bio"},
+			paths:   []string{"lname"},
+			columns: map[string]any{"lname": "Name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserSvcTest()
+
+			fields, err := transfer.UpdateFieldsFromPatch(tt.patch, tt.paths)
+			if err != nil {
+				t.Fatalf("UpdateFieldsFromPatch: %v", err)
+			}
+
+			if _, err := st.svc.UpdateUser(context.Background(), &transfer.UpdateUserInfo{
+				Id:           st.user.Id,
+				UpdateFields: fields,
+			}); err != nil {
+				t.Fatalf("UpdateUser: %v", err)
+			}
+
+			if len(st.users.updates) != 1 {
+				t.Fatalf("user updated %d times, want once", len(st.users.updates))
+			}
+			if columns := st.users.updates[0].UpdateInfo; !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("updated columns = %#v, want %#v", columns, tt.columns)
+			}
+			if len(st.users.users.cacheDeletes) != 1 {
+				t.Errorf("user deleted from cache %d times, want once", len(st.users.users.cacheDeletes))
+			}
+		})
+	}
+}
+
+// addUsers stores new users, the cached ones are put into the cache too.
+func (st *userSvcTest) addUsers(stored int, cached int) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, stored+cached)
+	for i := 0; i < stored+cached; i++ {
+		user := models.NewUserModel(uuid.NewString()+"@example.com", "First", "Last", nil)
+		st.users.users.users = append(st.users.users.users, user)
+		if i >= stored {
+			st.users.cached[user.Id] = user
+		}
+		ids = append(ids, user.Id)
+	}
+	return ids
+}
+
+func resultIds(res *transfer.GetUsersResult) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(res.Users))
+	for _, user := range res.Users {
+		ids = append(ids, user.Id)
+	}
+	return ids
+}
+
+func TestGetUsersMergesCacheAndDb(t *testing.T) {
+	st := newUserSvcTest()
+	stored := st.addUsers(2, 0)
+	cached := st.addUsers(0, 2)
+	missing := uuid.New()
+
+	requested := []uuid.UUID{stored[0], cached[0], missing, stored[1], cached[1]}
+	res, err := st.svc.GetUsers(context.Background(), &transfer.GetUsersInfo{Ids: requested})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+
+	if want := []uuid.UUID{stored[0], cached[0], stored[1], cached[1]}; !slices.Equal(resultIds(res), want) {
+		t.Errorf("GetUsers users = %v, want %v", resultIds(res), want)
+	}
+	if want := []uuid.UUID{missing}; !slices.Equal(res.NotFoundIds, want) {
+		t.Errorf("GetUsers not found ids = %v, want %v", res.NotFoundIds, want)
+	}
+
+	if len(st.users.queries) != 1 {
+		t.Fatalf("users read from db %d times, want once", len(st.users.queries))
+	}
+	if want := []uuid.UUID{stored[0], missing, stored[1]}; !slices.Equal(st.users.queries[0], want) {
+		t.Errorf("users read from db = %v, want the cache misses %v", st.users.queries[0], want)
+	}
+	for _, id := range stored {
+		if _, ok := st.users.cached[id]; !ok {
+			t.Errorf("user %s read from db is not cached", id)
+		}
+	}
+}
+
+func TestGetUsersDedupesIds(t *testing.T) {
+	st := newUserSvcTest()
+	stored := st.addUsers(1, 0)
+	cached := st.addUsers(0, 1)
+	missing := uuid.New()
+
+	requested := []uuid.UUID{cached[0], stored[0], missing, cached[0], missing}
+	res, err := st.svc.GetUsers(context.Background(), &transfer.GetUsersInfo{Ids: requested})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+
+	if want := []uuid.UUID{cached[0], stored[0]}; !slices.Equal(resultIds(res), want) {
+		t.Errorf("GetUsers users = %v, want %v", resultIds(res), want)
+	}
+	if want := []uuid.UUID{missing}; !slices.Equal(res.NotFoundIds, want) {
+		t.Errorf("GetUsers not found ids = %v, want %v", res.NotFoundIds, want)
+	}
+	if want := []uuid.UUID{stored[0], missing}; len(st.users.queries) != 1 || !slices.Equal(st.users.queries[0], want) {
+		t.Errorf("users read from db = %v, want once %v", st.users.queries, want)
+	}
+}
+
+func TestGetUsersAllCached(t *testing.T) {
+	st := newUserSvcTest()
+	cached := st.addUsers(0, 3)
+
+	res, err := st.svc.GetUsers(context.Background(), &transfer.GetUsersInfo{Ids: cached})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+
+	if !slices.Equal(resultIds(res), cached) || len(res.NotFoundIds) != 0 {
+		t.Errorf("GetUsers = %v, not found %v, want %v", resultIds(res), res.NotFoundIds, cached)
+	}
+	if len(st.users.queries) != 0 {
+		t.Errorf("users read from db %d times, want none", len(st.users.queries))
+	}
+}
+
+func TestGetUsersCacheFailure(t *testing.T) {
+	st := newUserSvcTest()
+	ids := st.addUsers(2, 1)
+	st.users.cacheErr = errors.New("cache is down")
+
+	res, err := st.svc.GetUsers(context.Background(), &transfer.GetUsersInfo{Ids: ids})
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+
+	if !slices.Equal(resultIds(res), ids) {
+		t.Errorf("GetUsers users = %v, want %v", resultIds(res), ids)
+	}
+	if len(st.users.queries) != 1 || !slices.Equal(st.users.queries[0], ids) {
+		t.Errorf("users read from db = %v, want all of %v", st.users.queries, ids)
+	}
+}
+
+func TestGetUsersLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     func(st *userSvcTest) []uuid.UUID
+		wantErr error
+	}{
+		{name: "at the limit", ids: func(st *userSvcTest) []uuid.UUID { return st.addUsers(5, 0) }},
+		{name: "above the limit", ids: func(st *userSvcTest) []uuid.UUID { return st.addUsers(6, 0) }, wantErr: ctxerrors.ErrBadRequest},
+		{
+			name: "duplicates count against the limit",
+			ids: func(st *userSvcTest) []uuid.UUID {
+				id := st.addUsers(1, 0)[0]
+				return []uuid.UUID{id, id, id, id, id, id}
+			},
+			wantErr: ctxerrors.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newUserSvcTest()
+
+			_, err := st.svc.GetUsers(context.Background(), &transfer.GetUsersInfo{Ids: tt.ids(st)})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("GetUsers: %v", err)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetUsers err = %v, want %v", err, tt.wantErr)
+				}
+				if len(st.users.queries) != 0 {
+					t.Errorf("users read from db over the limit")
+				}
+			}
+		})
+	}
+}
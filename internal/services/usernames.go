@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/KBcHMFollower/blog_user_service/internal/database"
+	ctxerrors "github.com/KBcHMFollower/blog_user_service/internal/domain/errors"
+	repositoriestransfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/repositories"
+	transfer "github.com/KBcHMFollower/blog_user_service/internal/domain/layers_TOs/services"
+	"github.com/KBcHMFollower/blog_user_service/internal/domain/models"
+	"github.com/KBcHMFollower/blog_user_service/internal/lib/validators"
+	"github.com/KBcHMFollower/blog_user_service/internal/logger"
+	servicesutils "github.com/KBcHMFollower/blog_user_service/internal/services/lib"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	usernameLogKey    = "username"
+	oldUsernameLogKey = "old-username"
+)
+
+const (
+	usernameSuggestionsCount = 5
+	// usernameSuggestionsTries is the number of candidates checked at once, some of them are expected to be taken.
+	usernameSuggestionsTries = 15
+	usernameSuggestionSuffix = 1000
+	usernameFallbackBase     = "user"
+)
+
+const usernameTakenReason = "username is taken"
+
+// UsernameOptions configure renames, the former username redirects to the user for RedirectTTL
+// and can't be taken by other users meanwhile.
+type UsernameOptions struct {
+	RedirectTTL time.Duration
+}
+
+func (a *UserService) GetUserByUsername(ctx context.Context, info *transfer.GetUserByUsernameInfo) (resUser *transfer.GetUserByUsernameResult, resErr error) {
+	ctx = logger.UpdateLoggerCtx(ctx, usernameLogKey, info.Username)
+
+	a.log.DebugContext(ctx, "try to get user by username")
+
+	if cacheUser := a.cachedUserByUsername(ctx, info.Username); cacheUser != nil {
+		a.log.DebugContext(ctx, "user found in cache")
+		return &transfer.GetUserByUsernameResult{
+			User: transfer.GetUserResultFromModel(cacheUser),
+		}, nil
+	}
+
+	tx, err := a.txCreator.BeginTxCtx(ctx, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t begin tx", err))
+	}
+	defer func() {
+		resErr = servicesutils.HandleErrInTransaction(resErr, tx)
+	}()
+
+	user, err := a.userRep.UserByUsername(ctx, info.Username, tx)
+	if err != nil && !errors.Is(err, ctxerrors.ErrNotFound) {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user by username from db", err))
+	}
+
+	redirected := false
+	if user == nil {
+		user, err = a.formerUsernameUser(ctx, info.Username, tx)
+		if err != nil {
+			return nil, err
+		}
+		redirected = true
+
+		a.log.DebugContext(ctx, "username is a former one, redirect to the user")
+	} else {
+		if err := a.userRep.SetToCache(ctx, user); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t set user to cache", err))
+		}
+		if err := a.userRep.SetUsernameToCache(ctx, info.Username, user.Id); err != nil {
+			return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t set username to cache", err))
+		}
+		a.log.DebugContext(ctx, "user added to cache")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t commit tx", err))
+	}
+
+	a.log.DebugContext(ctx, "get user by username successfully")
+
+	return &transfer.GetUserByUsernameResult{
+		User:       transfer.GetUserResultFromModel(user),
+		Redirected: redirected,
+	}, nil
+}
+
+// CheckUsernameAvailable reports whether the username can be taken, an unavailable one comes with suggestions
+// based on it. Usernames held by the rename history are unavailable until the redirect expires.
+func (a *UserService) CheckUsernameAvailable(ctx context.Context, info *transfer.CheckUsernameInfo) (*transfer.UsernameAvailabilityResult, error) {
+	ctx = logger.UpdateLoggerCtx(ctx, usernameLogKey, info.Username)
+
+	a.log.DebugContext(ctx, "try to check username availability")
+
+	reason := ""
+	if err := validators.CheckUsername(info.Username); err != nil {
+		reason = err.Error()
+	} else {
+		taken, err := a.takenUsernames(ctx, []string{info.Username})
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := taken[strings.ToLower(info.Username)]; ok {
+			reason = usernameTakenReason
+		}
+	}
+
+	if reason == "" {
+		a.log.DebugContext(ctx, "username is available")
+		return &transfer.UsernameAvailabilityResult{
+			Available:   true,
+			Suggestions: []string{},
+		}, nil
+	}
+
+	suggestions, err := a.suggestUsernames(ctx, info.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	a.log.DebugContext(ctx, "username is not available", "reason", reason)
+
+	return &transfer.UsernameAvailabilityResult{
+		Available:   false,
+		Reason:      reason,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// renameUsername checks the new username of the user and keeps the former one in the history,
+// a case only change is not a rename. It returns the former username to clear its cache.
+func (a *UserService) renameUsername(ctx context.Context, userId uuid.UUID, username string, tx database.Transaction) (string, error) {
+	if err := validators.CheckUsername(username); err != nil {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(err.Error(), ctxerrors.ErrBadRequest))
+	}
+
+	user, err := a.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]any{
+			repositoriestransfer.UserIdCondition: userId,
+		},
+	}, tx)
+	if err != nil {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	formerUsername := user.Username.String
+	if user.Username.Valid && strings.EqualFold(formerUsername, username) {
+		return formerUsername, nil
+	}
+
+	ctx = logger.UpdateLoggerCtx(ctx, oldUsernameLogKey, formerUsername)
+
+	owner, err := a.userRep.UserByUsername(ctx, username, tx)
+	if err != nil && !errors.Is(err, ctxerrors.ErrNotFound) {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user by username from db", err))
+	}
+	if owner != nil {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(usernameTakenReason, ctxerrors.ErrConflict))
+	}
+
+	entry, err := a.usernamesRep.Entry(ctx, username, tx)
+	if err != nil && !errors.Is(err, ctxerrors.ErrNotFound) {
+		return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get username history entry", err))
+	}
+	if entry != nil {
+		if entry.UserId != userId && !entry.IsExpired() {
+			return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap(usernameTakenReason, ctxerrors.ErrConflict))
+		}
+		if err := a.usernamesRep.Delete(ctx, username, tx); err != nil {
+			return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t delete username history entry", err))
+		}
+	}
+
+	if user.Username.Valid {
+		if err := a.usernamesRep.Create(ctx, repositoriestransfer.CreateUsernameHistoryInfo{
+			Username:  formerUsername,
+			UserId:    userId,
+			ExpiresAt: time.Now().Add(a.usernameOpts.RedirectTTL),
+		}, tx); err != nil {
+			return "", ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t create username history entry", err))
+		}
+
+		a.log.InfoContext(ctx, "former username redirects to the user")
+	}
+
+	return formerUsername, nil
+}
+
+// cachedUserByUsername returns nil when the cached user has been renamed since the username was cached.
+func (a *UserService) cachedUserByUsername(ctx context.Context, username string) *models.User {
+	id, err := a.userRep.TryGetIdByUsernameFromCache(ctx, username)
+	if err != nil {
+		a.log.DebugContext(ctx, "can`t get username from cache: ", "err", err.Error())
+		return nil
+	}
+
+	user, err := a.userRep.TryGetFromCache(ctx, id)
+	if err != nil {
+		a.log.DebugContext(ctx, "can`t get cacheUser from cache: ", "err", err.Error())
+		return nil
+	}
+	if user == nil || !strings.EqualFold(user.Username.String, username) {
+		return nil
+	}
+
+	return user
+}
+
+func (a *UserService) formerUsernameUser(ctx context.Context, username string, tx database.Transaction) (*models.User, error) {
+	entry, err := a.usernamesRep.Entry(ctx, username, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get username history entry", err))
+	}
+	if entry.IsExpired() {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("username redirect is expired", ctxerrors.ErrNotFound))
+	}
+
+	user, err := a.userRep.User(ctx, repositoriestransfer.GetUserInfo{
+		Condition: map[repositoriestransfer.UserFieldTarget]any{
+			repositoriestransfer.UserIdCondition: entry.UserId,
+		},
+	}, tx)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get user from db", err))
+	}
+
+	return user, nil
+}
+
+// takenUsernames returns the lower cased usernames of the list that are taken by users or held by the history.
+func (a *UserService) takenUsernames(ctx context.Context, usernames []string) (map[string]struct{}, error) {
+	existing, err := a.userRep.ExistingUsernames(ctx, usernames, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get existing usernames", err))
+	}
+
+	held, err := a.usernamesRep.HeldUsernames(ctx, usernames, nil)
+	if err != nil {
+		return nil, ctxerrors.WrapCtx(ctx, ctxerrors.Wrap("can`t get held usernames", err))
+	}
+
+	taken := make(map[string]struct{}, len(existing)+len(held))
+	for _, username := range append(existing, held...) {
+		taken[username] = struct{}{}
+	}
+
+	return taken, nil
+}
+
+func (a *UserService) suggestUsernames(ctx context.Context, username string) ([]string, error) {
+	base := usernameSuggestionBase(username)
+
+	candidates := make([]string, 0, usernameSuggestionsTries)
+	seen := make(map[string]struct{}, usernameSuggestionsTries)
+	for range usernameSuggestionsTries {
+		candidate := fmt.Sprintf("%s%d", base, rand.IntN(usernameSuggestionSuffix))
+		if _, ok := seen[candidate]; ok || validators.CheckUsername(candidate) != nil {
+			continue
+		}
+		seen[candidate] = struct{}{}
+		candidates = append(candidates, candidate)
+	}
+
+	taken, err := a.takenUsernames(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0, usernameSuggestionsCount)
+	for _, candidate := range candidates {
+		if len(suggestions) == usernameSuggestionsCount {
+			break
+		}
+		if _, ok := taken[strings.ToLower(candidate)]; ok {
+			continue
+		}
+		suggestions = append(suggestions, candidate)
+	}
+
+	return suggestions, nil
+}
+
+// usernameSuggestionBase strips the characters usernames can't have and leaves room for a numeric suffix.
+func usernameSuggestionBase(username string) string {
+	base := strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return -1
+	}, username)
+
+	maxLength := validators.UsernameMaxLength - len(fmt.Sprint(usernameSuggestionSuffix-1))
+	if len(base) > maxLength {
+		base = base[:maxLength]
+	}
+	if strings.Trim(base, "0123456789_") == "" {
+		base = usernameFallbackBase
+	}
+
+	return base
+}
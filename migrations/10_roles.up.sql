@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+    name TEXT PRIMARY KEY,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission),
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id UUID NOT NULL,
+    role TEXT NOT NULL,
+    granted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE
+);
+
+INSERT INTO roles (name) VALUES ('admin'), ('moderator') ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:update:any'),
+    ('admin', 'users:delete:any'),
+    ('admin', 'roles:manage'),
+    ('admin', 'accounts:unlock'),
+    ('admin', 'tokens:revoke:any'),
+    ('moderator', 'users:update:any'),
+    ('moderator', 'accounts:unlock')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id UUID PRIMARY KEY,
+    service_account TEXT NOT NULL,
+    prefix TEXT NOT NULL UNIQUE,
+    secret_hash BYTEA NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account ON api_keys(service_account);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'api_keys:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_one_time_tokens_email_purpose;
+DELETE FROM one_time_tokens WHERE user_id IS NULL;
+ALTER TABLE one_time_tokens ALTER COLUMN user_id SET NOT NULL;
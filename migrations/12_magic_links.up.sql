@@ -0,0 +1,3 @@
+-- magic links for not registered emails are bound to the email only, the account is created on redemption
+ALTER TABLE one_time_tokens ALTER COLUMN user_id DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_one_time_tokens_email_purpose ON one_time_tokens(email, purpose);
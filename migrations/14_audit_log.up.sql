@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id UUID PRIMARY KEY,
+    action TEXT NOT NULL,
+    actor_id UUID NULL,
+    target_user_id UUID NULL,
+    token_id UUID NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target_user_id ON audit_log(target_user_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:impersonate')
+ON CONFLICT DO NOTHING;
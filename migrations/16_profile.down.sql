@@ -0,0 +1,8 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS links,
+    DROP COLUMN IF EXISTS location,
+    DROP COLUMN IF EXISTS birthday,
+    DROP COLUMN IF EXISTS birthday_visibility,
+    DROP COLUMN IF EXISTS pronouns,
+    DROP COLUMN IF EXISTS cover;
@@ -0,0 +1,9 @@
+-- blogger profile, links are a json array of urls, the birthday is shown to other users only when public
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS birthday DATE NULL,
+    ADD COLUMN IF NOT EXISTS birthday_visibility TEXT NOT NULL DEFAULT 'private' CHECK (birthday_visibility IN ('public', 'private')),
+    ADD COLUMN IF NOT EXISTS pronouns TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cover TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- bumped by every profile update, clients send the version they have read to detect concurrent edits
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS user_tokens_revocations
+(
+    user_id UUID PRIMARY KEY,
+    revoked_before TIMESTAMP NOT NULL
+);
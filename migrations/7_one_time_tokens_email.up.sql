@@ -0,0 +1 @@
+ALTER TABLE one_time_tokens ADD COLUMN IF NOT EXISTS email TEXT NULL;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- a session is a refresh token family, the families alive at the migration moment are kept logged in
+INSERT INTO sessions (id, user_id, last_seen_at, created_date)
+SELECT family_id, user_id, MAX(created_date), MIN(created_date)
+FROM refresh_tokens
+WHERE revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;